@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon/operations"
+)
+
+// When and by whom an account was created
+type accountCreation struct {
+	Time   time.Time
+	Funder string
+	Hash   string
+}
+
+// Find the create_account operation that created accountID among ops
+func findAccountCreation(ops []operations.Operation, accountID string) (accountCreation, bool) {
+	var found accountCreation
+	ok := false
+	for _, op := range ops {
+		create, isCreate := op.(operations.CreateAccount)
+		if !isCreate || create.Account != accountID {
+			continue
+		}
+		if !ok || create.LedgerCloseTime.Before(found.Time) {
+			found = accountCreation{Time: create.LedgerCloseTime, Funder: create.Funder, Hash: create.TransactionHash}
+			ok = true
+		}
+	}
+	return found, ok
+}
+
+// Rough age like "2 years, 3 months" or "5 days"
+func accountAgeText(created, now time.Time) string {
+	if now.Before(created) {
+		return "just created"
+	}
+	years := now.Year() - created.Year()
+	months := int(now.Month()) - int(created.Month())
+	if now.Day() < created.Day() {
+		months--
+	}
+	if months < 0 {
+		years--
+		months += 12
+	}
+
+	var parts []string
+	if years > 0 {
+		parts = append(parts, plural(years, "year"))
+	}
+	if months > 0 {
+		parts = append(parts, plural(months, "month"))
+	}
+	if len(parts) == 0 {
+		days := int(now.Sub(created).Hours() / 24)
+		if days == 0 {
+			return "less than a day"
+		}
+		return plural(days, "day")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// The account's oldest operations, which include the one creating it
+func fetchAccountCreation(accountID string) (accountCreation, bool, error) {
+	page, err := client.Operations(horizonclient.OperationRequest{
+		ForAccount: accountID,
+		Order:      horizonclient.OrderAsc,
+		Limit:      10,
+	})
+	if err != nil {
+		return accountCreation{}, false, err
+	}
+	created, ok := findAccountCreation(page.Embedded.Records, accountID)
+	return created, ok, nil
+}
+
+func showAccountAgeDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	addressEntry := widget.NewEntry()
+	addressEntry.SetText(wallet.PublicKey)
+	resultLabel := widget.NewLabel("")
+	resultLabel.Wrapping = fyne.TextWrapBreak
+
+	lookup := widget.NewButton("Look Up", func() {
+		accountID := strings.TrimSpace(addressEntry.Text)
+		created, ok, err := fetchAccountCreation(accountID)
+		switch {
+		case err != nil:
+			resultLabel.SetText(fmt.Sprintf("error loading operations: %v", err))
+		case !ok:
+			resultLabel.SetText("Creation not found (the history may have been pruned)")
+		default:
+			resultLabel.SetText(fmt.Sprintf("Created %s (%s ago)\nFunded by %s\nTransaction %s",
+				created.Time.Local().Format("2006-01-02 15:04"), accountAgeText(created.Time, time.Now()), created.Funder, created.Hash))
+		}
+	})
+
+	content := container.NewVBox(addressEntry, lookup, resultLabel)
+	dialog.ShowCustom("Account Age", "Close", content, window)
+	lookup.OnTapped()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/protocols/horizon/operations"
+)
+
+func TestAccountAgeText(t *testing.T) {
+	created := time.Date(2021, time.March, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"clock behind", created.Add(-time.Hour), "just created"},
+		{"same hour", created.Add(time.Hour), "less than a day"},
+		{"one day", created.AddDate(0, 0, 1), "1 day"},
+		{"days before a month", time.Date(2021, time.April, 14, 10, 0, 0, 0, time.UTC), "30 days"},
+		{"one month", time.Date(2021, time.April, 15, 10, 0, 0, 0, time.UTC), "1 month"},
+		{"year and months", time.Date(2022, time.June, 20, 0, 0, 0, 0, time.UTC), "1 year, 3 months"},
+		{"not quite two years", time.Date(2023, time.March, 14, 0, 0, 0, 0, time.UTC), "1 year, 11 months"},
+		{"exact years", time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC), "3 years"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := accountAgeText(created, tt.now); got != tt.want {
+				t.Errorf("accountAgeText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAccountCreation(t *testing.T) {
+	create := func(account, funder, hash string, at time.Time) operations.CreateAccount {
+		op := operations.CreateAccount{Account: account, Funder: funder}
+		op.LedgerCloseTime, op.TransactionHash = at, hash
+		return op
+	}
+	first := time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)
+	ops := []operations.Operation{
+		operations.Payment{From: testOther, To: testWallet},
+		create(testOther, testWallet, "other", first.Add(-time.Hour)),
+		create(testWallet, testOther, "later", first.Add(time.Hour)),
+		create(testWallet, "GFUNDER", "first", first),
+	}
+
+	got, ok := findAccountCreation(ops, testWallet)
+	want := accountCreation{Time: first, Funder: "GFUNDER", Hash: "first"}
+	if !ok || got != want {
+		t.Errorf("creation = %+v, %v, want %+v", got, ok, want)
+	}
+	if _, ok := findAccountCreation(ops[:1], testWallet); ok {
+		t.Error("found a creation among payments only")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// How long a fetched account record is reused before asking Horizon again
+const accountCacheTTL = 15 * time.Second
+
+type cachedAccount struct {
+	account horizon.Account
+	fetched time.Time
+}
+
+// Account records by network and ID, so a send doesn't fetch the same
+// account several times. Never used for the sequence number of a transaction
+// being built, which must be current.
+type accountCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedAccount
+	now     func() time.Time
+}
+
+func newAccountCache(ttl time.Duration) *accountCache {
+	return &accountCache{ttl: ttl, entries: make(map[string]cachedAccount), now: time.Now}
+}
+
+var accountRecords = newAccountCache(accountCacheTTL)
+
+func accountCacheKey(network, accountID string) string {
+	return network + "/" + accountID
+}
+
+func (c *accountCache) get(network, accountID string) (horizon.Account, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[accountCacheKey(network, accountID)]
+	if !ok || c.now().Sub(entry.fetched) >= c.ttl {
+		return horizon.Account{}, false
+	}
+	return entry.account, true
+}
+
+func (c *accountCache) put(network, accountID string, account horizon.Account) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[accountCacheKey(network, accountID)] = cachedAccount{account: account, fetched: c.now()}
+}
+
+// Drop one account, such as after a payment to it arrives
+func (c *accountCache) invalidate(network, accountID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, accountCacheKey(network, accountID))
+}
+
+// Drop everything; a submitted transaction can change any account it touches
+func (c *accountCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cachedAccount)
+}
+
+// Account from the cache, or from Horizon when missing or stale. Errors
+// aren't cached, so an unfunded account is seen as soon as it's funded.
+func (c *accountCache) fetch(hc core.HorizonAPI, network, accountID string) (horizon.Account, error) {
+	if account, ok := c.get(network, accountID); ok {
+		return account, nil
+	}
+	account, err := hc.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+	if err != nil {
+		return horizon.Account{}, err
+	}
+	c.put(network, accountID, account)
+	return account, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+func TestAccountCacheFetch(t *testing.T) {
+	clock := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	cache := newAccountCache(15 * time.Second)
+	cache.now = func() time.Time { return clock }
+
+	fake := horizontest.NewFakeHorizon(horizon.Account{AccountID: testWallet, Sequence: 1})
+	sequence := func(network string) int64 {
+		t.Helper()
+		account, err := cache.fetch(fake, network, testWallet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return account.Sequence
+	}
+
+	if got := sequence("testnet"); got != 1 {
+		t.Fatalf("first fetch sequence = %d, want 1", got)
+	}
+	fake.Accounts[testWallet] = horizon.Account{AccountID: testWallet, Sequence: 2}
+
+	steps := []struct {
+		name     string
+		change   func()
+		network  string
+		expected int64
+	}{
+		{"cached within ttl", func() { clock = clock.Add(14 * time.Second) }, "testnet", 1},
+		{"other network is separate", func() {}, "public", 2},
+		{"stale after ttl", func() { clock = clock.Add(time.Second) }, "testnet", 2},
+		{"invalidate", func() {
+			fake.Accounts[testWallet] = horizon.Account{AccountID: testWallet, Sequence: 3}
+			cache.invalidate("testnet", testWallet)
+		}, "testnet", 3},
+		{"invalidate leaves other networks", func() {}, "public", 2},
+		{"clear", func() {
+			fake.Accounts[testWallet] = horizon.Account{AccountID: testWallet, Sequence: 4}
+			cache.clear()
+		}, "public", 4},
+	}
+	for _, step := range steps {
+		step.change()
+		if got := sequence(step.network); got != step.expected {
+			t.Errorf("%s: sequence = %d, want %d", step.name, got, step.expected)
+		}
+	}
+}
+
+func TestAccountCacheSkipsErrors(t *testing.T) {
+	cache := newAccountCache(time.Minute)
+	fake := horizontest.NewFakeHorizon()
+
+	if _, err := cache.fetch(fake, "testnet", testWallet); !isNotFound(err) {
+		t.Fatalf("fetch of a missing account = %v, want not found", err)
+	}
+	if _, ok := cache.get("testnet", testWallet); ok {
+		t.Error("a missing account was cached")
+	}
+
+	// Funded since the last look
+	fake.Accounts[testWallet] = horizon.Account{AccountID: testWallet}
+	if _, err := cache.fetch(fake, "testnet", testWallet); err != nil {
+		t.Errorf("fetch after funding = %v", err)
+	}
+}
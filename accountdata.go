@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Longest key or value a data entry can have, in bytes
+const maxDataEntryBytes = 64
+
+// One key-value pair stored on the account
+type dataEntry struct {
+	Key   string
+	Value []byte
+}
+
+// Entries from an account's data map, which Horizon returns base64 encoded,
+// sorted by key
+func dataEntries(data map[string]string) ([]dataEntry, error) {
+	entries := make([]dataEntry, 0, len(data))
+	for key, encoded := range data {
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for data entry %q: %v", key, err)
+		}
+		entries = append(entries, dataEntry{Key: key, Value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// Value as text when it is printable, otherwise as hex
+func dataValueText(value []byte) string {
+	if utf8.Valid(value) {
+		printable := true
+		for _, r := range string(value) {
+			if !unicode.IsPrint(r) {
+				printable = false
+				break
+			}
+		}
+		if printable {
+			return string(value)
+		}
+	}
+	return "0x" + hex.EncodeToString(value)
+}
+
+func validateDataEntry(key string, value []byte) error {
+	switch {
+	case key == "":
+		return errors.New("key is required")
+	case len(key) > maxDataEntryBytes:
+		return fmt.Errorf("key is %d bytes; it can be at most %d", len(key), maxDataEntryBytes)
+	case len(value) > maxDataEntryBytes:
+		return fmt.Errorf("value is %d bytes; it can be at most %d", len(value), maxDataEntryBytes)
+	}
+	return nil
+}
+
+// Operation setting key to value, or removing key when value is nil
+func manageDataOp(key string, value []byte) *txnbuild.ManageData {
+	return &txnbuild.ManageData{Name: key, Value: value}
+}
+
+func showAccountDataDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	account, err := activeSession().Account()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+		return
+	}
+	entries, err := dataEntries(account.Data)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	var d dialog.Dialog
+	list := container.NewVBox()
+	if len(entries) == 0 {
+		list.Add(widget.NewLabel("This account has no data entries."))
+	}
+	for _, entry := range entries {
+		entry := entry
+		remove := widget.NewButton("Remove", func() {
+			dialog.ShowConfirm("Remove Data Entry", fmt.Sprintf("Remove %q from the account?", entry.Key), func(ok bool) {
+				if !ok {
+					return
+				}
+				d.Hide()
+				submitWithFeedback([]txnbuild.Operation{manageDataOp(entry.Key, nil)}, nil, func(hash string) {
+					dialog.ShowInformation("Success", fmt.Sprintf("Data entry removed! Hash: %s", hash), window)
+				})
+			}, window)
+		})
+		label := widget.NewLabel(fmt.Sprintf("%s = %s", entry.Key, dataValueText(entry.Value)))
+		label.Wrapping = fyne.TextWrapBreak
+		list.Add(container.NewBorder(nil, nil, nil, remove, label))
+	}
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(scaled(380), scaled(240)))
+	add := widget.NewButton("Set Entry...", func() {
+		d.Hide()
+		showSetDataEntryDialog()
+	})
+	d = dialog.NewCustom("Account Data", "Close", container.NewBorder(nil, add, nil, nil, scroll), window)
+	d.Show()
+}
+
+// Add an entry, or replace the value of an existing key
+func showSetDataEntryDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder("Key (up to 64 bytes)")
+	valueEntry := widget.NewEntry()
+	valueEntry.SetPlaceHolder("Value (up to 64 bytes)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Key", keyEntry),
+		widget.NewFormItem("Value", valueEntry),
+	}
+	dialog.ShowForm("Set Data Entry", "Save", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		key, value := keyEntry.Text, []byte(valueEntry.Text)
+		if err := validateDataEntry(key, value); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		// An empty value would remove the entry instead
+		if len(value) == 0 {
+			dialog.ShowError(errors.New("value is required; use Remove to delete an entry"), window)
+			return
+		}
+		submitWithFeedback([]txnbuild.Operation{manageDataOp(key, value)}, nil, func(hash string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Data entry saved! Hash: %s", hash), window)
+		})
+	}, window)
+}
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/protocols/horizon/operations"
+)
+
+const dateLayout = "2006-01-02"
+
+// A payment-like operation as seen from the wallet account
+type activityRecord struct {
+	Time         time.Time
+	Hash         string
+	Type         string
+	Direction    string // "sent" or "received"
+	Asset        string // "XLM" or "CODE:ISSUER"
+	Amount       string
+	Counterparty string
+	Fee          string // XLM the account paid for the transaction, when known
+	Memo         string // formatted like describeMemo, empty for none
+	Cursor       string
+}
+
+// Convert a payments endpoint record into an activity record for accountID
+func activityFromOperation(op operations.Operation, accountID string) (activityRecord, bool) {
+	base := op.GetBase()
+	record := activityRecord{
+		Time:   base.LedgerCloseTime,
+		Hash:   base.TransactionHash,
+		Type:   base.Type,
+		Cursor: base.PT,
+	}
+
+	var from, to string
+	switch o := op.(type) {
+	case operations.Payment:
+		from, to, record.Amount = o.From, o.To, o.Amount
+		record.Asset = assetString(assetFromHorizon(o.Asset.Type, o.Asset.Code, o.Asset.Issuer))
+	case operations.PathPayment:
+		from, to, record.Amount = o.From, o.To, o.Amount
+		record.Asset = assetString(assetFromHorizon(o.Asset.Type, o.Asset.Code, o.Asset.Issuer))
+	case operations.PathPaymentStrictSend:
+		from, to, record.Amount = o.From, o.To, o.Amount
+		record.Asset = assetString(assetFromHorizon(o.Asset.Type, o.Asset.Code, o.Asset.Issuer))
+	case operations.CreateAccount:
+		from, to, record.Amount, record.Asset = o.Funder, o.Account, o.StartingBalance, "XLM"
+	case operations.AccountMerge:
+		from, to, record.Asset = o.Account, o.Into, "XLM"
+	default:
+		return activityRecord{}, false
+	}
+
+	if from == accountID {
+		record.Direction, record.Counterparty = "sent", to
+	} else {
+		record.Direction, record.Counterparty = "received", from
+	}
+	// Only present when the transactions were joined into the request
+	if tx := base.Transaction; tx != nil {
+		if tx.FeeAccount == accountID {
+			record.Fee = stroopsToXLM(tx.FeeCharged)
+		}
+		record.Memo = horizonMemoText(tx.MemoType, tx.Memo)
+	}
+	return record, true
+}
+
+// Memo of a Horizon transaction record in the same form as describeMemo.
+// Horizon gives hash and return memos in base64; they're shown as hex.
+func horizonMemoText(memoType, memo string) string {
+	switch memoType {
+	case "", "none":
+		return ""
+	case "text":
+		return fmt.Sprintf("text %q", memo)
+	case "hash", "return":
+		raw, err := base64.StdEncoding.DecodeString(memo)
+		if err != nil {
+			return memoType + " " + memo
+		}
+		return fmt.Sprintf("%s %x", memoType, raw)
+	}
+	return memoType + " " + memo
+}
+
+// Which records an export keeps: those within [From, To) involving Asset.
+// Zero times leave that end open; an empty asset matches everything, and a
+// bare code matches any issuer.
+type activityFilter struct {
+	From  time.Time
+	To    time.Time
+	Asset string
+}
+
+func (f activityFilter) keep(record activityRecord) bool {
+	if !f.From.IsZero() && record.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !record.Time.Before(f.To) {
+		return false
+	}
+	asset := strings.TrimSpace(f.Asset)
+	return asset == "" || activityMatchesAsset(record, asset)
+}
+
+// Records come newest first, so one older than From ends the export
+func (f activityFilter) past(record activityRecord) bool {
+	return !f.From.IsZero() && record.Time.Before(f.From)
+}
+
+func activityMatchesAsset(record activityRecord, asset string) bool {
+	if strings.EqualFold(record.Asset, asset) {
+		return true
+	}
+	code, _, _ := strings.Cut(record.Asset, ":")
+	return !strings.Contains(asset, ":") && strings.EqualFold(code, asset)
+}
+
+// One page of activity, newest first, older than cursor (empty for the newest),
+// along with the cursor to continue after it. A page that doesn't move the
+// cursor forward ends the history.
+type activityFetcher func(cursor string) ([]activityRecord, string, error)
+
+// Largest page Horizon serves
+const exportPageSize = 200
+
+func sessionActivityFetcher(s walletSession) activityFetcher {
+	return func(cursor string) ([]activityRecord, string, error) {
+		return s.Payments(cursor, exportPageSize)
+	}
+}
+
+// Stream activity to w a page at a time, starting after cursor, until the
+// history runs out, passes filter.From or stop returns true. Each page is
+// flushed before moving on and reported to progress with the cursor and last
+// written hash after it, so an interrupted export can resume from there.
+// Returns that cursor. A transaction's fee is written on its first row only
+// so totals aren't counted once per operation; lastHash carries that across a
+// resume.
+func exportActivityPages(fetch activityFetcher, w *csv.Writer, cursor, lastHash string, filter activityFilter, progress func(written int, cursor, lastHash string), stop func() bool) (string, error) {
+	written := 0
+	for {
+		if stop != nil && stop() {
+			return cursor, errExportStopped
+		}
+
+		records, next, err := fetch(cursor)
+		if err != nil {
+			return cursor, err
+		}
+		if len(records) == 0 && next == cursor {
+			return cursor, nil
+		}
+
+		done := false
+		for _, record := range records {
+			if filter.past(record) {
+				done = true
+				break
+			}
+			if !filter.keep(record) {
+				continue
+			}
+			if record.Hash == lastHash {
+				record.Fee = ""
+			}
+			lastHash = record.Hash
+			if err := w.Write(activityCSVRow(record)); err != nil {
+				return cursor, err
+			}
+			written++
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return cursor, err
+		}
+
+		cursor = next
+		if progress != nil {
+			progress(written, cursor, lastHash)
+		}
+		if done {
+			return cursor, nil
+		}
+	}
+}
+
+// First and last few characters of a long address
+func shortAddress(address string) string {
+	if len(address) <= 12 {
+		return address
+	}
+	return address[:5] + "…" + address[len(address)-5:]
+}
+
+// One line summary such as "Sent 10 XLM to GABCD…WXYZ"
+func activitySummary(record activityRecord) string {
+	amount := record.Amount
+	if amount == "" {
+		amount = "all"
+	}
+	if record.Direction == "sent" {
+		return fmt.Sprintf("Sent %s %s to %s", amount, assetLabel(record.Asset), shortAddress(record.Counterparty))
+	}
+	return fmt.Sprintf("Received %s %s from %s", amount, assetLabel(record.Asset), shortAddress(record.Counterparty))
+}
+
+// Summary, time and memo, and transaction hash of a record on three lines
+func historyItem(record activityRecord) string {
+	when := record.Time.Local().Format("2006-01-02 15:04")
+	if record.Memo != "" {
+		when += " · Memo " + record.Memo
+	}
+	return fmt.Sprintf("%s\n%s\nHash: %s", activitySummary(record), when, record.Hash)
+}
+
+func historyText(records []activityRecord) string {
+	items := make([]string, 0, len(records))
+	for _, record := range records {
+		items = append(items, historyItem(record))
+	}
+	return strings.Join(items, "\n\n")
+}
+
+var errExportStopped = errors.New("export stopped")
+
+var activityCSVHeader = []string{"date", "hash", "type", "direction", "asset", "amount", "counterparty", "fee", "memo"}
+
+func activityCSVRow(record activityRecord) []string {
+	return []string{
+		record.Time.UTC().Format(time.RFC3339),
+		record.Hash,
+		record.Type,
+		record.Direction,
+		record.Asset,
+		record.Amount,
+		record.Counterparty,
+		record.Fee,
+		record.Memo,
+	}
+}
+
+// Parse an optional YYYY-MM-DD date; endOfDay moves it to the start of the next day
+func parseDate(text string, endOfDay bool) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(dateLayout, text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dates must look like %s", dateLayout)
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+// Where an interrupted export left off, so it can be resumed. Offset is the
+// file size at that point; anything after it is from a page that was never
+// recorded and is cut off before resuming.
+type exportResume struct {
+	Account  string `json:"account"`
+	Network  string `json:"network"`
+	Path     string `json:"path"`
+	Cursor   string `json:"cursor"`
+	LastHash string `json:"last_hash,omitempty"`
+	Offset   int64  `json:"offset"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Asset    string `json:"asset,omitempty"`
+	Written  int    `json:"written"`
+}
+
+// Why an export can't be resumed by account on network, or nil if it can
+func (r exportResume) mismatch(account, network string) error {
+	if r.Account != account || r.Network != network {
+		return fmt.Errorf("the unfinished export is of %s on %s, not %s on %s",
+			shortAddress(r.Account), r.Network, shortAddress(account), network)
+	}
+	return nil
+}
+
+func (r exportResume) filter() (activityFilter, error) {
+	from, err := parseDate(r.From, false)
+	if err != nil {
+		return activityFilter{}, err
+	}
+	to, err := parseDate(r.To, true)
+	if err != nil {
+		return activityFilter{}, err
+	}
+	return activityFilter{From: from, To: to, Asset: r.Asset}, nil
+}
+
+func showExportActivityDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if resume := settings.ExportResume; resume != nil {
+		if err := resume.mismatch(wallet.PublicKey, wallet.Network); err != nil {
+			message := fmt.Sprintf("An export to %s stopped after %d records, but %v.\nSwitch to that account to resume it, or start a new export.",
+				resume.Path, resume.Written, err)
+			label := widget.NewLabel(message)
+			label.Wrapping = fyne.TextWrapWord
+			dialog.ShowCustomConfirm("Resume Export", "Start Over", "Cancel", label, func(ok bool) {
+				if !ok {
+					return
+				}
+				settings.ExportResume = nil
+				if err := saveSettings(); err != nil {
+					dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+				}
+				showExportActivityForm()
+			}, window)
+			return
+		}
+
+		message := fmt.Sprintf("An export to %s stopped after %d records.\nResume it?", resume.Path, resume.Written)
+		dialog.ShowCustomConfirm("Resume Export", "Resume", "Start Over", widget.NewLabel(message), func(ok bool) {
+			if ok {
+				runActivityExport(*resume, false)
+				return
+			}
+			settings.ExportResume = nil
+			if err := saveSettings(); err != nil {
+				dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+			}
+			showExportActivityForm()
+		}, window)
+		return
+	}
+	showExportActivityForm()
+}
+
+func showExportActivityForm() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("YYYY-MM-DD (optional)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("YYYY-MM-DD (optional)")
+	assetEntry := widget.NewEntry()
+	assetEntry.SetPlaceHolder("e.g. USDC or XLM (optional)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("From", fromEntry),
+		widget.NewFormItem("To", toEntry),
+		widget.NewFormItem("Asset", assetEntry),
+	}
+
+	dialog.ShowForm("Export Activity", "Export", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		resume := exportResume{
+			Account: wallet.PublicKey,
+			Network: wallet.Network,
+			From:    strings.TrimSpace(fromEntry.Text),
+			To:      strings.TrimSpace(toEntry.Text),
+			Asset:   strings.TrimSpace(assetEntry.Text),
+		}
+		if _, err := resume.filter(); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			// Reopened as a plain file below so a resumed export can append to it
+			writer.Close()
+			if writer.URI().Scheme() != "file" {
+				dialog.ShowError(fmt.Errorf("exports can only be written to local files"), window)
+				return
+			}
+			resume.Path = writer.URI().Path()
+			runActivityExport(resume, true)
+		}, window)
+		save.SetFileName("stellar_activity.csv")
+		save.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+		save.Show()
+	}, window)
+}
+
+// Progress of the export running in the background. The export goroutine
+// only updates this; it is copied into the settings and saved from the UI,
+// when the export is stopped, finishes or the window closes.
+var (
+	exportMu     sync.Mutex
+	exportState  *exportResume
+	exportFinish bool
+)
+
+func setExportProgress(resume exportResume, finished bool) {
+	exportMu.Lock()
+	exportState, exportFinish = &resume, finished
+	exportMu.Unlock()
+}
+
+// Save the running export's progress into the settings, clearing the resume
+// point once it has finished. Call from the UI only.
+func recordExportProgress() {
+	exportMu.Lock()
+	state, finished := exportState, exportFinish
+	if finished {
+		exportState = nil
+	}
+	exportMu.Unlock()
+	if state == nil {
+		return
+	}
+
+	if finished {
+		settings.ExportResume = nil
+	} else {
+		resume := *state
+		settings.ExportResume = &resume
+	}
+	if err := saveSettings(); err != nil {
+		log.Println(err)
+	}
+}
+
+// Open the export file for writing from resume's checkpoint, cutting off
+// anything written after it
+func openExportFile(resume exportResume, fresh bool) (*os.File, error) {
+	if fresh {
+		return os.OpenFile(resume.Path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	}
+
+	info, err := os.Stat(resume.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < resume.Offset {
+		return nil, fmt.Errorf("%s is shorter than when the export stopped; start the export over", resume.Path)
+	}
+	if err := os.Truncate(resume.Path, resume.Offset); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(resume.Path, os.O_WRONLY|os.O_APPEND, 0644)
+}
+
+// Export in the background with progress, recording the cursor after every
+// page so the export can pick up where it stopped
+func runActivityExport(resume exportResume, fresh bool) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if err := resume.mismatch(wallet.PublicKey, wallet.Network); err != nil {
+		dialog.ShowError(fmt.Errorf("can't resume export: %v", err), window)
+		return
+	}
+	filter, err := resume.filter()
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	file, err := openExportFile(resume, fresh)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error opening export file: %v", err), window)
+		return
+	}
+
+	writer := csv.NewWriter(file)
+	if fresh {
+		writer.Write(activityCSVHeader)
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			file.Close()
+			dialog.ShowError(fmt.Errorf("error writing CSV: %v", err), window)
+			return
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			dialog.ShowError(fmt.Errorf("error writing CSV: %v", err), window)
+			return
+		}
+		resume.Offset = info.Size()
+	}
+
+	// Checkpoint before starting so a crash mid-export can still be resumed
+	setExportProgress(resume, false)
+	recordExportProgress()
+
+	status := widget.NewLabel(fmt.Sprintf("Exported %d records...", resume.Written))
+	var stopped, done atomic.Bool
+	progress := dialog.NewCustom("Exporting Activity", "Stop", container.NewVBox(widget.NewProgressBarInfinite(), status), window)
+	progress.SetOnClosed(func() {
+		stopped.Store(true)
+		if !done.Load() {
+			// Pressed Stop
+			recordExportProgress()
+		}
+	})
+	progress.Show()
+
+	session := activeSession()
+	go func() {
+		defer file.Close()
+		start := resume.Written
+		_, err := exportActivityPages(sessionActivityFetcher(session), writer, resume.Cursor, resume.LastHash, filter,
+			func(written int, cursor, lastHash string) {
+				info, err := file.Stat()
+				if err != nil {
+					return
+				}
+				resume.Written = start + written
+				resume.Cursor, resume.LastHash, resume.Offset = cursor, lastHash, info.Size()
+				setExportProgress(resume, false)
+				status.SetText(fmt.Sprintf("Exported %d records...", resume.Written))
+			},
+			stopped.Load)
+
+		if err == errExportStopped {
+			dialog.ShowInformation("Export Paused", fmt.Sprintf("Stopped after %d records. Export again to resume.", resume.Written), window)
+			return
+		}
+		done.Store(true)
+		progress.Hide()
+
+		var result dialog.Dialog
+		if err != nil {
+			result = dialog.NewError(fmt.Errorf("export interrupted after %d records, export again to resume: %v", resume.Written, err), window)
+		} else {
+			setExportProgress(resume, true)
+			result = dialog.NewInformation("Success", fmt.Sprintf("Exported %d records.", resume.Written), window)
+		}
+		result.SetOnClosed(recordExportProgress)
+		result.Show()
+	}()
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Pages of records served newest first, with cursors "1", "2", ... between them
+func pagedFetcher(pages [][]activityRecord) activityFetcher {
+	return func(cursor string) ([]activityRecord, string, error) {
+		i := 0
+		if cursor != "" {
+			i, _ = strconv.Atoi(cursor)
+		}
+		if i >= len(pages) {
+			return nil, cursor, nil
+		}
+		return pages[i], strconv.Itoa(i + 1), nil
+	}
+}
+
+func TestExportActivityPagesResume(t *testing.T) {
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	pages := [][]activityRecord{
+		{{Time: at, Hash: "a", Fee: "0.0000100"}, {Time: at, Hash: "b", Fee: "0.0000100"}},
+		// Second operation of transaction b, on the next page
+		{{Time: at, Hash: "b", Fee: "0.0000100"}, {Time: at, Hash: "c", Fee: "0.0000100"}},
+	}
+
+	tests := []struct {
+		name     string
+		cursor   string
+		lastHash string
+		fees     []string
+	}{
+		{"from the start", "", "", []string{"0.0000100", "0.0000100", "", "0.0000100"}},
+		{"resumed after page one", "1", "b", []string{"", "0.0000100"}},
+		{"resumed without the hash", "1", "", []string{"0.0000100", "0.0000100"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var checkpoints []string
+			cursor, err := exportActivityPages(pagedFetcher(pages), csv.NewWriter(&buf), tt.cursor, tt.lastHash, activityFilter{},
+				func(written int, cursor, lastHash string) {
+					checkpoints = append(checkpoints, cursor+"/"+lastHash)
+				}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cursor != "2" {
+				t.Errorf("final cursor = %q, want 2", cursor)
+			}
+			if checkpoints[len(checkpoints)-1] != "2/c" {
+				t.Errorf("last checkpoint = %q, want 2/c", checkpoints[len(checkpoints)-1])
+			}
+
+			rows, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != len(tt.fees) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.fees))
+			}
+			for i, row := range rows {
+				if fee := row[7]; fee != tt.fees[i] {
+					t.Errorf("row %d fee = %q, want %q", i, fee, tt.fees[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExportResumeMismatch(t *testing.T) {
+	resume := exportResume{Account: testWallet, Network: "testnet"}
+
+	tests := []struct {
+		name    string
+		account string
+		network string
+		ok      bool
+	}{
+		{"same", testWallet, "testnet", true},
+		{"other account", testOther, "testnet", false},
+		{"other network", testWallet, "public", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := resume.mismatch(tt.account, tt.network); (err == nil) != tt.ok {
+				t.Errorf("mismatch() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestOpenExportFileCutsUnrecordedRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.csv")
+	if err := os.WriteFile(path, []byte("header\nrow1\npartial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := openExportFile(exportResume{Path: path, Offset: int64(len("header\nrow1\n"))}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("row2\n")
+	file.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "header\nrow1\nrow2\n" {
+		t.Errorf("file = %q", data)
+	}
+
+	if _, err := openExportFile(exportResume{Path: path, Offset: 1000}, false); err == nil {
+		t.Error("resumed into a file shorter than the checkpoint")
+	}
+}
+
+func TestActivityFilterKeep(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 12, 0, 0, 0, time.UTC) }
+	usd := "USD:" + testOther
+	xlm := activityRecord{Time: day(10), Asset: "XLM"}
+	dollars := activityRecord{Time: day(10), Asset: usd}
+
+	tests := []struct {
+		name   string
+		filter activityFilter
+		record activityRecord
+		want   bool
+	}{
+		{"no filter", activityFilter{}, xlm, true},
+		{"within range", activityFilter{From: day(1), To: day(20)}, xlm, true},
+		{"at from", activityFilter{From: day(10)}, xlm, true},
+		{"before from", activityFilter{From: day(11)}, xlm, false},
+		{"at to", activityFilter{To: day(10)}, xlm, false},
+		{"after to", activityFilter{To: day(5)}, xlm, false},
+		{"same asset", activityFilter{Asset: "XLM"}, xlm, true},
+		{"asset case and spacing", activityFilter{Asset: " xlm "}, xlm, true},
+		{"other asset", activityFilter{Asset: "XLM"}, dollars, false},
+		{"bare code matches any issuer", activityFilter{Asset: "usd"}, dollars, true},
+		{"full asset", activityFilter{Asset: usd}, dollars, true},
+		{"other issuer", activityFilter{Asset: "USD:" + testWallet}, dollars, false},
+		{"code is not a prefix match", activityFilter{Asset: "US"}, dollars, false},
+		{"asset matches but out of range", activityFilter{From: day(11), Asset: "USD"}, dollars, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.keep(tt.record); got != tt.want {
+				t.Errorf("keep = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
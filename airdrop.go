@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// One airdrop recipient; Amount is empty to use the shared amount
+type airdropRow struct {
+	Destination string
+	Amount      string
+}
+
+// Read "destination[,amount]" rows, skipping blank lines and a header row
+func parseAirdropCSV(r io.Reader) ([]airdropRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV: %v", err)
+	}
+
+	var rows []airdropRow
+	for i, record := range records {
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		destination := strings.TrimSpace(record[0])
+		if !strkey.IsValidEd25519PublicKey(destination) {
+			if i == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: invalid destination %q", i+1, destination)
+		}
+		row := airdropRow{Destination: destination}
+		if len(record) > 1 {
+			row.Amount = strings.TrimSpace(record[1])
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no recipients found")
+	}
+	return rows, nil
+}
+
+// One CreateClaimableBalance per row, each claimable only by its recipient
+func airdropOps(rows []airdropRow, asset txnbuild.Asset, sharedAmount string) ([]txnbuild.Operation, error) {
+	ops := make([]txnbuild.Operation, 0, len(rows))
+	for i, row := range rows {
+		amt := row.Amount
+		if amt == "" {
+			amt = strings.TrimSpace(sharedAmount)
+		}
+		if stroops, err := amount.ParseInt64(amt); err != nil || stroops <= 0 {
+			return nil, fmt.Errorf("row %d (%s): invalid amount %q", i+1, row.Destination, amt)
+		}
+		ops = append(ops, &txnbuild.CreateClaimableBalance{
+			Destinations: []txnbuild.Claimant{txnbuild.NewClaimant(row.Destination, nil)},
+			Asset:        asset,
+			Amount:       amt,
+		})
+	}
+	return ops, nil
+}
+
+// "destination amount" lines for the claimable balances in batches
+func airdropRecipients(batches [][]txnbuild.Operation) []string {
+	var lines []string
+	for _, batch := range batches {
+		for _, op := range batch {
+			if create, ok := op.(*txnbuild.CreateClaimableBalance); ok && len(create.Destinations) > 0 {
+				lines = append(lines, create.Destinations[0].Destination+" "+create.Amount)
+			}
+		}
+	}
+	return lines
+}
+
+// Balance IDs created by a transaction, in operation order, from its result XDR
+func createdBalanceIDs(resultXDR string) ([]string, error) {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXDR, &result); err != nil {
+		return nil, fmt.Errorf("invalid transaction result: %v", err)
+	}
+	results, ok := result.OperationResults()
+	if !ok {
+		return nil, fmt.Errorf("transaction has no operation results")
+	}
+
+	var ids []string
+	for _, res := range results {
+		if res.Tr == nil {
+			continue
+		}
+		created, ok := res.Tr.GetCreateClaimableBalanceResult()
+		if !ok {
+			continue
+		}
+		balanceID, ok := created.GetBalanceId()
+		if !ok {
+			continue
+		}
+		id, err := xdr.MarshalHex(balanceID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func showAirdropDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	assetEntry := widget.NewEntry()
+	assetEntry.SetText("XLM")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Per recipient, unless the CSV has amounts")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Asset", assetEntry),
+		widget.NewFormItem("Amount", amountEntry),
+	}
+	dialog.ShowForm("Airdrop", "Choose CSV", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		asset, err := parseAsset(assetEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		sharedAmount := amountEntry.Text
+
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			rows, err := parseAirdropCSV(reader)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			ops, err := airdropOps(rows, asset, sharedAmount)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			confirmAirdrop(ops, asset)
+		}, window)
+		open.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+		open.Show()
+	}, window)
+}
+
+func confirmAirdrop(ops []txnbuild.Operation, asset txnbuild.Asset) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	var total int64
+	for _, op := range ops {
+		stroops, _ := amount.ParseInt64(op.(*txnbuild.CreateClaimableBalance).Amount)
+		total += stroops
+	}
+	batches := chunkOperations(ops, maxOpsPerTx)
+	message := fmt.Sprintf("Create %d claimable balances totalling %s %s in %d transaction(s)?\n\nEach balance also locks %s XLM of reserve until claimed.",
+		len(ops), amount.StringFromInt64(total), assetCode(asset), len(batches), stroopsToXLM(baseReserve))
+
+	dialog.ShowConfirm("Confirm Airdrop", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		submitBatches(batches, nil, func(hashes []string) {
+			var ids []string
+			for _, hash := range hashes {
+				tx, err := client.TransactionDetail(hash)
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("airdrop sent, but loading transaction %s failed: %v", hash, err), window)
+					return
+				}
+				created, err := createdBalanceIDs(tx.ResultXdr)
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("airdrop sent, but reading balance IDs failed: %v", err), window)
+					return
+				}
+				ids = append(ids, created...)
+			}
+			showCreatedBalances(ids)
+		}, func(hashes []string, failed int) {
+			paid, unpaid := airdropRecipients(batches[:failed]), airdropRecipients(batches[failed:])
+			remaining := fmt.Sprintf("Already paid (%d):\n%s\n\nNot paid (%d):\n%s",
+				len(paid), strings.Join(paid, "\n"), len(unpaid), strings.Join(unpaid, "\n"))
+			showPartialBatches(hashes, len(batches), remaining)
+		})
+	}, window)
+}
+
+func showCreatedBalances(ids []string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	text := widget.NewMultiLineEntry()
+	text.SetText(strings.Join(ids, "\n"))
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+	copyButton := widget.NewButton("Copy", func() {
+		window.Clipboard().SetContent(strings.Join(ids, "\n"))
+	})
+
+	scroll := container.NewScroll(text)
+	scroll.SetMinSize(fyne.NewSize(scaled(320), scaled(240)))
+	content := container.NewBorder(widget.NewLabel(fmt.Sprintf("Created %d claimable balances:", len(ids))), copyButton, nil, nil, scroll)
+	dialog.ShowCustom("Airdrop Complete", "Close", content, window)
+}
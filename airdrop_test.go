@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestParseAirdropCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []airdropRow
+		wantErr string
+	}{
+		{"header, blanks and amounts", "destination,amount\n" + testWallet + ", 5\n\n" + testOther + "\n",
+			[]airdropRow{{Destination: testWallet, Amount: "5"}, {Destination: testOther}}, ""},
+		{"no header", " " + testOther + " ,1.5", []airdropRow{{Destination: testOther, Amount: "1.5"}}, ""},
+		{"bad destination", testWallet + "\nGNOTANADDRESS,1", nil, `line 2: invalid destination "GNOTANADDRESS"`},
+		{"only a header", "destination,amount\n", nil, "no recipients"},
+		{"empty", "", nil, "no recipients"},
+		{"unterminated quote", "\"" + testWallet, nil, "error reading CSV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseAirdropCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows = %+v, want %+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestAirdropOps(t *testing.T) {
+	rows := []airdropRow{{Destination: testWallet, Amount: "5"}, {Destination: testOther}}
+	ops, err := airdropOps(rows, txnbuild.NativeAsset{}, " 2 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := airdropRecipients([][]txnbuild.Operation{ops}); !reflect.DeepEqual(got, []string{testWallet + " 5", testOther + " 2"}) {
+		t.Errorf("recipients = %q", got)
+	}
+
+	for _, shared := range []string{"", "0", "-1", "abc"} {
+		if _, err := airdropOps(rows, txnbuild.NativeAsset{}, shared); err == nil || !strings.Contains(err.Error(), "row 2") {
+			t.Errorf("shared amount %q: error = %v, want row 2 rejected", shared, err)
+		}
+	}
+}
+
+func TestAirdropBatches(t *testing.T) {
+	var csv strings.Builder
+	for i := 0; i < 250; i++ {
+		fmt.Fprintf(&csv, "%s,%d\n", testOther, i+1)
+	}
+	rows, err := parseAirdropCSV(strings.NewReader(csv.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := airdropOps(rows, txnbuild.NativeAsset{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := chunkOperations(ops, maxOpsPerTx)
+	if len(batches) != 3 || len(batches[2]) != 50 {
+		t.Fatalf("%d batches, want 100+100+50", len(batches))
+	}
+
+	// The second batch failed: the first was paid, the rest were not
+	paid, unpaid := airdropRecipients(batches[:1]), airdropRecipients(batches[1:])
+	if len(paid) != 100 || len(unpaid) != 150 {
+		t.Fatalf("paid %d, unpaid %d, want 100 and 150", len(paid), len(unpaid))
+	}
+	if paid[99] != testOther+" 100" || unpaid[0] != testOther+" 101" {
+		t.Errorf("split at %q / %q, want between rows 100 and 101", paid[99], unpaid[0])
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// Balances seen on the previous refresh, keyed by asset
+var lastBalances map[string]string
+
+// Assets whose balance went from at or above its threshold to below it
+func crossedBelowThreshold(previous, current, thresholds map[string]string) []string {
+	var crossed []string
+	for asset, threshold := range thresholds {
+		limit, err := amount.ParseInt64(threshold)
+		if err != nil {
+			continue
+		}
+		before, ok := previous[asset]
+		if !ok {
+			continue
+		}
+		after, ok := current[asset]
+		if !ok {
+			continue
+		}
+		oldAmount, err1 := amount.ParseInt64(before)
+		newAmount, err2 := amount.ParseInt64(after)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if oldAmount >= limit && newAmount < limit {
+			crossed = append(crossed, asset)
+		}
+	}
+	sort.Strings(crossed)
+	return crossed
+}
+
+// Thresholds configured for the wallet account
+func accountThresholds() map[string]string {
+	return settings.Thresholds[wallet.PublicKey]
+}
+
+// Compare a fresh balance snapshot with the last one and warn about any drop below a threshold
+func checkBalanceAlerts(account horizon.Account) {
+	current := core.Balances(account)
+	previous := lastBalances
+	lastBalances = current
+
+	thresholds := accountThresholds()
+	crossed := crossedBelowThreshold(previous, current, thresholds)
+	if len(crossed) == 0 {
+		return
+	}
+
+	var lines []string
+	for _, asset := range crossed {
+		lines = append(lines, fmt.Sprintf("%s balance %s is below %s", assetLabel(asset), current[asset], thresholds[asset]))
+	}
+	message := strings.Join(lines, "\n")
+
+	app := fyne.CurrentApp()
+	app.SendNotification(fyne.NewNotification("Low Balance", message))
+	if windows := app.Driver().AllWindows(); len(windows) > 0 {
+		dialog.ShowInformation("Low Balance", message, windows[0])
+	}
+}
+
+// Short display form of an "XLM" or "CODE:ISSUER" key
+func assetLabel(asset string) string {
+	code, _, _ := strings.Cut(asset, ":")
+	return code
+}
+
+func thresholdsText(thresholds map[string]string) string {
+	if len(thresholds) == 0 {
+		return "No alerts set"
+	}
+	var lines []string
+	for asset, limit := range thresholds {
+		lines = append(lines, fmt.Sprintf("%s below %s", assetLabel(asset), limit))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func showBalanceAlertsDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	assetEntry := widget.NewEntry()
+	assetEntry.SetText("XLM")
+	limitEntry := widget.NewEntry()
+	limitEntry.SetPlaceHolder("Threshold (empty to remove)")
+	currentLabel := widget.NewLabel(thresholdsText(accountThresholds()))
+
+	form := container.NewVBox(
+		currentLabel,
+		widget.NewForm(
+			widget.NewFormItem("Asset", assetEntry),
+			widget.NewFormItem("Alert Below", limitEntry),
+		),
+	)
+
+	dialog.ShowCustomConfirm("Balance Alerts", "Save", "Cancel", form, func(save bool) {
+		if !save {
+			return
+		}
+
+		asset, err := parseAsset(assetEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		key := assetString(asset)
+
+		if settings.Thresholds == nil {
+			settings.Thresholds = make(map[string]map[string]string)
+		}
+		thresholds := settings.Thresholds[wallet.PublicKey]
+		if thresholds == nil {
+			thresholds = make(map[string]string)
+			settings.Thresholds[wallet.PublicKey] = thresholds
+		}
+
+		limit := strings.TrimSpace(limitEntry.Text)
+		if limit == "" {
+			delete(thresholds, key)
+		} else {
+			if _, err := amount.ParseInt64(limit); err != nil {
+				dialog.ShowError(fmt.Errorf("invalid threshold: %v", err), window)
+				return
+			}
+			thresholds[key] = limit
+		}
+
+		if err := saveSettings(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+		}
+	}, window)
+}
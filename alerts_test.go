@@ -0,0 +1,37 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCrossedBelowThreshold(t *testing.T) {
+	usd := "USD:" + testOther
+	thresholds := map[string]string{"XLM": "10", usd: "5"}
+	tests := []struct {
+		name              string
+		previous, current map[string]string
+		want              []string
+	}{
+		{"above to below", map[string]string{"XLM": "12"}, map[string]string{"XLM": "9.9999999"}, []string{"XLM"}},
+		{"exactly at to below", map[string]string{"XLM": "10"}, map[string]string{"XLM": "9"}, []string{"XLM"}},
+		{"below to below", map[string]string{"XLM": "9"}, map[string]string{"XLM": "8"}, nil},
+		{"above to exactly at", map[string]string{"XLM": "12"}, map[string]string{"XLM": "10"}, nil},
+		{"below to above", map[string]string{"XLM": "8"}, map[string]string{"XLM": "20"}, nil},
+		{"first refresh", nil, map[string]string{"XLM": "1"}, nil},
+		{"trustline removed", map[string]string{usd: "6"}, map[string]string{}, nil},
+		{"no threshold for asset", map[string]string{"EUR:" + testOther: "100"}, map[string]string{"EUR:" + testOther: "0"}, nil},
+		{"both assets, sorted", map[string]string{"XLM": "50", usd: "5"}, map[string]string{"XLM": "1", usd: "4"}, []string{usd, "XLM"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := crossedBelowThreshold(tt.previous, tt.current, thresholds); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("crossed = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := crossedBelowThreshold(map[string]string{"XLM": "12"}, map[string]string{"XLM": "1"}, map[string]string{"XLM": "lots"}); got != nil {
+		t.Errorf("unparseable threshold fired for %q", got)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Parse "XLM" or "CODE:ISSUER" into an asset
+func parseAsset(s string) (txnbuild.Asset, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "XLM") || strings.EqualFold(s, "native") {
+		return txnbuild.NativeAsset{}, nil
+	}
+
+	code, issuer, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, fmt.Errorf("asset must be XLM or CODE:ISSUER")
+	}
+	if len(code) == 0 || len(code) > 12 {
+		return nil, fmt.Errorf("asset code must be 1-12 characters")
+	}
+	for _, r := range code {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return nil, fmt.Errorf("asset code must be alphanumeric")
+		}
+	}
+	if !strkey.IsValidEd25519PublicKey(issuer) {
+		return nil, fmt.Errorf("invalid asset issuer %q", issuer)
+	}
+	return txnbuild.CreditAsset{Code: code, Issuer: issuer}, nil
+}
+
+// Canonical "XLM" or "CODE:ISSUER" form of an asset
+func assetString(asset txnbuild.BasicAsset) string {
+	if asset.IsNative() {
+		return "XLM"
+	}
+	return asset.GetCode() + ":" + asset.GetIssuer()
+}
+
+// Asset from the type/code/issuer triple Horizon uses in its records
+func assetFromHorizon(assetType, code, issuer string) txnbuild.Asset {
+	if assetType == "native" {
+		return txnbuild.NativeAsset{}
+	}
+	return txnbuild.CreditAsset{Code: code, Issuer: issuer}
+}
+
+// Asset type, code and issuer as Horizon request parameters
+func horizonAssetParams(asset txnbuild.Asset) (horizonclient.AssetType, string, string) {
+	if asset.IsNative() {
+		return horizonclient.AssetTypeNative, "", ""
+	}
+	if len(asset.GetCode()) > 4 {
+		return horizonclient.AssetType12, asset.GetCode(), asset.GetIssuer()
+	}
+	return horizonclient.AssetType4, asset.GetCode(), asset.GetIssuer()
+}
+
+// Asset in the form Horizon expects in asset list parameters
+func horizonAssetList(asset txnbuild.Asset) string {
+	if asset.IsNative() {
+		return "native"
+	}
+	return assetString(asset)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const defaultAutoLock = 10 * time.Minute
+
+var (
+	lockMu    sync.Mutex
+	lockTimer *time.Timer
+)
+
+// Inactivity before the wallet locks itself; zero means never
+func autoLockTimeout() time.Duration {
+	switch {
+	case settings.AutoLockMinutes < 0:
+		return 0
+	case settings.AutoLockMinutes == 0:
+		return defaultAutoLock
+	}
+	return time.Duration(settings.AutoLockMinutes) * time.Minute
+}
+
+// Start, or restart, the inactivity countdown for window
+func startAutoLock(window fyne.Window) {
+	lockMu.Lock()
+	defer lockMu.Unlock()
+	if lockTimer != nil {
+		lockTimer.Stop()
+		lockTimer = nil
+	}
+	timeout := autoLockTimeout()
+	if timeout == 0 {
+		return
+	}
+	lockTimer = time.AfterFunc(timeout, func() { lockWallet(window) })
+}
+
+// Note user activity, putting off the auto-lock
+func touchActivity() {
+	lockMu.Lock()
+	defer lockMu.Unlock()
+	if lockTimer != nil {
+		lockTimer.Reset(autoLockTimeout())
+	}
+}
+
+// Forget every decrypted secret and the wallet key, stop talking to the
+// network and ask for the password again before anything can be signed
+func lockWallet(window fyne.Window) {
+	lockMu.Lock()
+	if lockTimer != nil {
+		lockTimer.Stop()
+		lockTimer = nil
+	}
+	lockMu.Unlock()
+	stopPaymentStream()
+	stopPolling()
+
+	walletMu.Lock()
+	for i := range store.Wallets {
+		store.Wallets[i].SecretKey = ""
+	}
+	for i := range walletKey {
+		walletKey[i] = 0
+	}
+	walletKey = nil
+	walletMu.Unlock()
+
+	// Dialogs left open could still act on the wallet
+	overlays := window.Canvas().Overlays()
+	for overlays.Top() != nil {
+		overlays.Remove(overlays.Top())
+	}
+	window.SetMainMenu(nil)
+	removeMainShortcuts(window.Canvas())
+	window.SetContent(lockedContent())
+	showUnlockDialog(window, func() { showMainWindow(window) })
+}
+
+// Wraps the main window content to notice the mouse moving or clicking over
+// it. Widgets that handle these themselves, like buttons, don't pass them on,
+// so their actions call touchActivity directly.
+type activityTracker struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+}
+
+func newActivityTracker(content fyne.CanvasObject) *activityTracker {
+	t := &activityTracker{content: content}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *activityTracker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(t.content)
+}
+
+func (t *activityTracker) MouseIn(*desktop.MouseEvent)    { touchActivity() }
+func (t *activityTracker) MouseMoved(*desktop.MouseEvent) { touchActivity() }
+func (t *activityTracker) MouseOut()                      {}
+func (t *activityTracker) Tapped(*fyne.PointEvent)        { touchActivity() }
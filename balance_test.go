@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestBalanceErrorText(t *testing.T) {
+	notFound := &horizonclient.Error{Problem: problem.P{Status: 404, Title: "Resource Missing"}}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name     string
+		err      error
+		last     string
+		expected string
+	}{
+		{"missing account", notFound, "", "Account not funded"},
+		{"missing account ignores last balance", fmt.Errorf("wrapped: %w", notFound), "3.0000000", "Account not funded"},
+		{"offline keeps last balance", refused, "3.0000000", "Balance: 3.0000000 XLM (offline, last known)"},
+		{"timeout keeps last balance", context.DeadlineExceeded, "3.0000000", "Balance: 3.0000000 XLM (offline, last known)"},
+		{"timeout without balance", context.DeadlineExceeded, "",
+			"Balance unavailable: Horizon did not respond in time; check your connection and try again"},
+		{"server error without balance", &horizonclient.Error{Problem: problem.P{Status: 502}}, "",
+			"Balance unavailable: Horizon server error (502); the network may be having problems, try again later"},
+		{"offline without balance", refused, "", "Balance unavailable (can't reach Horizon)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := balanceErrorText(tt.err, tt.last); got != tt.expected {
+				t.Errorf("balanceErrorText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"404", &horizonclient.Error{Problem: problem.P{Status: 404}}, true},
+		{"404 by value", horizonclient.Error{Problem: problem.P{Status: 404}}, true},
+		{"wrapped 404", fmt.Errorf("source account does not exist: %w", &horizonclient.Error{Problem: problem.P{Status: 404}}), true},
+		{"400", &horizonclient.Error{Problem: problem.P{Status: 400}}, false},
+		{"timeout", context.DeadlineExceeded, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNotFound(tt.err); got != tt.expected {
+				t.Errorf("isNotFound() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyBalanceDropsStaleResults(t *testing.T) {
+	wallet = &Wallet{PublicKey: testWallet, Network: "testnet", Balance: "5.0000000"}
+	offline := errors.New("connection refused")
+
+	refreshMu.Lock()
+	generation := balanceGeneration
+	refreshMu.Unlock()
+
+	text, ok := applyBalance(generation, horizon.Account{}, offline)
+	if !ok || text != "Balance: 5.0000000 XLM (offline, last known)" {
+		t.Errorf("current refresh = %q, %v", text, ok)
+	}
+
+	lastBalances = map[string]string{"XLM": "5.0000000"}
+	resetBalanceState()
+	if lastBalances != nil {
+		t.Errorf("resetBalanceState kept lastBalances %v", lastBalances)
+	}
+	if text, ok := applyBalance(generation, horizon.Account{}, offline); ok {
+		t.Errorf("stale refresh applied: %q", text)
+	}
+}
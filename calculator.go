@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Price as a fraction, or nil for a zero or malformed one that can't be used
+func priceRat(p horizon.Price) *big.Rat {
+	if p.N <= 0 || p.D <= 0 {
+		return nil
+	}
+	return big.NewRat(int64(p.N), int64(p.D))
+}
+
+// Rough value of sellAmount of the book's base asset in its counter asset, at
+// the mid price between the best bid and ask (or whichever side exists). It
+// ignores depth, so large amounts will get less in practice, and rounds down
+// to whole stroops.
+func conversionEstimate(book horizon.OrderBookSummary, sellAmount string) (string, error) {
+	sold, err := amount.ParseInt64(strings.TrimSpace(sellAmount))
+	if err != nil || sold <= 0 {
+		return "", fmt.Errorf("invalid amount %q", sellAmount)
+	}
+
+	var bid, ask *big.Rat
+	if len(book.Bids) > 0 {
+		bid = priceRat(book.Bids[0].PriceR)
+	}
+	if len(book.Asks) > 0 {
+		ask = priceRat(book.Asks[0].PriceR)
+	}
+
+	var mid *big.Rat
+	switch {
+	case bid != nil && ask != nil:
+		mid = new(big.Rat).Add(bid, ask)
+		mid.Quo(mid, big.NewRat(2, 1))
+	case bid != nil:
+		mid = bid
+	case ask != nil:
+		mid = ask
+	default:
+		return "", fmt.Errorf("no offers in the order book")
+	}
+
+	value := new(big.Rat).Mul(big.NewRat(sold, 1), mid)
+	stroops := new(big.Int).Quo(value.Num(), value.Denom())
+	if !stroops.IsInt64() {
+		return "", fmt.Errorf("amount out of range")
+	}
+	return amount.StringFromInt64(stroops.Int64()), nil
+}
+
+func fetchOrderBook(selling, buying txnbuild.Asset) (horizon.OrderBookSummary, error) {
+	sellingType, sellingCode, sellingIssuer := horizonAssetParams(selling)
+	buyingType, buyingCode, buyingIssuer := horizonAssetParams(buying)
+	return client.OrderBook(horizonclient.OrderBookRequest{
+		SellingAssetType:   sellingType,
+		SellingAssetCode:   sellingCode,
+		SellingAssetIssuer: sellingIssuer,
+		BuyingAssetType:    buyingType,
+		BuyingAssetCode:    buyingCode,
+		BuyingAssetIssuer:  buyingIssuer,
+		Limit:              1,
+	})
+}
+
+// Small calculator converting between two assets at the current DEX mid price
+func showCalculatorDialog(from, to, sendAmount string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetText(from)
+	fromEntry.SetPlaceHolder("XLM or CODE:ISSUER")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetText(sendAmount)
+	toEntry := widget.NewEntry()
+	toEntry.SetText(to)
+	toEntry.SetPlaceHolder("XLM or CODE:ISSUER")
+	resultLabel := widget.NewLabel("")
+	resultLabel.Wrapping = fyne.TextWrapWord
+
+	calculate := widget.NewButton("Calculate", func() {
+		fromAsset, err := parseAsset(fromEntry.Text)
+		if err != nil {
+			resultLabel.SetText("From: " + err.Error())
+			return
+		}
+		toAsset, err := parseAsset(toEntry.Text)
+		if err != nil {
+			resultLabel.SetText("To: " + err.Error())
+			return
+		}
+		book, err := fetchOrderBook(fromAsset, toAsset)
+		if err != nil {
+			resultLabel.SetText(fmt.Sprintf("error loading order book: %v", err))
+			return
+		}
+		estimate, err := conversionEstimate(book, amountEntry.Text)
+		if err != nil {
+			resultLabel.SetText(err.Error())
+			return
+		}
+		resultLabel.SetText(fmt.Sprintf("≈ %s %s at the mid price", estimate, assetCode(toAsset)))
+	})
+
+	content := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("From", fromEntry),
+			widget.NewFormItem("Amount", amountEntry),
+			widget.NewFormItem("To", toEntry),
+		),
+		calculate,
+		resultLabel,
+	)
+	dialog.ShowCustom("Amount Calculator", "Close", content, window)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+)
+
+func TestConversionEstimate(t *testing.T) {
+	level := func(n, d int32) horizon.PriceLevel {
+		return horizon.PriceLevel{PriceR: horizon.Price{N: n, D: d}}
+	}
+	book := func(bids, asks []horizon.PriceLevel) horizon.OrderBookSummary {
+		return horizon.OrderBookSummary{Bids: bids, Asks: asks}
+	}
+	tests := []struct {
+		name    string
+		book    horizon.OrderBookSummary
+		amount  string
+		want    string
+		wantErr string
+	}{
+		{"mid price", book([]horizon.PriceLevel{level(1, 10)}, []horizon.PriceLevel{level(3, 10)}), "100", "20.0000000", ""},
+		{"only bids", book([]horizon.PriceLevel{level(1, 4)}, nil), "10", "2.5000000", ""},
+		{"only asks", book(nil, []horizon.PriceLevel{level(2, 1)}), "1.5", "3.0000000", ""},
+		{"best level used", book([]horizon.PriceLevel{level(1, 2), level(1, 100)}, nil), "2", "1.0000000", ""},
+		{"rounds down to a stroop", book(nil, []horizon.PriceLevel{level(1, 3)}), "1", "0.3333333", ""},
+		{"below a stroop", book(nil, []horizon.PriceLevel{level(1, 3)}), "0.0000001", "0.0000000", ""},
+		{"zero price side ignored", book([]horizon.PriceLevel{level(0, 1)}, []horizon.PriceLevel{level(1, 2)}), "4", "2.0000000", ""},
+		{"zero denominator ignored", book([]horizon.PriceLevel{level(1, 0)}, []horizon.PriceLevel{level(1, 2)}), "4", "2.0000000", ""},
+		{"only zero prices", book([]horizon.PriceLevel{level(0, 1)}, []horizon.PriceLevel{level(5, 0)}), "4", "", "no offers"},
+		{"empty book", book(nil, nil), "1", "", "no offers"},
+		{"zero amount", book(nil, []horizon.PriceLevel{level(1, 1)}), "0", "", "invalid amount"},
+		{"bad amount", book(nil, []horizon.PriceLevel{level(1, 1)}), "ten", "", "invalid amount"},
+		{"overflow", book(nil, []horizon.PriceLevel{level(2147483647, 1)}), "900000000000", "", "out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conversionEstimate(tt.book, tt.amount)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("estimate = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// Optional Horizon endpoints that custom or older servers may not offer
+type feature string
+
+const (
+	featurePaths             feature = "paths"
+	featureFeeStats          feature = "fee_stats"
+	featureClaimableBalances feature = "claimable_balances"
+	featureOffers            feature = "offers"
+	featureOrderBook         feature = "order_book"
+	featureLiquidityPools    feature = "liquidity_pools"
+)
+
+var allFeatures = []feature{
+	featurePaths,
+	featureFeeStats,
+	featureClaimableBalances,
+	featureOffers,
+	featureOrderBook,
+	featureLiquidityPools,
+}
+
+type gatedMenuItem struct {
+	item  *fyne.MenuItem
+	label string
+}
+
+var (
+	capabilitiesMu sync.RWMutex
+	capabilities   = map[feature]bool{}
+
+	// Gated items of the current main menu, replaced whenever it is rebuilt.
+	// Written while menus are built and read by refreshCapabilities.
+	menuItemsMu      sync.Mutex
+	featureMenuItems = map[feature][]gatedMenuItem{}
+)
+
+// Whether the server reports an endpoint as missing rather than just failing
+func isUnsupportedError(err error) bool {
+	herr := horizonError(err)
+	if herr == nil {
+		return false
+	}
+	return herr.Problem.Status == http.StatusNotFound || herr.Problem.Status == http.StatusNotImplemented
+}
+
+// Work out which optional features the server supports from its root links.
+// Transient errors leave everything enabled; only a server that clearly lacks
+// the endpoint disables a feature.
+func probeCapabilities(root horizon.Root, err error) map[feature]bool {
+	enabled := make(map[feature]bool, len(allFeatures))
+	if err != nil {
+		unsupported := isUnsupportedError(err)
+		for _, f := range allFeatures {
+			enabled[f] = !unsupported
+		}
+		return enabled
+	}
+
+	links := root.Links
+	enabled[featurePaths] = links.StrictSendPaths != nil && links.StrictSendPaths.Href != ""
+	enabled[featureFeeStats] = links.FeeStats.Href != ""
+	enabled[featureClaimableBalances] = links.ClaimableBalances != nil && links.ClaimableBalances.Href != ""
+	enabled[featureOffers] = links.Offers != nil && links.Offers.Href != ""
+	enabled[featureOrderBook] = links.OrderBook.Href != ""
+	enabled[featureLiquidityPools] = links.LiquidityPools != nil && links.LiquidityPools.Href != ""
+	return enabled
+}
+
+func featureEnabled(f feature) bool {
+	capabilitiesMu.RLock()
+	defer capabilitiesMu.RUnlock()
+	enabled, known := capabilities[f]
+	return !known || enabled
+}
+
+// Forget the gated items of the previous menu; called as a menu is built
+func resetGatedMenuItems() {
+	menuItemsMu.Lock()
+	defer menuItemsMu.Unlock()
+	featureMenuItems = map[feature][]gatedMenuItem{}
+}
+
+// Register a menu item that only works when the server supports f, starting
+// from what the last probe found
+func gateMenuItem(f feature, item *fyne.MenuItem) *fyne.MenuItem {
+	gated := gatedMenuItem{item: item, label: item.Label}
+	gated.apply(featureEnabled(f))
+
+	menuItemsMu.Lock()
+	defer menuItemsMu.Unlock()
+	featureMenuItems[f] = append(featureMenuItems[f], gated)
+	return item
+}
+
+func (g gatedMenuItem) apply(enabled bool) {
+	g.item.Disabled = !enabled
+	g.item.Label = g.label
+	if !enabled {
+		g.item.Label += " (not supported by this server)"
+	}
+}
+
+// Which optional features the server behind hc supports
+func probeServer(hc core.HorizonAPI) map[feature]bool {
+	return probeCapabilities(hc.Root())
+}
+
+// Probe the current server and disable menu items it can't serve
+func refreshCapabilities() {
+	probed := probeServer(client)
+	capabilitiesMu.Lock()
+	capabilities = probed
+	capabilitiesMu.Unlock()
+
+	menuItemsMu.Lock()
+	for f, items := range featureMenuItems {
+		for _, gated := range items {
+			gated.apply(featureEnabled(f))
+		}
+	}
+	menuItemsMu.Unlock()
+
+	for _, window := range fyne.CurrentApp().Driver().AllWindows() {
+		if menu := window.MainMenu(); menu != nil {
+			menu.Refresh()
+		}
+	}
+}
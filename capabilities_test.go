@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// Root as a server would send it, linking only the given endpoints
+func rootWithLinks(t *testing.T, links ...string) horizon.Root {
+	t.Helper()
+	hrefs := map[string]map[string]string{}
+	for _, l := range links {
+		hrefs[l] = map[string]string{"href": "https://horizon.example/" + l}
+	}
+	data, err := json.Marshal(map[string]interface{}{"_links": hrefs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root horizon.Root
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestProbeServer(t *testing.T) {
+	all := []string{"strict_send_paths", "fee_stats", "claimable_balances", "offers", "order_book", "liquidity_pools"}
+	tests := []struct {
+		name     string
+		root     horizon.Root
+		err      error
+		disabled []feature
+	}{
+		{"full server", rootWithLinks(t, all...), nil, nil},
+		{"no pools or claimable balances", rootWithLinks(t, "strict_send_paths", "fee_stats", "offers", "order_book"), nil,
+			[]feature{featureClaimableBalances, featureLiquidityPools}},
+		{"root missing", horizon.Root{}, &horizonclient.Error{Problem: problem.P{Status: 404}}, allFeatures},
+		{"not implemented", horizon.Root{}, &horizonclient.Error{Problem: problem.P{Status: 501}}, allFeatures},
+		{"server error", horizon.Root{}, &horizonclient.Error{Problem: problem.P{Status: 503}}, nil},
+		{"network error", horizon.Root{}, errors.New("connection refused"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := horizontest.NewFakeHorizon()
+			fake.ServerRoot, fake.RootErr = tt.root, tt.err
+			got := probeServer(fake)
+
+			disabled := map[feature]bool{}
+			for _, f := range tt.disabled {
+				disabled[f] = true
+			}
+			for _, f := range allFeatures {
+				if got[f] != !disabled[f] {
+					t.Errorf("%s enabled = %v, want %v", f, got[f], !disabled[f])
+				}
+			}
+		})
+	}
+}
+
+func TestGateMenuItem(t *testing.T) {
+	capabilitiesMu.Lock()
+	saved := capabilities
+	capabilities = map[feature]bool{featureOffers: false}
+	capabilitiesMu.Unlock()
+	t.Cleanup(func() {
+		capabilitiesMu.Lock()
+		capabilities = saved
+		capabilitiesMu.Unlock()
+		resetGatedMenuItems()
+	})
+
+	for i := 0; i < 3; i++ {
+		resetGatedMenuItems()
+		gateMenuItem(featurePaths, fyne.NewMenuItem("Path Payment", nil))
+		offers := gateMenuItem(featureOffers, fyne.NewMenuItem("Open Offers", nil))
+		if !offers.Disabled || offers.Label != "Open Offers (not supported by this server)" {
+			t.Errorf("offers item = %q disabled %v, want it disabled when registered", offers.Label, offers.Disabled)
+		}
+	}
+
+	menuItemsMu.Lock()
+	defer menuItemsMu.Unlock()
+	if n := len(featureMenuItems[featurePaths]) + len(featureMenuItems[featureOffers]); n != 2 {
+		t.Errorf("%d gated items after rebuilding the menu three times, want 2", n)
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// What a claim is expected to pay out, from a shared claim link or typed in
+type claimExpectation struct {
+	BalanceID string
+	Asset     string
+	Amount    string
+}
+
+// Accept either a bare balance ID or a link carrying id, asset and amount query parameters
+func parseClaimInput(input string) claimExpectation {
+	input = strings.TrimSpace(input)
+	_, query, ok := strings.Cut(input, "?")
+	if !ok {
+		return claimExpectation{BalanceID: input}
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return claimExpectation{BalanceID: input}
+	}
+	return claimExpectation{
+		BalanceID: values.Get("id"),
+		Asset:     values.Get("asset"),
+		Amount:    values.Get("amount"),
+	}
+}
+
+// Ways a claimable balance differs from what the user expected to receive.
+// Empty expectations are not checked.
+func claimExpectationMismatches(balance horizon.ClaimableBalance, expected claimExpectation) []string {
+	var mismatches []string
+
+	if expected.Asset != "" {
+		want, err := parseAsset(expected.Asset)
+		got, gotErr := parseAsset(balance.Asset)
+		if err != nil || gotErr != nil || assetString(want) != assetString(got) {
+			mismatches = append(mismatches, fmt.Sprintf("asset is %s, expected %s", balance.Asset, expected.Asset))
+		}
+	}
+
+	if expected.Amount != "" {
+		want, err := amount.ParseInt64(expected.Amount)
+		got, gotErr := amount.ParseInt64(balance.Amount)
+		if err != nil || gotErr != nil || want != got {
+			mismatches = append(mismatches, fmt.Sprintf("amount is %s, expected %s", balance.Amount, expected.Amount))
+		}
+	}
+	return mismatches
+}
+
+func isClaimant(balance horizon.ClaimableBalance, accountID string) bool {
+	for _, claimant := range balance.Claimants {
+		if claimant.Destination == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+func showClaimBalanceDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	idEntry := widget.NewEntry()
+	idEntry.SetPlaceHolder("Balance ID or claim link")
+	assetEntry := widget.NewEntry()
+	assetEntry.SetPlaceHolder("Expected asset (optional)")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Expected amount (optional)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Balance", idEntry),
+		widget.NewFormItem("Asset", assetEntry),
+		widget.NewFormItem("Amount", amountEntry),
+	}
+
+	dialog.ShowForm("Claim Balance", "Review", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		expected := parseClaimInput(idEntry.Text)
+		if asset := strings.TrimSpace(assetEntry.Text); asset != "" {
+			expected.Asset = asset
+		}
+		if amt := strings.TrimSpace(amountEntry.Text); amt != "" {
+			expected.Amount = amt
+		}
+		if expected.BalanceID == "" {
+			dialog.ShowError(fmt.Errorf("balance ID is required"), window)
+			return
+		}
+
+		balance, err := client.ClaimableBalance(expected.BalanceID)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading claimable balance: %v", err), window)
+			return
+		}
+		confirmClaim(balance, expected)
+	}, window)
+}
+
+// Show what a claim pays out, with any surprises, before signing it
+func confirmClaim(balance horizon.ClaimableBalance, expected claimExpectation) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	predicate, ok := claimantPredicate(balance, wallet.PublicKey)
+	if !ok {
+		dialog.ShowError(fmt.Errorf("this account is not a claimant of the balance"), window)
+		return
+	}
+	if !predicateSatisfied(predicate, time.Now()) {
+		dialog.ShowError(fmt.Errorf("this balance can't be claimed yet; it is claimable %s", predicateText(predicate)), window)
+		return
+	}
+
+	message := fmt.Sprintf("Claim %s %s?", balance.Amount, balance.Asset)
+	if mismatches := claimExpectationMismatches(balance, expected); len(mismatches) > 0 {
+		message = fmt.Sprintf("Warning: this balance does not match what you expected:\n%s\n\n%s",
+			strings.Join(mismatches, "\n"), message)
+	}
+
+	dialog.ShowConfirm("Confirm Claim", message, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		submitWithFeedback([]txnbuild.Operation{
+			&txnbuild.ClaimClaimableBalance{BalanceID: balance.BalanceID},
+		}, nil, func(hash string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Balance claimed! Hash: %s", hash), window)
+		})
+	}, window)
+}
+
+// All claimable balances the account can claim, following Horizon's paging
+func fetchClaimableBalances(accountID string) ([]horizon.ClaimableBalance, error) {
+	var balances []horizon.ClaimableBalance
+	cursor := ""
+	for {
+		page, err := client.ClaimableBalances(horizonclient.ClaimableBalanceRequest{
+			Claimant: accountID,
+			Cursor:   cursor,
+			Limit:    200,
+		})
+		if err != nil {
+			return nil, err
+		}
+		records := page.Embedded.Records
+		if len(records) == 0 {
+			return balances, nil
+		}
+		balances = append(balances, records...)
+		cursor = records[len(records)-1].PT
+	}
+}
+
+// Claim operations for the selected balances, skipping duplicates, grouped
+// into transactions within the op limit
+func claimOps(balanceIDs []string) [][]txnbuild.Operation {
+	seen := make(map[string]bool, len(balanceIDs))
+	var ops []txnbuild.Operation
+	for _, id := range balanceIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ops = append(ops, &txnbuild.ClaimClaimableBalance{BalanceID: id})
+	}
+	return chunkOperations(ops, maxOpsPerTx)
+}
+
+// Balance IDs claimed by batches of claim operations
+func claimedBalanceIDs(batches [][]txnbuild.Operation) []string {
+	var ids []string
+	for _, batch := range batches {
+		for _, op := range batch {
+			if claim, ok := op.(*txnbuild.ClaimClaimableBalance); ok {
+				ids = append(ids, claim.BalanceID)
+			}
+		}
+	}
+	return ids
+}
+
+func showClaimableBalancesDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	balances, err := fetchClaimableBalances(wallet.PublicKey)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading claimable balances: %v", err), window)
+		return
+	}
+	if len(balances) == 0 {
+		dialog.ShowInformation("Claimable Balances", "No claimable balances are waiting for this account.", window)
+		return
+	}
+
+	list := container.NewVBox()
+	checks := make([]*widget.Check, len(balances))
+	for i, balance := range balances {
+		code := balance.Asset
+		if asset, err := parseAsset(balance.Asset); err == nil {
+			code = assetCode(asset)
+		}
+		// Balances that can't be claimed yet are listed but can't be selected
+		predicate, _ := claimantPredicate(balance, wallet.PublicKey)
+		checks[i] = widget.NewCheck(fmt.Sprintf("%s %s, claimable %s", balance.Amount, code, predicateText(predicate)), nil)
+		if !predicateSatisfied(predicate, time.Now()) {
+			checks[i].Disable()
+		}
+		list.Add(checks[i])
+	}
+	selectAll := widget.NewCheck("Select all", func(checked bool) {
+		for _, check := range checks {
+			if !check.Disabled() {
+				check.SetChecked(checked)
+			}
+		}
+	})
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(scaled(320), scaled(240)))
+	content := container.NewBorder(selectAll, nil, nil, nil, scroll)
+
+	dialog.ShowCustomConfirm("Claimable Balances", "Claim Selected", "Close", content, func(claim bool) {
+		if !claim {
+			return
+		}
+		var ids []string
+		for i, check := range checks {
+			if check.Checked {
+				ids = append(ids, balances[i].BalanceID)
+			}
+		}
+		if len(ids) == 0 {
+			return
+		}
+
+		batches := claimOps(ids)
+		message := fmt.Sprintf("Claim %d balance(s) in %d transaction(s)?", len(ids), len(batches))
+		dialog.ShowConfirm("Confirm Claim", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			submitBatches(batches, nil, func(hashes []string) {
+				dialog.ShowInformation("Success", fmt.Sprintf("Claimed %d balance(s) in %d transaction(s).", len(ids), len(hashes)), window)
+			}, func(hashes []string, failed int) {
+				unclaimed := claimedBalanceIDs(batches[failed:])
+				remaining := fmt.Sprintf("%d balance(s) not claimed:\n%s", len(unclaimed), strings.Join(unclaimed, "\n"))
+				showPartialBatches(hashes, len(batches), remaining)
+			})
+		}, window)
+	}, window)
+}
+
+// Predicate the account must satisfy to claim balance
+func claimantPredicate(balance horizon.ClaimableBalance, accountID string) (xdr.ClaimPredicate, bool) {
+	for _, claimant := range balance.Claimants {
+		if claimant.Destination == accountID {
+			return claimant.Predicate, true
+		}
+	}
+	return xdr.ClaimPredicate{}, false
+}
+
+// Whether a claim made at now would satisfy p. Horizon reports relative
+// predicates as absolute ones, so a relative one left over is assumed to hold.
+func predicateSatisfied(p xdr.ClaimPredicate, now time.Time) bool {
+	switch p.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateAnd:
+		for _, sub := range *p.AndPredicates {
+			if !predicateSatisfied(sub, now) {
+				return false
+			}
+		}
+		return true
+	case xdr.ClaimPredicateTypeClaimPredicateOr:
+		for _, sub := range *p.OrPredicates {
+			if predicateSatisfied(sub, now) {
+				return true
+			}
+		}
+		return false
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		return p.NotPredicate == nil || *p.NotPredicate == nil || !predicateSatisfied(**p.NotPredicate, now)
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		return now.Unix() < int64(*p.AbsBefore)
+	default:
+		return true
+	}
+}
+
+// Readable form of a claim predicate, e.g. "after 2026-01-02 15:04"
+func predicateText(p xdr.ClaimPredicate) string {
+	const layout = "2006-01-02 15:04"
+	switch p.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateAnd:
+		parts := make([]string, len(*p.AndPredicates))
+		for i, sub := range *p.AndPredicates {
+			parts[i] = predicateText(sub)
+		}
+		return "(" + strings.Join(parts, " and ") + ")"
+	case xdr.ClaimPredicateTypeClaimPredicateOr:
+		parts := make([]string, len(*p.OrPredicates))
+		for i, sub := range *p.OrPredicates {
+			parts[i] = predicateText(sub)
+		}
+		return "(" + strings.Join(parts, " or ") + ")"
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		if p.NotPredicate == nil || *p.NotPredicate == nil {
+			return "never"
+		}
+		inner := **p.NotPredicate
+		if inner.Type == xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime {
+			return "after " + time.Unix(int64(*inner.AbsBefore), 0).Local().Format(layout)
+		}
+		return "not " + predicateText(inner)
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		return "before " + time.Unix(int64(*p.AbsBefore), 0).Local().Format(layout)
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime:
+		return fmt.Sprintf("within %s of creation", time.Duration(*p.RelBefore)*time.Second)
+	default:
+		return "any time"
+	}
+}
+
+// When a new claimable balance may be claimed from, typed as a duration from
+// now ("48h") or a date ("2006-01-02"). Empty means straight away.
+func parseClaimAfter(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("claim delay must be positive")
+		}
+		return now.Add(d), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("claim after must be a duration like 48h or a date like 2006-01-02")
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("claim date must be in the future")
+	}
+	return t, nil
+}
+
+// Predicate letting a claimant claim from after onwards, or at any time if it's zero
+func claimAfterPredicate(after time.Time) *xdr.ClaimPredicate {
+	if after.IsZero() {
+		return nil
+	}
+	p := txnbuild.NotPredicate(txnbuild.BeforeAbsoluteTimePredicate(after.Unix()))
+	return &p
+}
+
+func claimableBalanceOp(recipient, amount string, asset txnbuild.Asset, claimAfter time.Time) *txnbuild.CreateClaimableBalance {
+	return &txnbuild.CreateClaimableBalance{
+		Destinations: []txnbuild.Claimant{txnbuild.NewClaimant(recipient, claimAfterPredicate(claimAfter))},
+		Asset:        asset,
+		Amount:       amount,
+	}
+}
+
+// Put amount of asset in a claimable balance for recipient, who doesn't need
+// a funded account or trustline to receive it
+func sendClaimableBalance(recipient, amount string, memo memoSpec, asset txnbuild.Asset, baseFee int64, claimAfter time.Time, balanceLabel *widget.Label) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if !strkey.IsValidEd25519PublicKey(recipient) {
+		dialog.ShowError(fmt.Errorf("claimable balances need a G... recipient address"), window)
+		return
+	}
+	if err := validateAmount(amount); err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	txMemo, err := buildMemo(memo)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	op := claimableBalanceOp(recipient, amount, asset, claimAfter)
+	submitWithFee([]txnbuild.Operation{op}, txMemo, baseFee, nil, func(hash string) {
+		dialog.ShowInformation("Success", fmt.Sprintf("Claimable balance created! Hash: %s", hash), window)
+		if balanceLabel != nil {
+			refreshBalanceAsync(balanceLabel)
+		}
+	})
+}
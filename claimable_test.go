@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+)
+
+const testBalanceID = "00000000da0d57da7d4850e7fc10d2a9d0ebc731f7afb40574c03395b17d49149b91f5be"
+
+func TestParseClaimInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  claimExpectation
+	}{
+		{"bare id", "  " + testBalanceID + "\n", claimExpectation{BalanceID: testBalanceID}},
+		{"link", "web+stellar:claim?id=" + testBalanceID + "&asset=USD%3A" + testOther + "&amount=10",
+			claimExpectation{BalanceID: testBalanceID, Asset: "USD:" + testOther, Amount: "10"}},
+		{"link with id only", "https://example.com/claim?id=" + testBalanceID, claimExpectation{BalanceID: testBalanceID}},
+		{"malformed query", "claim?id=%zz", claimExpectation{BalanceID: "claim?id=%zz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseClaimInput(tt.input); got != tt.want {
+				t.Errorf("parseClaimInput = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimExpectationMismatches(t *testing.T) {
+	usd := "USD:" + testOther
+	balance := horizon.ClaimableBalance{BalanceID: testBalanceID, Asset: usd, Amount: "10.0000000"}
+	tests := []struct {
+		name     string
+		expected claimExpectation
+		want     []string
+	}{
+		{"nothing expected", claimExpectation{BalanceID: testBalanceID}, nil},
+		{"matching asset and amount", claimExpectation{Asset: usd, Amount: "10"}, nil},
+		{"other asset", claimExpectation{Asset: "native"},
+			[]string{"asset is " + usd + ", expected native"}},
+		{"other issuer", claimExpectation{Asset: "USD:" + testWallet},
+			[]string{"asset is " + usd + ", expected USD:" + testWallet}},
+		{"unparseable asset", claimExpectation{Asset: "USD"},
+			[]string{"asset is " + usd + ", expected USD"}},
+		{"smaller amount", claimExpectation{Amount: "10.5"},
+			[]string{"amount is 10.0000000, expected 10.5"}},
+		{"both differ", claimExpectation{Asset: "XLM", Amount: "abc"},
+			[]string{"asset is " + usd + ", expected XLM", "amount is 10.0000000, expected abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claimExpectationMismatches(balance, tt.expected); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mismatches = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimOps(t *testing.T) {
+	ids := make([]string, 0, 150)
+	for i := 0; i < 150; i++ {
+		ids = append(ids, fmt.Sprintf("%072x", i))
+	}
+	tests := []struct {
+		name  string
+		ids   []string
+		sizes []int
+		want  []string
+	}{
+		{"none", nil, nil, nil},
+		{"duplicates and blanks", []string{testBalanceID, "", testBalanceID}, []int{1}, []string{testBalanceID}},
+		{"over the op limit", ids, []int{100, 50}, ids},
+		{"duplicates across batches", append(append([]string(nil), ids...), ids[0], ids[120]), []int{100, 50}, ids},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := claimOps(tt.ids)
+			var sizes []int
+			for _, batch := range batches {
+				sizes = append(sizes, len(batch))
+			}
+			if !reflect.DeepEqual(sizes, tt.sizes) {
+				t.Errorf("batch sizes = %v, want %v", sizes, tt.sizes)
+			}
+			if got := claimedBalanceIDs(batches); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("claimed %d ids, want %d in order", len(got), len(tt.want))
+			}
+		})
+	}
+
+	// What is left to claim once the first batch landed and the second failed
+	batches := claimOps(ids)
+	if got := claimedBalanceIDs(batches[1:]); !reflect.DeepEqual(got, ids[100:]) {
+		t.Errorf("unclaimed = %d ids, want the last 50", len(got))
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestCopyToClipboard(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	// The test driver opens a dummy window of its own
+	for _, window := range append([]fyne.Window(nil), app.Driver().AllWindows()...) {
+		window.Close()
+	}
+	if _, err := copyToClipboard(testWallet); err == nil {
+		t.Error("copied with no window open")
+	}
+
+	window := app.NewWindow("wallet")
+	defer window.Close()
+	got, err := copyToClipboard(testWallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != window {
+		t.Error("copied through another window")
+	}
+	if content := window.Clipboard().Content(); content != testWallet {
+		t.Errorf("clipboard = %q, want %q", content, testWallet)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"github.com/stellar/go/txnbuild"
+)
+
+const (
+	txTimeout = 300 // seconds
+
+	// Ledgers close every ~5s, so anything much beyond that is the local clock
+	maxClockSkew = 30 * time.Second
+)
+
+var (
+	clockSkewMu sync.RWMutex
+	clockSkew   time.Duration // ledger time minus local time
+)
+
+// How far the local clock trails the network, given the latest ledger close time
+func clockSkewFrom(local, ledgerClosedAt time.Time) time.Duration {
+	return ledgerClosedAt.Sub(local)
+}
+
+func skewSignificant(skew time.Duration) bool {
+	return skew > maxClockSkew || skew < -maxClockSkew
+}
+
+// Time bounds expiring timeout seconds from now. When the clock is off and
+// useLedgerTime is set, "now" is corrected by the measured skew first.
+func timeBoundsAt(now time.Time, skew time.Duration, timeout int64, useLedgerTime bool) txnbuild.TimeBounds {
+	if useLedgerTime && skewSignificant(skew) {
+		now = now.Add(skew)
+	}
+	return txnbuild.NewTimebounds(0, now.Unix()+timeout)
+}
+
+func currentClockSkew() time.Duration {
+	clockSkewMu.RLock()
+	defer clockSkewMu.RUnlock()
+	return clockSkew
+}
+
+// Time bounds for a transaction submitted now
+func transactionTimeBounds() txnbuild.TimeBounds {
+	return timeBoundsAt(time.Now(), currentClockSkew(), txTimeout, settings.UseLedgerTime)
+}
+
+func skewText(skew time.Duration) string {
+	skew = skew.Round(time.Second)
+	if skew > 0 {
+		return fmt.Sprintf("Your clock is %v behind the network.", skew)
+	}
+	return fmt.Sprintf("Your clock is %v ahead of the network.", -skew)
+}
+
+// Measure skew against the latest ledger and warn if it would break time bounds
+func checkClockSkew() {
+	root, err := client.Root()
+	if err != nil || root.HorizonLatestClosedAt.IsZero() {
+		return
+	}
+	skew := clockSkewFrom(time.Now(), root.HorizonLatestClosedAt)
+
+	clockSkewMu.Lock()
+	clockSkew = skew
+	clockSkewMu.Unlock()
+
+	if !skewSignificant(skew) || settings.UseLedgerTime {
+		return
+	}
+
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+	message := skewText(skew) + "\nTransactions may fail with tx_too_early or tx_too_late.\n\nBase transaction time limits on ledger time instead?"
+	dialog.ShowConfirm("Clock Skew", message, func(use bool) {
+		if !use {
+			return
+		}
+		settings.UseLedgerTime = true
+		if err := saveSettings(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+		}
+	}, window)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockSkewFrom(t *testing.T) {
+	local := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		ledger      time.Time
+		want        time.Duration
+		significant bool
+	}{
+		{"in step", local, 0, false},
+		{"ledger a few seconds ahead", local.Add(5 * time.Second), 5 * time.Second, false},
+		{"at the limit", local.Add(maxClockSkew), maxClockSkew, false},
+		{"local clock behind", local.Add(2 * time.Minute), 2 * time.Minute, true},
+		{"local clock ahead", local.Add(-10 * time.Minute), -10 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skew := clockSkewFrom(local, tt.ledger)
+			if skew != tt.want {
+				t.Errorf("skew = %v, want %v", skew, tt.want)
+			}
+			if got := skewSignificant(skew); got != tt.significant {
+				t.Errorf("significant = %v, want %v", got, tt.significant)
+			}
+		})
+	}
+}
+
+func TestTimeBoundsAt(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name          string
+		skew          time.Duration
+		useLedgerTime bool
+		wantMax       int64
+	}{
+		{"no skew", 0, true, 1700000300},
+		{"skew ignored when not using ledger time", 10 * time.Minute, false, 1700000300},
+		{"small skew ignored", 20 * time.Second, true, 1700000300},
+		{"behind corrected", 10 * time.Minute, true, 1700000900},
+		{"ahead corrected", -2 * time.Minute, true, 1700000180},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := timeBoundsAt(now, tt.skew, txTimeout, tt.useLedgerTime)
+			if bounds.MinTime != 0 || bounds.MaxTime != tt.wantMax {
+				t.Errorf("bounds = [%d, %d], want [0, %d]", bounds.MinTime, bounds.MaxTime, tt.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/clients/horizonclient"
+)
+
+// Overrides given on the command line, for this run only
+type launchOptions struct {
+	Network    string // "testnet" or "public"; empty keeps the wallet's
+	WalletPath string // wallet file in place of the one in the config directory
+	HorizonURL string // Horizon server for the network the wallet starts on
+}
+
+var launch launchOptions
+
+// Parse and check the command line. flag.ErrHelp is returned after usage has
+// been printed for -h.
+func parseLaunchOptions(args []string, output io.Writer) (launchOptions, error) {
+	var opts launchOptions
+	fs := flag.NewFlagSet("fyne-stellar", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&opts.Network, "network", "", "network to use, testnet or public (default: the wallet's own)")
+	fs.StringVar(&opts.WalletPath, "wallet", "", "path of the wallet file (default: "+walletFile+" in the config directory)")
+	fs.StringVar(&opts.HorizonURL, "horizon", "", "Horizon server URL for the starting network (default: SDF's server)")
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage: %s [flags]\n\n", fs.Name())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return launchOptions{}, err
+	}
+	if fs.NArg() > 0 {
+		return launchOptions{}, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	if opts.Network != "" && !core.ValidNetwork(opts.Network) {
+		return launchOptions{}, fmt.Errorf("-network must be testnet or public, not %q", opts.Network)
+	}
+	if opts.WalletPath != "" {
+		path, err := filepath.Abs(opts.WalletPath)
+		if err != nil {
+			return launchOptions{}, fmt.Errorf("invalid -wallet path: %v", err)
+		}
+		opts.WalletPath = path
+	}
+	if opts.HorizonURL != "" {
+		u, err := url.Parse(opts.HorizonURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return launchOptions{}, fmt.Errorf("-horizon must be an http or https URL, not %q", opts.HorizonURL)
+		}
+	}
+	return opts, nil
+}
+
+// Network the -horizon server is used for, set once the wallet is loaded
+var customHorizonNetwork string
+
+// Horizon client for network, honouring -horizon
+func launchClient(network string) *horizonclient.Client {
+	if launch.HorizonURL != "" && network == customHorizonNetwork {
+		return core.NewHorizonClient(launch.HorizonURL)
+	}
+	return core.HorizonClient(network)
+}
+
+// Apply -network to the loaded wallet and tie -horizon to the network it
+// starts on
+func applyLaunchNetwork() {
+	if launch.Network != "" {
+		wallet.Network = launch.Network
+	}
+	customHorizonNetwork = wallet.Network
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Subfolder of the user's config directory holding the wallet and settings
+const appDirName = "fyne-stellar"
+
+// The app's config directory, created if needed
+func configDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no config directory: %v", err)
+	}
+	dir := filepath.Join(base, appDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("error creating config directory: %v", err)
+	}
+	return dir, nil
+}
+
+// Path of name in the config directory. A copy left in the working
+// directory by older versions, which wrote there, is moved over first.
+func configPath(name string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if err := migrateFromWorkingDir(name, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func migrateFromWorkingDir(name, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s to migrate it: %v", name, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("error migrating %s: %v", name, err)
+	}
+	if err := os.Remove(name); err != nil {
+		log.Printf("migrated %s to %s but could not remove the old copy: %v", name, path, err)
+		return nil
+	}
+	log.Printf("moved %s to %s", name, path)
+	return nil
+}
+
+// The wallet file, from -wallet when given
+func walletPath() (string, error) {
+	if launch.WalletPath != "" {
+		return launch.WalletPath, nil
+	}
+	return configPath(walletFile)
+}
+
+func settingsPath() (string, error) {
+	return configPath(settingsFile)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Sends of at least this much XLM are confirmed unless configured otherwise
+const defaultConfirmThreshold = "1000"
+
+// Whether a send needs an explicit confirmation step. Real funds on the public
+// network and large amounts always do; alwaysConfirm extends that to everything.
+func shouldConfirmSend(alwaysConfirm bool, network, sendAmount, threshold string) bool {
+	if alwaysConfirm || network == "public" {
+		return true
+	}
+	if threshold == "" {
+		threshold = defaultConfirmThreshold
+	}
+	limit, err := amount.ParseInt64(threshold)
+	if err != nil {
+		return true
+	}
+	sent, err := amount.ParseInt64(strings.TrimSpace(sendAmount))
+	if err != nil {
+		// Let the send itself report the invalid amount
+		return false
+	}
+	return sent >= limit
+}
+
+// Everything a payment will do, for the user to check before it is signed.
+// Amounts are shown to the full 7 decimal places the network uses.
+func sendSummaryText(params sendParams, network string, baseFee int64) string {
+	sendAmount := strings.TrimSpace(params.Amount)
+	if stroops, err := amount.ParseInt64(sendAmount); err == nil {
+		sendAmount = amount.StringFromInt64(stroops)
+	}
+	asset := params.Asset
+	if asset != "XLM" {
+		code, issuer, _ := strings.Cut(asset, ":")
+		asset = fmt.Sprintf("%s (issuer %s)", code, shortAddress(issuer))
+	}
+
+	text := fmt.Sprintf("Send %s %s\nto %s\non %s", sendAmount, asset, params.Recipient, network)
+	if memo := params.memo(); memo.Type != "none" {
+		text += fmt.Sprintf("\n\nMemo (%s): %s", memo.Type, memo.Value)
+	}
+	text += fmt.Sprintf("\n\nEstimated fee: %s XLM", stroopsToXLM(baseFee))
+	return text
+}
+
+// Run send directly or after the user confirms the details, as settings require
+func confirmSend(params sendParams, send func()) {
+	baseFee := params.BaseFee
+	if baseFee == 0 {
+		baseFee = txnbuild.MinBaseFee
+	}
+	confirmSendMessage(params.Amount, sendSummaryText(params, wallet.Network, baseFee), send)
+}
+
+// Like confirmSend for sends that aren't a single payment, described by message
+// and moving sendAmount in total
+func confirmSendMessage(sendAmount, message string, send func()) {
+	if !shouldConfirmSend(settings.AlwaysConfirmSends, wallet.Network, sendAmount, settings.ConfirmThreshold) {
+		send()
+		return
+	}
+
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+	dialog.ShowConfirm("Confirm Send", message, func(ok bool) {
+		if ok {
+			send()
+		}
+	}, window)
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestShouldConfirmSend(t *testing.T) {
+	tests := []struct {
+		name          string
+		alwaysConfirm bool
+		network       string
+		amount        string
+		threshold     string
+		expected      bool
+	}{
+		{"public always", false, "public", "1", "", true},
+		{"testnet small", false, "testnet", "1", "", false},
+		{"testnet always confirm", true, "testnet", "1", "", true},
+		{"testnet at default threshold", false, "testnet", defaultConfirmThreshold, "", true},
+		{"testnet custom threshold", false, "testnet", "50", "10", true},
+		{"bad threshold confirms", false, "testnet", "1", "lots", true},
+		{"bad amount left to the send", false, "testnet", "abc", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldConfirmSend(tt.alwaysConfirm, tt.network, tt.amount, tt.threshold); got != tt.expected {
+				t.Errorf("shouldConfirmSend() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplateTotal(t *testing.T) {
+	tests := []struct {
+		name     string
+		template sendTemplate
+		expected string
+	}{
+		{"single", sendTemplate{Recipients: []string{"a"}, Amount: "2.5"}, "2.5000000"},
+		{"several", sendTemplate{Recipients: []string{"a", "b", "c"}, Amount: "10"}, "30.0000000"},
+		{"overflow", sendTemplate{Recipients: []string{"a", "b", "c"}, Amount: "900000000000"}, "922337203685.4775807"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := templateTotal(tt.template); got != tt.expected {
+				t.Errorf("templateTotal() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
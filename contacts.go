@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/strkey"
+)
+
+// A named recipient, with the memo they require if any
+type contact struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Memo     string `json:"memo,omitempty"`
+	MemoType string `json:"memo_type,omitempty"`
+}
+
+func (c contact) memo() memoSpec {
+	if c.MemoType == "" {
+		return memoSpec{Type: "none"}
+	}
+	return memoSpec{Type: c.MemoType, Value: c.Memo}
+}
+
+// Add a contact, replacing any existing one with the same name
+func putContact(contacts []contact, c contact) ([]contact, error) {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Address = strings.TrimSpace(c.Address)
+	if c.Name == "" {
+		return contacts, fmt.Errorf("contact name is required")
+	}
+	if !strkey.IsValidEd25519PublicKey(c.Address) && !strkey.IsValidMuxedAccountEd25519PublicKey(c.Address) {
+		return contacts, fmt.Errorf("invalid Stellar address for %s", c.Name)
+	}
+	if c.MemoType == "none" {
+		c.Memo, c.MemoType = "", ""
+	}
+	if _, err := buildMemo(c.memo()); err != nil {
+		return contacts, err
+	}
+
+	updated := make([]contact, 0, len(contacts)+1)
+	for _, existing := range contacts {
+		if existing.Name != c.Name {
+			updated = append(updated, existing)
+		}
+	}
+	updated = append(updated, c)
+	sort.Slice(updated, func(i, j int) bool { return updated[i].Name < updated[j].Name })
+	return updated, nil
+}
+
+func findContact(contacts []contact, name string) (contact, bool) {
+	for _, c := range contacts {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return contact{}, false
+}
+
+func contactForAddress(contacts []contact, address string) (contact, bool) {
+	for _, c := range contacts {
+		if c.Address == address {
+			return c, true
+		}
+	}
+	return contact{}, false
+}
+
+func deleteContact(contacts []contact, name string) []contact {
+	var remaining []contact
+	for _, c := range contacts {
+		if c.Name != name {
+			remaining = append(remaining, c)
+		}
+	}
+	return remaining
+}
+
+func contactNames(contacts []contact) []string {
+	names := make([]string, len(contacts))
+	for i, c := range contacts {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func storeContact(c contact) error {
+	contacts, err := putContact(settings.Contacts, c)
+	if err != nil {
+		return err
+	}
+	settings.Contacts = contacts
+	return saveSettings()
+}
+
+// Ask for a name and save address, with the memo it was paid with, as a contact
+func showSaveContactDialog(address string, memo memoSpec) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. Alice")
+	memoCheck := widget.NewCheck("Always use this memo", nil)
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Address", widget.NewLabel(shortAddress(address))),
+	}
+	if memo.Type != "none" {
+		memoCheck.SetText(fmt.Sprintf("Always use memo %s %s", memo.Type, memo.Value))
+		items = append(items, widget.NewFormItem("", memoCheck))
+	}
+
+	dialog.ShowForm("Save Contact", "Save", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		c := contact{Name: nameEntry.Text, Address: address}
+		if memoCheck.Checked {
+			c.Memo, c.MemoType = memo.Value, memo.Type
+		}
+		if err := storeContact(c); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+}
+
+func showContactsDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	contactSelect := widget.NewSelect(contactNames(settings.Contacts), nil)
+	nameEntry := widget.NewEntry()
+	addressEntry := widget.NewEntry()
+	addressEntry.SetPlaceHolder("G... or M... address")
+	memoEntry := widget.NewEntry()
+	memoTypeSelect := widget.NewSelect(memoTypes, func(memoType string) {
+		memoEntry.SetPlaceHolder(memoPlaceholder(memoType))
+	})
+	memoTypeSelect.SetSelected("none")
+
+	contactSelect.OnChanged = func(name string) {
+		c, ok := findContact(settings.Contacts, name)
+		if !ok {
+			return
+		}
+		nameEntry.SetText(c.Name)
+		addressEntry.SetText(c.Address)
+		memoTypeSelect.SetSelected(c.memo().Type)
+		memoEntry.SetText(c.Memo)
+	}
+
+	current := func() contact {
+		return contact{
+			Name:     nameEntry.Text,
+			Address:  addressEntry.Text,
+			Memo:     memoEntry.Text,
+			MemoType: memoTypeSelect.Selected,
+		}
+	}
+
+	saveButton := widget.NewButton("Save", func() {
+		if err := storeContact(current()); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		contactSelect.Options = contactNames(settings.Contacts)
+		contactSelect.SetSelected(strings.TrimSpace(nameEntry.Text))
+	})
+	deleteButton := widget.NewButton("Delete", func() {
+		name := contactSelect.Selected
+		if name == "" {
+			return
+		}
+		settings.Contacts = deleteContact(settings.Contacts, name)
+		if err := saveSettings(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+		}
+		contactSelect.Options = contactNames(settings.Contacts)
+		contactSelect.ClearSelected()
+	})
+
+	var popup dialog.Dialog
+	sendButton := widget.NewButton("Send", func() {
+		c := current()
+		if _, err := putContact(nil, c); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		popup.Hide()
+		memo := c.memo()
+		if c.MemoType == "none" {
+			memo = memoSpec{Type: "none"}
+		}
+		showSendDialog(nil, sendParams{Recipient: strings.TrimSpace(c.Address), Asset: "XLM", Memo: memo.Value, MemoType: memo.Type})
+	})
+
+	content := container.NewVBox(
+		contactSelect,
+		widget.NewForm(
+			widget.NewFormItem("Name", nameEntry),
+			widget.NewFormItem("Address", addressEntry),
+			widget.NewFormItem("Memo Type", memoTypeSelect),
+			widget.NewFormItem("Memo", memoEntry),
+		),
+		container.NewGridWithColumns(3, saveButton, deleteButton, sendButton),
+	)
+	popup = dialog.NewCustom("Contacts", "Close", content, window)
+	popup.Resize(fyne.NewSize(scaled(420), popup.MinSize().Height))
+	popup.Show()
+}
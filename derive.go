@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/exp/crypto/derivation"
+	"github.com/stellar/go/keypair"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Maximum accounts listed at once from a phrase
+const maxDerivedAccounts = 20
+
+// What it takes to re-derive an account: its phrase, passphrase and SEP-5 index
+type recoveryPhrase = core.RecoveryPhrase
+
+// A fresh 24-word phrase along with its first account
+func newRecoveryPhrase() (recoveryPhrase, *keypair.Full, error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return recoveryPhrase{}, nil, err
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return recoveryPhrase{}, nil, err
+	}
+	accounts, err := deriveAccounts(mnemonic, "", 0, 1)
+	if err != nil {
+		return recoveryPhrase{}, nil, err
+	}
+	return recoveryPhrase{Mnemonic: mnemonic}, accounts[0], nil
+}
+
+// Parse a phrase typed by the user, checking its words and checksum
+func parseRecoveryPhrase(text string) (recoveryPhrase, *keypair.Full, error) {
+	mnemonic := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return recoveryPhrase{}, nil, fmt.Errorf("invalid recovery phrase: it should be 12 to 24 words from the BIP-39 word list")
+	}
+	accounts, err := deriveAccounts(mnemonic, "", 0, 1)
+	if err != nil {
+		return recoveryPhrase{}, nil, err
+	}
+	return recoveryPhrase{Mnemonic: mnemonic}, accounts[0], nil
+}
+
+// SEP-5 keypairs at m/44'/148'/i' for i in [start, start+count)
+func deriveAccounts(mnemonic, passphrase string, start, count uint32) ([]*keypair.Full, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recovery phrase: %v", err)
+	}
+
+	accounts := make([]*keypair.Full, 0, count)
+	for i := start; i < start+count; i++ {
+		key, err := derivation.DeriveForPath(fmt.Sprintf(derivation.StellarAccountPathFormat, i), seed)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving account %d: %v", i, err)
+		}
+		kp, err := keypair.FromRawSeed(key.RawSeed())
+		if err != nil {
+			return nil, fmt.Errorf("error deriving account %d: %v", i, err)
+		}
+		accounts = append(accounts, kp)
+	}
+	return accounts, nil
+}
+
+func showDeriveAccountsDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	phraseEntry := widget.NewMultiLineEntry()
+	phraseEntry.SetPlaceHolder("12 or 24 word recovery phrase")
+	phraseEntry.Wrapping = fyne.TextWrapWord
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder("Optional passphrase")
+	countEntry := widget.NewEntry()
+	countEntry.SetText("5")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Phrase", phraseEntry),
+		widget.NewFormItem("Passphrase", passphraseEntry),
+		widget.NewFormItem("Accounts", countEntry),
+	}
+
+	dialog.ShowForm("Accounts from Phrase", "Derive", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		count, err := strconv.ParseUint(strings.TrimSpace(countEntry.Text), 10, 32)
+		if err != nil || count == 0 || count > maxDerivedAccounts {
+			dialog.ShowError(fmt.Errorf("number of accounts must be between 1 and %d", maxDerivedAccounts), window)
+			return
+		}
+		accounts, err := deriveAccounts(phraseEntry.Text, passphraseEntry.Text, 0, uint32(count))
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		phrase := recoveryPhrase{Mnemonic: strings.Join(strings.Fields(phraseEntry.Text), " "), Passphrase: passphraseEntry.Text}
+		showDerivedAccounts(accounts, phrase)
+	}, window)
+}
+
+// Selector label for the derived account at index i
+func derivedAccountOption(i int, kp *keypair.Full, balance string) string {
+	return fmt.Sprintf("%d: %s…%s (%s)", i, kp.Address()[:6], kp.Address()[50:], balance)
+}
+
+// Index of the account a derivedAccountOption label is for
+func derivedAccountIndex(option string) (int, error) {
+	index, _, _ := strings.Cut(option, ":")
+	return strconv.Atoi(index)
+}
+
+// The options in updated for the same accounts as selected, which are labels
+// from an earlier derivedAccountOption
+func relabelSelection(selected, updated []string) []string {
+	var relabelled []string
+	for _, option := range selected {
+		if i, err := derivedAccountIndex(option); err == nil && i >= 0 && i < len(updated) {
+			relabelled = append(relabelled, updated[i])
+		}
+	}
+	return relabelled
+}
+
+// List derived accounts, filling in their balances in the background, and let
+// the user pick any number of them to add
+func showDerivedAccounts(accounts []*keypair.Full, phrase recoveryPhrase) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	options := make([]string, len(accounts))
+	for i, kp := range accounts {
+		options[i] = derivedAccountOption(i, kp, "checking…")
+	}
+	choice := widget.NewCheckGroup(options, nil)
+	content := container.NewVBox(widget.NewLabel("Balances on "+wallet.Network), choice)
+
+	network := wallet.Network
+	go func() {
+		updated := make([]string, len(accounts))
+		for i, kp := range accounts {
+			balance := "unfunded"
+			if account, err := newSession(network, kp.Address()).Account(); err == nil {
+				if xlm, ok := nativeBalance(account); ok {
+					balance = xlm + " XLM"
+				}
+			}
+			updated[i] = derivedAccountOption(i, kp, balance)
+		}
+
+		// Swap in the new labels and the selection carried over to them in
+		// one go; SetSelected refreshes the group
+		choice.Options = updated
+		choice.SetSelected(relabelSelection(choice.Selected, updated))
+	}()
+
+	dialog.ShowCustomConfirm("Derived Accounts", "Add Accounts", "Cancel", content, func(use bool) {
+		if !use || len(choice.Selected) == 0 {
+			return
+		}
+		var indexes []int
+		for _, option := range choice.Selected {
+			if i, err := derivedAccountIndex(option); err == nil {
+				indexes = append(indexes, i)
+			}
+		}
+		sort.Ints(indexes)
+
+		lines := make([]string, len(indexes))
+		for n, i := range indexes {
+			lines[n] = fmt.Sprintf("%d: %s", i, accounts[i].Address())
+		}
+		message := fmt.Sprintf("Add %d account(s) to this wallet and switch to account %d?\n%s",
+			len(indexes), indexes[0], strings.Join(lines, "\n"))
+		dialog.ShowConfirm("Add Accounts", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			for _, i := range indexes {
+				accountPhrase := phrase
+				accountPhrase.Index = uint32(i)
+				if err := addWalletAccount(accounts[i], &accountPhrase); err != nil {
+					window.SetContent(createMainUI())
+					dialog.ShowError(fmt.Errorf("error adding account %d: %v", i, err), window)
+					return
+				}
+			}
+			if err := activateWallet(store.Index(accounts[indexes[0]].Address())); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			window.SetContent(createMainUI())
+		}, window)
+	}, window)
+}
+
+// Show the current account's phrase after the wallet password is re-entered
+func showRevealPhraseDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if wallet.EncryptedRecovery == "" {
+		dialog.ShowInformation("Recovery Phrase", "This account was not created from a recovery phrase.\nBack up its secret seed instead.", window)
+		return
+	}
+
+	passwordEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		widget.NewFormItem("Password", passwordEntry),
+	}
+	dialog.ShowForm("Reveal Recovery Phrase", "Reveal", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		if !checkWalletPassword(passwordEntry.Text) {
+			dialog.ShowError(core.ErrWrongPassword, window)
+			return
+		}
+		plain, err := core.Open(walletKey, wallet.EncryptedRecovery, wallet.RecoveryNonce)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error decrypting recovery phrase: %v", err), window)
+			return
+		}
+		var phrase recoveryPhrase
+		if err := json.Unmarshal([]byte(plain), &phrase); err != nil {
+			dialog.ShowError(fmt.Errorf("corrupt recovery phrase: %v", err), window)
+			return
+		}
+		showRecoveryPhrase(phrase)
+	}, window)
+}
+
+// Numbered words of phrase, with what else is needed to restore the account
+func showRecoveryPhrase(phrase recoveryPhrase) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	words := strings.Fields(phrase.Mnemonic)
+	grid := container.NewGridWithColumns(3)
+	for i, word := range words {
+		grid.Add(widget.NewLabel(fmt.Sprintf("%d. %s", i+1, word)))
+	}
+
+	notes := fmt.Sprintf("Account index %d (m/44'/148'/%d').", phrase.Index, phrase.Index)
+	if phrase.Passphrase != "" {
+		notes += "\nThis account also needs the passphrase it was derived with."
+	}
+	notes += "\nWrite these words down in order and keep them offline."
+	info := widget.NewLabel(notes)
+	info.Wrapping = fyne.TextWrapWord
+
+	dialog.ShowCustom("Recovery Phrase", "Done", container.NewVBox(grid, info), window)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+)
+
+// SEP-5 test vector 1
+const sep5Mnemonic = "illness spike retreat truth genius clock brain pass fit cave bargain toe"
+
+func TestDeriveAccounts(t *testing.T) {
+	accounts, err := deriveAccounts(sep5Mnemonic, "", 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6",
+		"GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX",
+		"GAY5PRAHJ2HIYBYCLZXTHID6SPVELOOYH2LBPH3LD4RUMXUW3DOYTLXW",
+	}
+	for i, want := range expected {
+		if got := accounts[i].Address(); got != want {
+			t.Errorf("account %d = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestDerivedAccountIndex(t *testing.T) {
+	kp := keypair.MustRandom()
+	for _, i := range []int{0, 7, 19} {
+		for _, balance := range []string{"checking…", "unfunded", "12.5 XLM"} {
+			got, err := derivedAccountIndex(derivedAccountOption(i, kp, balance))
+			if err != nil || got != i {
+				t.Errorf("index of option %d with %q = %d, %v", i, balance, got, err)
+			}
+		}
+	}
+}
+
+func TestRelabelSelection(t *testing.T) {
+	accounts := []*keypair.Full{keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()}
+	option := func(i int, balance string) string { return derivedAccountOption(i, accounts[i], balance) }
+	updated := []string{option(0, "unfunded"), option(1, "5 XLM"), option(2, "unfunded")}
+
+	tests := []struct {
+		name     string
+		selected []string
+		expected []string
+	}{
+		{"none", nil, nil},
+		{"carried over", []string{option(2, "checking…"), option(0, "checking…")}, []string{updated[2], updated[0]}},
+		{"out of range", []string{"7: gone"}, nil},
+		{"unparseable", []string{"junk"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relabelSelection(tt.selected, updated); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("relabelSelection() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
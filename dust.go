@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+const defaultDustThreshold = "0.01"
+
+// A non-native balance small enough to clean up
+type dustBalance struct {
+	Asset   txnbuild.Asset
+	Balance string
+	Zero    bool
+	Blocked string // why it can't be cleaned up right now, if anything
+}
+
+// Credit balances strictly below threshold. Balances tied up in offers are
+// reported as blocked since neither trading nor removing the trustline works.
+func findDust(balances []horizon.Balance, threshold string) ([]dustBalance, error) {
+	limit, err := amount.ParseInt64(threshold)
+	if err != nil {
+		return nil, fmt.Errorf("invalid threshold %q: %v", threshold, err)
+	}
+
+	var dust []dustBalance
+	for _, balance := range balances {
+		if balance.Asset.Type == "native" || balance.Asset.Type == "liquidity_pool_shares" {
+			continue
+		}
+		stroops, err := amount.ParseInt64(balance.Balance)
+		if err != nil || stroops >= limit {
+			continue
+		}
+
+		entry := dustBalance{
+			Asset:   assetFromHorizon(balance.Asset.Type, balance.Asset.Code, balance.Asset.Issuer),
+			Balance: balance.Balance,
+			Zero:    stroops == 0,
+		}
+		if !isZeroAmount(balance.BuyingLiabilities) || !isZeroAmount(balance.SellingLiabilities) {
+			entry.Blocked = "has open offers"
+		}
+		dust = append(dust, entry)
+	}
+	return dust, nil
+}
+
+func isZeroAmount(s string) bool {
+	stroops, err := amount.ParseInt64(s)
+	return err == nil && stroops == 0
+}
+
+func dustThreshold() string {
+	if settings.DustThreshold == "" {
+		return defaultDustThreshold
+	}
+	return settings.DustThreshold
+}
+
+// Operations that sell a dust balance to XLM (when quoted) and drop its trustline
+func dustOps(dust dustBalance, quote *horizon.Path) ([]txnbuild.Operation, error) {
+	var ops []txnbuild.Operation
+	if !dust.Zero {
+		bps, err := parseSlippage(defaultSlippage(len(quote.Path)))
+		if err != nil {
+			return nil, err
+		}
+		destMin, err := slippageBound(quote.DestinationAmount, bps, true)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, &txnbuild.PathPaymentStrictSend{
+			SendAsset:   dust.Asset,
+			SendAmount:  dust.Balance,
+			Destination: wallet.PublicKey,
+			DestAsset:   txnbuild.NativeAsset{},
+			DestMin:     destMin,
+			Path:        pathAssets(*quote),
+		})
+	}
+	ops = append(ops, &txnbuild.ChangeTrust{
+		Line:  dust.Asset.MustToChangeTrustAsset(),
+		Limit: "0",
+	})
+	return ops, nil
+}
+
+func showDustDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetText(dustThreshold())
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Below", thresholdEntry),
+	}
+	dialog.ShowForm("Consolidate Dust", "Scan", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: wallet.PublicKey})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+			return
+		}
+		dust, err := findDust(account.Balances, thresholdEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		settings.DustThreshold = thresholdEntry.Text
+		if err := saveSettings(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+		}
+
+		if len(dust) == 0 {
+			dialog.ShowInformation("Consolidate Dust", "No balances below "+thresholdEntry.Text+".", window)
+			return
+		}
+		showDustReview(dust)
+	}, window)
+}
+
+// List the dust with a quote for each and let the user pick what to clean up
+func showDustReview(dust []dustBalance) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	list := container.NewVBox()
+	checks := make([]*widget.Check, len(dust))
+	quotes := make([]*horizon.Path, len(dust))
+	for i, d := range dust {
+		code := assetCode(d.Asset)
+		var label string
+		switch {
+		case d.Blocked != "":
+			label = fmt.Sprintf("%s %s: skipped, %s", d.Balance, code, d.Blocked)
+		case d.Zero:
+			label = fmt.Sprintf("%s: remove empty trustline", code)
+		default:
+			quote, err := bestStrictSendPath(d.Asset, d.Balance, txnbuild.NativeAsset{})
+			if err != nil {
+				label = fmt.Sprintf("%s %s: skipped, %v", d.Balance, code, err)
+				d.Blocked = err.Error()
+				break
+			}
+			quotes[i] = &quote
+			label = fmt.Sprintf("%s %s: sell for ~%s XLM and remove trustline", d.Balance, code, quote.DestinationAmount)
+		}
+
+		check := widget.NewCheck(label, nil)
+		if d.Blocked != "" {
+			check.Disable()
+		} else {
+			check.SetChecked(true)
+		}
+		checks[i] = check
+		list.Add(check)
+	}
+
+	info := widget.NewLabel(fmt.Sprintf("Each removed trustline frees %s XLM of reserve.", stroopsToXLM(baseReserve)))
+	info.Wrapping = fyne.TextWrapWord
+	list.Add(info)
+
+	dialog.ShowCustomConfirm("Review Dust", "Clean Up", "Cancel", list, func(confirm bool) {
+		if !confirm {
+			return
+		}
+
+		var ops []txnbuild.Operation
+		for i, d := range dust {
+			if !checks[i].Checked || checks[i].Disabled() {
+				continue
+			}
+			dOps, err := dustOps(d, quotes[i])
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s: %v", assetCode(d.Asset), err), window)
+				return
+			}
+			ops = append(ops, dOps...)
+		}
+		if len(ops) == 0 {
+			return
+		}
+
+		submitBatches(chunkOperations(ops, maxOpsPerTx), nil, func(hashes []string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Dust cleaned up in %d transaction(s).", len(hashes)), window)
+		}, nil)
+	}, window)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestFindDust(t *testing.T) {
+	credit := func(code, balance, selling string) horizon.Balance {
+		return horizon.Balance{
+			Balance:            balance,
+			BuyingLiabilities:  "0.0000000",
+			SellingLiabilities: selling,
+			Asset:              base.Asset{Type: "credit_alphanum4", Code: code, Issuer: testOther},
+		}
+	}
+	balances := []horizon.Balance{
+		{Balance: "0.0010000", Asset: base.Asset{Type: "native"}},
+		{Balance: "0.0000001", Asset: base.Asset{Type: "liquidity_pool_shares"}, LiquidityPoolId: "pool"},
+		credit("ZERO", "0.0000000", "0.0000000"),
+		credit("TINY", "0.0099999", "0.0000000"),
+		credit("EDGE", "0.0100000", "0.0000000"),
+		credit("BIG", "25.0000000", "0.0000000"),
+		credit("HELD", "0.0050000", "0.0050000"),
+	}
+	asset := func(code string) txnbuild.Asset { return txnbuild.CreditAsset{Code: code, Issuer: testOther} }
+
+	tests := []struct {
+		name      string
+		threshold string
+		want      []dustBalance
+	}{
+		{"default threshold", defaultDustThreshold, []dustBalance{
+			{Asset: asset("ZERO"), Balance: "0.0000000", Zero: true},
+			{Asset: asset("TINY"), Balance: "0.0099999"},
+			{Asset: asset("HELD"), Balance: "0.0050000", Blocked: "has open offers"},
+		}},
+		{"zero threshold", "0", nil},
+		{"raised threshold", "0.0100001", []dustBalance{
+			{Asset: asset("ZERO"), Balance: "0.0000000", Zero: true},
+			{Asset: asset("TINY"), Balance: "0.0099999"},
+			{Asset: asset("EDGE"), Balance: "0.0100000"},
+			{Asset: asset("HELD"), Balance: "0.0050000", Blocked: "has open offers"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findDust(balances, tt.threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dust = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := findDust(balances, "a little"); err == nil || !strings.Contains(err.Error(), "invalid threshold") {
+		t.Errorf("error = %v, want an invalid threshold error", err)
+	}
+}
+
+func TestDustThreshold(t *testing.T) {
+	saved := settings
+	t.Cleanup(func() { settings = saved })
+
+	settings.DustThreshold = ""
+	if got := dustThreshold(); got != defaultDustThreshold {
+		t.Errorf("unset threshold = %q, want %q", got, defaultDustThreshold)
+	}
+	settings.DustThreshold = "0.5"
+	if got := dustThreshold(); got != "0.5" {
+		t.Errorf("threshold = %q, want 0.5", got)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// Horizon error behind err, looking through any wrapping
+func horizonError(err error) *horizonclient.Error {
+	var herr *horizonclient.Error
+	if errors.As(err, &herr) {
+		return herr
+	}
+	var value horizonclient.Error
+	if errors.As(err, &value) {
+		return &value
+	}
+	return nil
+}
+
+// Transaction result codes of a failed submission, if Horizon returned any
+func resultCodes(err error) *horizon.TransactionResultCodes {
+	herr := horizonError(err)
+	if herr == nil {
+		return nil
+	}
+	codes, cerr := herr.ResultCodes()
+	if cerr != nil {
+		return nil
+	}
+	return codes
+}
+
+// Transaction level result code, preferring the inner transaction of a fee bump
+func transactionCode(codes *horizon.TransactionResultCodes) string {
+	if codes == nil {
+		return ""
+	}
+	if codes.InnerTransactionCode != "" {
+		return codes.InnerTransactionCode
+	}
+	return codes.TransactionCode
+}
+
+// Transaction codes worth explaining in plain words
+var transactionCodeMessages = map[string]string{
+	"tx_insufficient_balance":  "the fee would leave the account below its minimum reserve",
+	"tx_bad_auth":              "missing or invalid signature; the account may need more signers",
+	"tx_bad_auth_extra":        "the transaction has signatures it doesn't need",
+	"tx_bad_seq":               "the account's sequence number changed; try again",
+	"tx_insufficient_fee":      "the fee is too low for current network traffic",
+	"tx_too_early":             "the transaction is not valid yet",
+	"tx_too_late":              "the transaction expired before it reached the network",
+	"tx_no_source_account":     "the source account does not exist",
+	"tx_missing_operation":     "the transaction has no operations",
+	"tx_internal_error":        "the network had an internal error; try again",
+	"tx_fee_bump_inner_failed": "the inner transaction of the fee bump failed",
+}
+
+// Why Horizon couldn't be reached or couldn't answer, once retries ran out.
+// False for errors Horizon returned about the request itself.
+func connectionProblem(err error) (string, bool) {
+	if herr := horizonError(err); herr != nil {
+		switch status := herr.Problem.Status; {
+		case status == http.StatusTooManyRequests:
+			return "Horizon is rate limiting requests; wait a minute and try again", true
+		case status >= 500:
+			return fmt.Sprintf("Horizon server error (%d); the network may be having problems, try again later", status), true
+		}
+		return "", false
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "Horizon did not respond in time; check your connection and try again", true
+	}
+	return "", false
+}
+
+// Plain-English reason a submission failed, for showing to the user.
+// Falls back to Horizon's problem description, then to err itself.
+func explainHorizonError(err error) string {
+	if message, ok := connectionProblem(err); ok {
+		return message
+	}
+	herr := horizonError(err)
+	if herr == nil {
+		return err.Error()
+	}
+
+	if codes := resultCodes(err); codes != nil {
+		// The fee payer is charged even when the bumped transaction fails
+		prefix := "Transaction failed: "
+		if codes.TransactionCode == "tx_fee_bump_inner_failed" {
+			prefix = "Fee bump was charged but the inner transaction failed: "
+		}
+		code := transactionCode(codes)
+		if message, ok := transactionCodeMessages[code]; ok {
+			return prefix + message
+		}
+		if failures := operationFailures(codes); len(failures) > 0 {
+			messages := make([]string, len(failures))
+			for i, f := range failures {
+				messages[i] = f.Message
+			}
+			return prefix + strings.Join(messages, "; ")
+		}
+		if code != "" {
+			return prefix + code
+		}
+	}
+
+	if herr.Problem.Detail != "" {
+		return herr.Problem.Title + ": " + herr.Problem.Detail
+	}
+	return err.Error()
+}
+
+// Operation codes worth explaining in plain words
+var operationCodeMessages = map[string]string{
+	"op_underfunded":         "not enough funds available",
+	"op_low_reserve":         "would leave the account below its minimum reserve",
+	"op_no_destination":      "destination account does not exist",
+	"op_no_trust":            "destination has no trustline for this asset",
+	"op_src_no_trust":        "source has no trustline for this asset",
+	"op_not_authorized":      "issuer has not authorized this trustline",
+	"op_src_not_authorized":  "source is not authorized to hold this asset",
+	"op_line_full":           "destination trustline limit would be exceeded",
+	"op_no_issuer":           "asset issuer does not exist",
+	"op_too_few_offers":      "not enough offers on the path",
+	"op_under_dest_min":      "would receive less than the minimum",
+	"op_over_source_max":     "would send more than the maximum",
+	"op_cross_self":          "would cross one of your own offers",
+	"op_already_exists":      "account already exists",
+	"op_no_account":          "source account does not exist",
+	"op_bad_auth":            "missing or invalid signature for this operation",
+	"op_malformed":           "operation is invalid",
+	"op_invalid_limit":       "trustline limit is below the current balance",
+	"op_offer_not_found":     "offer no longer exists",
+	"op_does_not_exist":      "entry does not exist",
+	"op_has_sub_entries":     "account still has trustlines, offers, signers or data entries; remove them before merging",
+	"op_seqnum_too_far":      "account's sequence number is too high to merge it yet",
+	"op_immutable_set":       "account flags are immutable and prevent merging",
+	"op_dest_full":           "destination would exceed its maximum XLM balance",
+	"op_name_not_found":      "data entry does not exist",
+	"op_invalid_name":        "data entry key is invalid",
+	"op_sell_no_trust":       "no trustline for the asset being sold",
+	"op_buy_no_trust":        "no trustline for the asset being bought",
+	"op_sell_not_authorized": "not authorized to sell this asset",
+	"op_buy_not_authorized":  "not authorized to buy this asset",
+	"op_sell_no_issuer":      "issuer of the asset being sold does not exist",
+	"op_buy_no_issuer":       "issuer of the asset being bought does not exist",
+	"op_not_found":           "offer no longer exists",
+	"op_cannot_claim":        "the claim conditions are not met yet",
+	"op_is_sponsor":          "account is sponsoring other entries and cannot be merged",
+}
+
+// A failed operation within a transaction, by position
+type opFailure struct {
+	Index   int
+	Code    string
+	Message string
+}
+
+// Operations that failed, by index into the submitted operations. Successful
+// operations are skipped; when the whole transaction failed before applying
+// any operation there are no per-operation codes and the result is empty.
+func operationFailures(codes *horizon.TransactionResultCodes) []opFailure {
+	if codes == nil {
+		return nil
+	}
+	var failures []opFailure
+	for i, code := range codes.OperationCodes {
+		if code == "op_success" {
+			continue
+		}
+		message, ok := operationCodeMessages[code]
+		if !ok {
+			message = code
+		}
+		failures = append(failures, opFailure{Index: i, Code: code, Message: message})
+	}
+	return failures
+}
+
+// Whether Horizon answered that the requested resource doesn't exist
+func isNotFound(err error) bool {
+	herr := horizonError(err)
+	return herr != nil && herr.Problem.Status == http.StatusNotFound
+}
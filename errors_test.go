@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// Error Horizon returns for a rejected submission with the given result codes
+func submissionError(txCode string, opCodes ...string) error {
+	codes := map[string]interface{}{"transaction": txCode}
+	if len(opCodes) > 0 {
+		codes["operations"] = opCodes
+	}
+	return &horizonclient.Error{Problem: problem.P{
+		Status: 400,
+		Title:  "Transaction Failed",
+		Detail: "The transaction failed when submitted to the stellar network.",
+		Extras: map[string]interface{}{"result_codes": codes},
+	}}
+}
+
+func TestExplainHorizonError(t *testing.T) {
+	feeBump := &horizonclient.Error{Problem: problem.P{
+		Status: 400,
+		Extras: map[string]interface{}{"result_codes": map[string]interface{}{
+			"transaction":       "tx_fee_bump_inner_failed",
+			"inner_transaction": "tx_bad_seq",
+		}},
+	}}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"transaction code", submissionError("tx_bad_auth"), "Transaction failed: missing or invalid signature; the account may need more signers"},
+		{"operation codes", submissionError("tx_failed", "op_success", "op_underfunded", "op_no_trust"),
+			"Transaction failed: not enough funds available; destination has no trustline for this asset"},
+		{"unknown operation code", submissionError("tx_failed", "op_something_new"), "Transaction failed: op_something_new"},
+		{"unknown transaction code", submissionError("tx_new_code"), "Transaction failed: tx_new_code"},
+		{"fee bump inner failure", feeBump, "Fee bump was charged but the inner transaction failed: the account's sequence number changed; try again"},
+		{"wrapped", fmt.Errorf("error submitting transaction: %w", submissionError("tx_too_late")),
+			"Transaction failed: the transaction expired before it reached the network"},
+		{"problem detail", &horizonclient.Error{Problem: problem.P{Status: 400, Title: "Bad Request", Detail: "invalid cursor"}},
+			"Bad Request: invalid cursor"},
+		{"rate limited", &horizonclient.Error{Problem: problem.P{Status: 429}}, "Horizon is rate limiting requests; wait a minute and try again"},
+		{"server error", &horizonclient.Error{Problem: problem.P{Status: 503}},
+			"Horizon server error (503); the network may be having problems, try again later"},
+		{"timeout", context.DeadlineExceeded, "Horizon did not respond in time; check your connection and try again"},
+		{"plain error", errors.New("source account does not exist"), "source account does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := explainHorizonError(tt.err); got != tt.expected {
+				t.Errorf("explainHorizonError() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOperationFailures(t *testing.T) {
+	codes := resultCodes(submissionError("tx_failed", "op_success", "op_low_reserve", "op_unheard_of"))
+	failures := operationFailures(codes)
+	expected := []opFailure{
+		{Index: 1, Code: "op_low_reserve", Message: "would leave the account below its minimum reserve"},
+		{Index: 2, Code: "op_unheard_of", Message: "op_unheard_of"},
+	}
+	if len(failures) != len(expected) {
+		t.Fatalf("operationFailures() = %v, want %v", failures, expected)
+	}
+	for i := range expected {
+		if failures[i] != expected[i] {
+			t.Errorf("failure %d = %+v, want %+v", i, failures[i], expected[i])
+		}
+	}
+	if failures := operationFailures(resultCodes(submissionError("tx_bad_seq"))); len(failures) != 0 {
+		t.Errorf("operationFailures() without operation codes = %v", failures)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+const explorerBase = "https://stellar.expert/explorer/"
+
+// stellar.expert page for a "tx" hash or an "account" on network
+func explorerURL(network, kind, id string) (*url.URL, error) {
+	switch network {
+	case "public", "testnet":
+	default:
+		return nil, fmt.Errorf("no explorer for network %q", network)
+	}
+	return url.Parse(explorerBase + network + "/" + kind + "/" + url.PathEscape(id))
+}
+
+// Open the explorer page for id on the wallet's network in the default browser
+func openInExplorer(kind, id string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	u, err := explorerURL(wallet.Network, kind, id)
+	if err == nil {
+		err = fyne.CurrentApp().OpenURL(u)
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error opening explorer: %v", err), window)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/clients/federation"
+	proto "github.com/stellar/go/protocols/federation"
+	"github.com/stellar/go/strkey"
+)
+
+// Account and memo a federation address (name*domain) stands for
+type federatedRecipient struct {
+	Address   string
+	AccountID string
+	Memo      memoSpec // Type "none" when the server doesn't require one
+}
+
+func isFederationAddress(s string) bool {
+	return strings.Contains(strings.TrimSpace(s), "*")
+}
+
+func federationClient(networkName string) *federation.Client {
+	return &federation.Client{
+		HTTP:        federation.DefaultPublicNetClient.HTTP,
+		Horizon:     launchClient(networkName),
+		StellarTOML: federation.DefaultPublicNetClient.StellarTOML,
+	}
+}
+
+// Memo a federation response asks payments to carry. SEP-2 sends hash and
+// return memos base64 encoded, while memoSpec holds them as hex.
+func federationMemo(resp *proto.NameResponse) (memoSpec, error) {
+	if resp.MemoType == "" {
+		return memoSpec{Type: "none"}, nil
+	}
+	memo := memoSpec{Type: resp.MemoType, Value: resp.Memo.Value}
+	if memo.Type == "hash" || memo.Type == "return" {
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(memo.Value))
+		if err != nil {
+			return memoSpec{}, fmt.Errorf("federation server returned an invalid %s memo: must be base64", memo.Type)
+		}
+		memo.Value = hex.EncodeToString(raw)
+	}
+	if _, err := buildMemo(memo); err != nil {
+		return memoSpec{}, fmt.Errorf("federation server returned an invalid memo: %v", err)
+	}
+	return memo, nil
+}
+
+// Resolve a name*domain address through the domain's SEP-2 federation server
+func resolveFederationAddress(address string) (federatedRecipient, error) {
+	address = strings.TrimSpace(address)
+	resp, err := federationClient(wallet.Network).LookupByAddress(address)
+	if err != nil {
+		return federatedRecipient{}, fmt.Errorf("could not resolve %s: %v", address, err)
+	}
+	if !strkey.IsValidEd25519PublicKey(resp.AccountID) {
+		return federatedRecipient{}, fmt.Errorf("%s resolved to an invalid account %q", address, resp.AccountID)
+	}
+	memo, err := federationMemo(resp)
+	if err != nil {
+		return federatedRecipient{}, err
+	}
+	return federatedRecipient{Address: address, AccountID: resp.AccountID, Memo: memo}, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	proto "github.com/stellar/go/protocols/federation"
+)
+
+func TestFederationMemo(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	hashBase64 := "q6urq6urq6urq6urq6urq6urq6urq6urq6urq6urq6s="
+
+	tests := []struct {
+		name     string
+		memoType string
+		value    string
+		want     memoSpec
+		wantErr  bool
+	}{
+		{"no memo", "", "", memoSpec{Type: "none"}, false},
+		{"text", "text", "invoice 42", memoSpec{Type: "text", Value: "invoice 42"}, false},
+		{"id", "id", "123", memoSpec{Type: "id", Value: "123"}, false},
+		{"hash is base64", "hash", hashBase64, memoSpec{Type: "hash", Value: hash}, false},
+		{"return is base64", "return", hashBase64, memoSpec{Type: "return", Value: hash}, false},
+		{"hex hash rejected", "hash", hash, memoSpec{}, true},
+		{"short hash", "hash", "q6ur", memoSpec{}, true},
+		{"bad id", "id", "abc", memoSpec{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &proto.NameResponse{MemoType: tt.memoType, Memo: proto.Memo{Value: tt.value}}
+			got, err := federationMemo(resp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("federationMemo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("federationMemo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Read-only view of the transaction a fee bump pays for. None of it can be
+// changed by the fee payer.
+type innerTxSummary struct {
+	Source     string
+	Sequence   int64
+	Memo       string
+	Operations []string
+	Signatures int
+}
+
+func innerTransactionSummary(feeBump *txnbuild.FeeBumpTransaction) innerTxSummary {
+	inner := feeBump.InnerTransaction()
+	source := inner.SourceAccount()
+
+	summary := innerTxSummary{
+		Source:     source.AccountID,
+		Sequence:   inner.SequenceNumber(),
+		Memo:       describeMemo(inner.Memo()),
+		Signatures: len(inner.Signatures()),
+	}
+	for _, op := range inner.Operations() {
+		line := describeOperation(op)
+		opSource := op.GetSourceAccount()
+		if opSource == "" {
+			opSource = source.AccountID
+		}
+		summary.Operations = append(summary.Operations, fmt.Sprintf("%s (source %s)", line, opSource))
+	}
+	return summary
+}
+
+func innerSummaryText(summary innerTxSummary) string {
+	lines := []string{
+		fmt.Sprintf("Source: %s", summary.Source),
+		fmt.Sprintf("Sequence: %d", summary.Sequence),
+		fmt.Sprintf("Memo: %s", summary.Memo),
+		fmt.Sprintf("Signatures: %d", summary.Signatures),
+		"Operations:",
+	}
+	for i, op := range summary.Operations {
+		lines = append(lines, fmt.Sprintf("  %d. %s", i+1, op))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// The last transaction signed for submission, kept so it can be fee bumped if
+// it gets stuck
+var (
+	lastSubmittedMu   sync.Mutex
+	lastSubmittedHash string
+	lastSubmittedXDR  string
+)
+
+func rememberSubmitted(tx *txnbuild.Transaction, passphrase string) {
+	hash, err := tx.HashHex(passphrase)
+	if err != nil {
+		return
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		return
+	}
+	lastSubmittedMu.Lock()
+	lastSubmittedHash, lastSubmittedXDR = hash, envelope
+	lastSubmittedMu.Unlock()
+}
+
+func lastSubmitted() (string, string) {
+	lastSubmittedMu.Lock()
+	defer lastSubmittedMu.Unlock()
+	return lastSubmittedHash, lastSubmittedXDR
+}
+
+func isTransactionHash(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// Envelope of the transaction to bump, given as XDR or as a hash. Only the
+// last submitted transaction can be found by hash: one Horizon knows about is
+// already in a ledger and can't be bumped.
+func envelopeToBump(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if !isTransactionHash(input) {
+		return input, nil
+	}
+	if hash, envelope := lastSubmitted(); strings.EqualFold(hash, input) {
+		return envelope, nil
+	}
+	if tx, err := client.TransactionDetail(strings.ToLower(input)); err == nil {
+		return "", fmt.Errorf("transaction is already in ledger %d and can't be bumped", tx.Ledger)
+	}
+	return "", fmt.Errorf("transaction %s is not known here; paste its XDR instead", shortAddress(input))
+}
+
+// Wrap a transaction in a fee bump paid by the wallet account
+func buildFeeBump(inner *txnbuild.Transaction, baseFee int64) (*txnbuild.FeeBumpTransaction, error) {
+	return txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: wallet.PublicKey,
+		BaseFee:    baseFee,
+	})
+}
+
+func showFeeBumpDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	xdrEntry := widget.NewMultiLineEntry()
+	xdrEntry.SetPlaceHolder("Signed transaction XDR (base64) or hash")
+	xdrEntry.Wrapping = fyne.TextWrapBreak
+	feeEntry := widget.NewEntry()
+	feeEntry.SetText(strconv.FormatInt(networkBaseFee()*10, 10))
+	lastButton := widget.NewButton("Use Last Submitted", func() {
+		if _, envelope := lastSubmitted(); envelope != "" {
+			xdrEntry.SetText(envelope)
+		}
+	})
+	if _, envelope := lastSubmitted(); envelope == "" {
+		lastButton.Disable()
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Transaction", xdrEntry),
+		widget.NewFormItem("", lastButton),
+		widget.NewFormItem("Base Fee (stroops)", feeEntry),
+	}
+
+	dialog.ShowForm("Fee Bump", "Review", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		envelope, err := envelopeToBump(xdrEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		gtx, err := txnbuild.TransactionFromXDR(envelope)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid transaction XDR: %v", err), window)
+			return
+		}
+		inner, ok := gtx.Transaction()
+		if !ok {
+			// Re-bumping an existing fee bump wraps its inner transaction
+			existing, _ := gtx.FeeBump()
+			inner = existing.InnerTransaction()
+		}
+
+		baseFee, err := strconv.ParseInt(strings.TrimSpace(feeEntry.Text), 10, 64)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid base fee: %v", err), window)
+			return
+		}
+
+		feeBump, err := buildFeeBump(inner, baseFee)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error building fee bump: %v", err), window)
+			return
+		}
+		confirmFeeBump(feeBump)
+	}, window)
+}
+
+// Show exactly what the inner transaction does before paying for it
+func confirmFeeBump(feeBump *txnbuild.FeeBumpTransaction) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	summary := innerTransactionSummary(feeBump)
+	text := fmt.Sprintf("You will pay up to %s XLM in fees for this transaction.\nIts contents cannot be changed.\n\n%s",
+		stroopsToXLM(feeBump.MaxFee()), innerSummaryText(summary))
+	if summary.Signatures == 0 {
+		text = "Warning: the inner transaction is not signed and will be rejected.\n\n" + text
+	}
+
+	grid := widget.NewTextGrid()
+	grid.SetText(text)
+
+	dialog.ShowCustomConfirm("Confirm Fee Bump", "Sign & Submit", "Cancel", container.NewScroll(grid), func(ok bool) {
+		if !ok {
+			return
+		}
+
+		gtx := txnbuild.NewGenericTransactionWithFeeBumpTransaction(feeBump)
+		authorizeSpend(envelopeOutgoingXLM(gtx, wallet.PublicKey), func(approved int64) {
+			var (
+				hash string
+				err  error
+			)
+			withProgress("Submitting fee bump...", func() {
+				hash, err = signAndSubmitGeneric(client, gtx, approved)
+			}, func() {
+				if err != nil {
+					dialog.ShowError(errors.New(explainHorizonError(err)), window)
+					return
+				}
+				dialog.ShowInformation("Success", fmt.Sprintf("Fee bump submitted! Hash: %s", hash), window)
+			})
+		})
+	}, window)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestInnerTransactionSummary(t *testing.T) {
+	source := txnbuild.NewSimpleAccount(testWallet, 41)
+	inner, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &source,
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Memo:                 txnbuild.MemoID(7),
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: testOther, Amount: "1", Asset: txnbuild.NativeAsset{}},
+			&txnbuild.Payment{Destination: testWallet, Amount: "2", Asset: txnbuild.NativeAsset{}, SourceAccount: testOther},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, err = inner.Sign(network.TestNetworkPassphrase, keypair.MustRandom(), keypair.MustRandom())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feeBump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: testOther,
+		BaseFee:    1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := innerTransactionSummary(feeBump)
+	want := innerTxSummary{
+		Source:   testWallet,
+		Sequence: 42,
+		Memo:     "id 7",
+		Operations: []string{
+			"Pay 1 XLM to " + testOther + " (source " + testWallet + ")",
+			"Pay 2 XLM to " + testWallet + " (source " + testOther + ")",
+		},
+		Signatures: 2,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %+v\nwant %+v", got, want)
+	}
+
+	text := innerSummaryText(got)
+	wantText := "Source: " + testWallet + "\nSequence: 42\nMemo: id 7\nSignatures: 2\nOperations:\n" +
+		"  1. Pay 1 XLM to " + testOther + " (source " + testWallet + ")\n" +
+		"  2. Pay 2 XLM to " + testWallet + " (source " + testOther + ")"
+	if text != wantText {
+		t.Errorf("text = %q\nwant %q", text, wantText)
+	}
+}
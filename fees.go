@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Highest base fee the wallet will suggest, in stroops (0.01 XLM per operation)
+const maxBaseFee = 100000
+
+// Format a stroop amount as XLM (1 XLM = 10,000,000 stroops)
+func stroopsToXLM(stroops int64) string {
+	return amount.StringFromInt64(stroops)
+}
+
+// Check a user-entered amount is a positive number with at most 7 decimal
+// places (one stroop), inspecting the digits rather than going through a float
+func validateAmount(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return fmt.Errorf("amount is required")
+	}
+	if strings.HasPrefix(s, "-") {
+		return fmt.Errorf("amount must be positive")
+	}
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return fmt.Errorf("amount %q is not a number", s)
+	}
+	if len(fraction) > 7 {
+		return fmt.Errorf("amount exceeds 7 decimal places")
+	}
+	stroops, err := amount.ParseInt64(s)
+	if err != nil {
+		return fmt.Errorf("amount %s is too large", s)
+	}
+	if stroops == 0 {
+		return fmt.Errorf("amount must be greater than zero")
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Maximum fees for a transaction, in stroops
+type costEstimate struct {
+	InnerFee int64 // operations * base fee
+	Overhead int64 // extra charged by a fee bump on top of the inner fee
+	Total    int64
+}
+
+// Estimate the most a transaction can cost. A fee bump counts as one extra
+// operation at its own base fee, which must be at least the inner base fee, and
+// its fee replaces the inner transaction's rather than adding to it.
+func estimateTransactionCost(innerOps int, baseFee int64, feeBump bool, bumpBaseFee int64) (costEstimate, error) {
+	if innerOps <= 0 || innerOps > maxOpsPerTx {
+		return costEstimate{}, fmt.Errorf("operation count must be between 1 and %d", maxOpsPerTx)
+	}
+	if baseFee < txnbuild.MinBaseFee {
+		return costEstimate{}, fmt.Errorf("base fee must be at least %d stroops", txnbuild.MinBaseFee)
+	}
+
+	// The inner fee is a 32-bit field of the transaction
+	if baseFee > math.MaxUint32/int64(innerOps) {
+		return costEstimate{}, fmt.Errorf("fee for %d operations would exceed %d stroops", innerOps, uint32(math.MaxUint32))
+	}
+	inner := int64(innerOps) * baseFee
+	if !feeBump {
+		return costEstimate{InnerFee: inner, Total: inner}, nil
+	}
+
+	if bumpBaseFee < baseFee {
+		return costEstimate{}, fmt.Errorf("fee bump base fee must be at least the inner base fee (%d stroops)", baseFee)
+	}
+	if bumpBaseFee > math.MaxInt64/int64(innerOps+1) {
+		return costEstimate{}, fmt.Errorf("fee bump base fee %d stroops is too large", bumpBaseFee)
+	}
+	total := int64(innerOps+1) * bumpBaseFee
+	return costEstimate{InnerFee: inner, Overhead: total - inner, Total: total}, nil
+}
+
+func costEstimateText(c costEstimate) string {
+	text := fmt.Sprintf("Operations: %s XLM", stroopsToXLM(c.InnerFee))
+	if c.Overhead > 0 {
+		text += fmt.Sprintf("\nFee bump overhead: %s XLM", stroopsToXLM(c.Overhead))
+	}
+	return text + fmt.Sprintf("\nTotal: %s XLM", stroopsToXLM(c.Total))
+}
+
+func showCostEstimator() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	opsEntry := widget.NewEntry()
+	opsEntry.SetText("1")
+	baseFeeEntry := widget.NewEntry()
+	baseFeeEntry.SetText(strconv.Itoa(txnbuild.MinBaseFee))
+	bumpFeeEntry := widget.NewEntry()
+	bumpFeeEntry.SetText(strconv.Itoa(txnbuild.MinBaseFee * 10))
+	bumpFeeEntry.Disable()
+	resultLabel := widget.NewLabel("")
+
+	bumpCheck := widget.NewCheck("Fee bump", nil)
+
+	update := func() {
+		ops, err := strconv.Atoi(strings.TrimSpace(opsEntry.Text))
+		if err != nil {
+			resultLabel.SetText("invalid operation count")
+			return
+		}
+		baseFee, err := strconv.ParseInt(strings.TrimSpace(baseFeeEntry.Text), 10, 64)
+		if err != nil {
+			resultLabel.SetText("invalid base fee")
+			return
+		}
+		var bumpFee int64
+		if bumpCheck.Checked {
+			bumpFee, err = strconv.ParseInt(strings.TrimSpace(bumpFeeEntry.Text), 10, 64)
+			if err != nil {
+				resultLabel.SetText("invalid fee bump base fee")
+				return
+			}
+		}
+
+		estimate, err := estimateTransactionCost(ops, baseFee, bumpCheck.Checked, bumpFee)
+		if err != nil {
+			resultLabel.SetText(err.Error())
+			return
+		}
+		resultLabel.SetText(costEstimateText(estimate))
+	}
+
+	bumpCheck.OnChanged = func(checked bool) {
+		if checked {
+			bumpFeeEntry.Enable()
+		} else {
+			bumpFeeEntry.Disable()
+		}
+		update()
+	}
+	opsEntry.OnChanged = func(string) { update() }
+	baseFeeEntry.OnChanged = func(string) { update() }
+	bumpFeeEntry.OnChanged = func(string) { update() }
+	update()
+
+	form := widget.NewForm(
+		widget.NewFormItem("Operations", opsEntry),
+		widget.NewFormItem("Base Fee (stroops)", baseFeeEntry),
+		widget.NewFormItem("", bumpCheck),
+		widget.NewFormItem("Bump Base Fee", bumpFeeEntry),
+		widget.NewFormItem("Cost", resultLabel),
+	)
+	dialog.ShowCustom("Cost Estimator", "Close", form, window)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStroopsToXLM(t *testing.T) {
+	tests := []struct {
+		stroops  int64
+		expected string
+	}{
+		{0, "0.0000000"},
+		{1, "0.0000001"},
+		{10000000, "1.0000000"},
+		{100, "0.0000100"},
+	}
+
+	for _, tt := range tests {
+		if got := stroopsToXLM(tt.stroops); got != tt.expected {
+			t.Errorf("stroopsToXLM(%d) = %q, want %q", tt.stroops, got, tt.expected)
+		}
+	}
+}
+
+func TestValidateAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		valid  bool
+	}{
+		{"1", true},
+		{" 10.5 ", true},
+		{"0.0000001", true},
+		{".5", true},
+		{"5.", true},
+		{"922337203685.4775807", true},
+		{"", false},
+		{"   ", false},
+		{"-1", false},
+		{"0", false},
+		{"0.0000000", false},
+		{"0.00000001", false},
+		{"1e3", false},
+		{"1,5", false},
+		{".", false},
+		{"1.2.3", false},
+		{"abc", false},
+		{"922337203685.4775808", false},
+	}
+
+	for _, tt := range tests {
+		err := validateAmount(tt.amount)
+		if (err == nil) != tt.valid {
+			t.Errorf("validateAmount(%q) = %v, want valid %v", tt.amount, err, tt.valid)
+		}
+	}
+}
+
+func TestEstimateTransactionCost(t *testing.T) {
+	tests := []struct {
+		name        string
+		ops         int
+		baseFee     int64
+		feeBump     bool
+		bumpBaseFee int64
+		expected    costEstimate
+		wantErr     bool
+	}{
+		{"one operation", 1, 100, false, 0, costEstimate{InnerFee: 100, Total: 100}, false},
+		{"several operations", 5, 100, false, 0, costEstimate{InnerFee: 500, Total: 500}, false},
+		{"raised base fee", 3, 1000, false, 0, costEstimate{InnerFee: 3000, Total: 3000}, false},
+		{"fee bump pays for one more operation", 2, 100, true, 100, costEstimate{InnerFee: 200, Overhead: 100, Total: 300}, false},
+		{"fee bump at a higher rate", 2, 100, true, 500, costEstimate{InnerFee: 200, Overhead: 1300, Total: 1500}, false},
+		{"largest inner fee", 1, math.MaxUint32, false, 0, costEstimate{InnerFee: math.MaxUint32, Total: math.MaxUint32}, false},
+		{"no operations", 0, 100, false, 0, costEstimate{}, true},
+		{"too many operations", maxOpsPerTx + 1, 100, false, 0, costEstimate{}, true},
+		{"base fee below minimum", 1, 99, false, 0, costEstimate{}, true},
+		{"fee bump below inner rate", 1, 200, true, 100, costEstimate{}, true},
+		{"inner fee overflows", 2, math.MaxUint32, false, 0, costEstimate{}, true},
+		{"inner fee overflows int64", maxOpsPerTx, math.MaxInt64, false, 0, costEstimate{}, true},
+		{"fee bump overflows", 1, 100, true, math.MaxInt64, costEstimate{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := estimateTransactionCost(tt.ops, tt.baseFee, tt.feeBump, tt.bumpBaseFee)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("estimateTransactionCost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("estimateTransactionCost() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Per-operation fee figures in stroops, taken from what recent transactions bid
+type feeSummary struct {
+	Min  int64
+	Mode int64
+	P50  int64
+	P90  int64
+}
+
+var (
+	feeStatsMu sync.Mutex
+	latestFees *feeSummary
+)
+
+// Summarise a fee_stats response using the max_fee distribution, i.e. what
+// other senders are currently willing to pay per operation
+func parseFeeStats(stats horizon.FeeStats) feeSummary {
+	return feeSummary{
+		Min:  stats.MaxFee.Min,
+		Mode: stats.MaxFee.Mode,
+		P50:  stats.MaxFee.P50,
+		P90:  stats.MaxFee.P90,
+	}
+}
+
+func clampBaseFee(fee int64) int64 {
+	if fee < txnbuild.MinBaseFee {
+		return txnbuild.MinBaseFee
+	}
+	if fee > maxBaseFee {
+		return maxBaseFee
+	}
+	return fee
+}
+
+// Base fee likely to get a transaction into the next few ledgers
+func suggestedBaseFee(fees feeSummary) int64 {
+	return clampBaseFee(fees.P50)
+}
+
+// Base fee to offer for a new transaction: the suggestion from the fee stats
+// last fetched by the poller, or the minimum if there are none yet. Never
+// goes to the network, so it's safe to call while building a form.
+func networkBaseFee() int64 {
+	fees, ok := currentFees()
+	if !ok {
+		return txnbuild.MinBaseFee
+	}
+	return suggestedBaseFee(fees)
+}
+
+func feeSummaryText(fees feeSummary) string {
+	return fmt.Sprintf("Fees (stroops): min %d · mode %d · p50 %d · p90 %d",
+		fees.Min, fees.Mode, fees.P50, fees.P90)
+}
+
+// Most recently fetched fee stats, if any
+func currentFees() (feeSummary, bool) {
+	feeStatsMu.Lock()
+	defer feeStatsMu.Unlock()
+	if latestFees == nil {
+		return feeSummary{}, false
+	}
+	return *latestFees, true
+}
+
+func refreshFeeStats() (feeSummary, error) {
+	stats, err := client.FeeStats()
+	if err != nil {
+		return feeSummary{}, err
+	}
+
+	fees := parseFeeStats(stats)
+	feeStatsMu.Lock()
+	latestFees = &fees
+	feeStatsMu.Unlock()
+	return fees, nil
+}
+
+// Forget fee stats fetched from another network or server
+func resetFeeStats() {
+	feeStatsMu.Lock()
+	latestFees = nil
+	feeStatsMu.Unlock()
+}
+
+// Update a label with fresh network fee stats, hiding it when the server
+// doesn't offer them
+func showFeeStats(label *widget.Label) {
+	if !featureEnabled(featureFeeStats) {
+		label.Hide()
+	} else if fees, err := refreshFeeStats(); err != nil {
+		label.SetText("Fees unavailable")
+		label.Show()
+	} else {
+		label.SetText(feeSummaryText(fees))
+		label.Show()
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestSuggestedBaseFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		p50      int64
+		expected int64
+	}{
+		{"below minimum", 10, txnbuild.MinBaseFee},
+		{"typical", 250, 250},
+		{"above maximum", maxBaseFee + 1, maxBaseFee},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestedBaseFee(feeSummary{P50: tt.p50}); got != tt.expected {
+				t.Errorf("suggestedBaseFee() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResetFeeStats(t *testing.T) {
+	feeStatsMu.Lock()
+	latestFees = &feeSummary{P50: 500}
+	feeStatsMu.Unlock()
+
+	resetFeeStats()
+	if _, ok := currentFees(); ok {
+		t.Error("fee stats survived a reset")
+	}
+}
+
+func TestNetworkBaseFee(t *testing.T) {
+	defer resetFeeStats()
+
+	resetFeeStats()
+	if got := networkBaseFee(); got != txnbuild.MinBaseFee {
+		t.Errorf("networkBaseFee() without stats = %d, want %d", got, txnbuild.MinBaseFee)
+	}
+
+	feeStatsMu.Lock()
+	latestFees = &feeSummary{P50: 500}
+	feeStatsMu.Unlock()
+	if got := networkBaseFee(); got != 500 {
+		t.Errorf("networkBaseFee() with stats = %d, want 500", got)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	priceFeedURL = "https://api.coingecko.com/api/v3/simple/price"
+	priceTTL     = time.Minute
+)
+
+// Currencies the fiat value can be shown in, with their symbols
+var fiatSymbols = map[string]string{"usd": "$", "eur": "€"}
+
+var (
+	priceMu    sync.Mutex
+	priceCache = map[string]cachedPrice{}
+
+	// Shown next to the balance by the main window; nil until it is built
+	fiatLabel *widget.Label
+)
+
+type cachedPrice struct {
+	Price   float64
+	Fetched time.Time
+}
+
+func fiatCurrency() string {
+	return fiatCurrencyOf(settings)
+}
+
+func fiatCurrencyOf(s Settings) string {
+	if _, ok := fiatSymbols[s.FiatCurrency]; ok {
+		return s.FiatCurrency
+	}
+	return "usd"
+}
+
+// The last price fetched for currency, however old, without going to the network
+func lastXLMPrice(currency string) (float64, bool) {
+	priceMu.Lock()
+	defer priceMu.Unlock()
+	cached, ok := priceCache[currency]
+	return cached.Price, ok
+}
+
+// Price of one XLM in the configured fiat currency, reusing a price fetched
+// within the last minute
+func fetchXLMPrice(ctx context.Context) (float64, error) {
+	currency := fiatCurrency()
+
+	priceMu.Lock()
+	cached, ok := priceCache[currency]
+	priceMu.Unlock()
+	if ok && time.Since(cached.Fetched) < priceTTL {
+		return cached.Price, nil
+	}
+
+	query := url.Values{"ids": {"stellar"}, "vs_currencies": {currency}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, priceFeedURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("price feed returned %s", resp.Status)
+	}
+
+	var prices map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return 0, fmt.Errorf("invalid price feed response: %v", err)
+	}
+	price, ok := prices["stellar"][currency]
+	if !ok || price <= 0 {
+		return 0, fmt.Errorf("price feed has no XLM/%s price", strings.ToUpper(currency))
+	}
+
+	priceMu.Lock()
+	priceCache[currency] = cachedPrice{Price: price, Fetched: time.Now()}
+	priceMu.Unlock()
+	return price, nil
+}
+
+// "≈ $12.34" for balance XLM at price
+func fiatValueText(balance string, price float64, currency string) (string, error) {
+	xlm, err := strconv.ParseFloat(balance, 64)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("≈ %s%.2f", fiatSymbols[currency], xlm*price), nil
+}
+
+// Show the fiat value of balance XLM, hiding the label if no price is
+// available or the lumens are testnet ones with no value
+func refreshFiatValue(balance string) {
+	label := fiatLabel
+	if label == nil {
+		return
+	}
+	if wallet.Network != "public" {
+		label.Hide()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	price, err := fetchXLMPrice(ctx)
+	if err != nil {
+		label.Hide()
+		return
+	}
+	text, err := fiatValueText(balance, price, fiatCurrency())
+	if err != nil {
+		label.Hide()
+		return
+	}
+	label.SetText(text)
+	label.Show()
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Whether a single account flag can be toggled, and why not if it can't
+type flagChange struct {
+	Allowed bool
+	Reason  string
+}
+
+var accountFlagNames = map[txnbuild.AccountFlag]string{
+	txnbuild.AuthRequired:        "Auth Required",
+	txnbuild.AuthRevocable:       "Auth Revocable",
+	txnbuild.AuthImmutable:       "Auth Immutable",
+	txnbuild.AuthClawbackEnabled: "Clawback Enabled",
+}
+
+var accountFlagOrder = []txnbuild.AccountFlag{
+	txnbuild.AuthRequired,
+	txnbuild.AuthRevocable,
+	txnbuild.AuthImmutable,
+	txnbuild.AuthClawbackEnabled,
+}
+
+func accountFlagSet(flags horizon.AccountFlags, flag txnbuild.AccountFlag) bool {
+	switch flag {
+	case txnbuild.AuthRequired:
+		return flags.AuthRequired
+	case txnbuild.AuthRevocable:
+		return flags.AuthRevocable
+	case txnbuild.AuthImmutable:
+		return flags.AuthImmutable
+	case txnbuild.AuthClawbackEnabled:
+		return flags.AuthClawbackEnabled
+	}
+	return false
+}
+
+// Work out which flag toggles the network accepts, given the flags currently on the
+// account and the flags the user wants after the same SetOptions operation.
+// Once AuthImmutable is set no authorization flag can ever be changed again, and
+// clawback can only be enabled while AuthRevocable ends up set.
+func allowedFlagChanges(current, desired horizon.AccountFlags) map[txnbuild.AccountFlag]flagChange {
+	changes := make(map[txnbuild.AccountFlag]flagChange, len(accountFlagOrder))
+
+	if current.AuthImmutable {
+		for _, flag := range accountFlagOrder {
+			changes[flag] = flagChange{Reason: "account is auth immutable, flags can no longer change"}
+		}
+		return changes
+	}
+
+	changes[txnbuild.AuthRequired] = flagChange{Allowed: true}
+	changes[txnbuild.AuthImmutable] = flagChange{Allowed: true}
+
+	if desired.AuthRevocable && desired.AuthClawbackEnabled {
+		changes[txnbuild.AuthRevocable] = flagChange{Reason: "clear Clawback Enabled before clearing Auth Revocable"}
+	} else {
+		changes[txnbuild.AuthRevocable] = flagChange{Allowed: true}
+	}
+
+	if !desired.AuthClawbackEnabled && !desired.AuthRevocable {
+		changes[txnbuild.AuthClawbackEnabled] = flagChange{Reason: "requires Auth Revocable to be set first"}
+	} else {
+		changes[txnbuild.AuthClawbackEnabled] = flagChange{Allowed: true}
+	}
+	return changes
+}
+
+func showAccountFlagsDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: wallet.PublicKey})
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+		return
+	}
+
+	checks := make(map[txnbuild.AccountFlag]*widget.Check)
+	info := widget.NewLabel("")
+	info.Wrapping = fyne.TextWrapWord
+
+	// Re-evaluate after every toggle so that, for example, ticking Auth Revocable
+	// unlocks Clawback Enabled within the same operation.
+	update := func() {
+		desired := horizon.AccountFlags{
+			AuthRequired:        checks[txnbuild.AuthRequired].Checked,
+			AuthRevocable:       checks[txnbuild.AuthRevocable].Checked,
+			AuthImmutable:       checks[txnbuild.AuthImmutable].Checked,
+			AuthClawbackEnabled: checks[txnbuild.AuthClawbackEnabled].Checked,
+		}
+		changes := allowedFlagChanges(account.Flags, desired)
+		var reasons []string
+		for _, flag := range accountFlagOrder {
+			if change := changes[flag]; change.Allowed {
+				checks[flag].Enable()
+				continue
+			}
+			checks[flag].Disable()
+			reasons = append(reasons, fmt.Sprintf("%s: %s", accountFlagNames[flag], changes[flag].Reason))
+		}
+		info.SetText(strings.Join(reasons, "\n"))
+	}
+
+	form := container.NewVBox()
+	for _, flag := range accountFlagOrder {
+		check := widget.NewCheck(accountFlagNames[flag], nil)
+		check.SetChecked(accountFlagSet(account.Flags, flag))
+		checks[flag] = check
+		form.Add(check)
+	}
+	for _, check := range checks {
+		check.OnChanged = func(bool) { update() }
+	}
+	update()
+	form.Add(info)
+
+	dialog.ShowCustomConfirm("Account Flags", "Apply", "Cancel", form, func(apply bool) {
+		if !apply {
+			return
+		}
+
+		var setFlags, clearFlags []txnbuild.AccountFlag
+		for _, flag := range accountFlagOrder {
+			current := accountFlagSet(account.Flags, flag)
+			if checks[flag].Checked && !current {
+				setFlags = append(setFlags, flag)
+			} else if !checks[flag].Checked && current {
+				clearFlags = append(clearFlags, flag)
+			}
+		}
+		if len(setFlags) == 0 && len(clearFlags) == 0 {
+			return
+		}
+
+		submit := func() {
+			submitWithFeedback([]txnbuild.Operation{
+				&txnbuild.SetOptions{SetFlags: setFlags, ClearFlags: clearFlags},
+			}, nil, func(hash string) {
+				dialog.ShowInformation("Success", fmt.Sprintf("Account flags updated! Hash: %s", hash), window)
+			})
+		}
+
+		if checks[txnbuild.AuthImmutable].Checked && !account.Flags.AuthImmutable {
+			dialog.ShowConfirm("Auth Immutable",
+				"Setting Auth Immutable is permanent: authorization flags can never be changed again. Continue?",
+				func(ok bool) {
+					if ok {
+						submit()
+					}
+				}, window)
+			return
+		}
+		submit()
+	}, window)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestAllowedFlagChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  horizon.AccountFlags
+		desired  horizon.AccountFlags
+		flag     txnbuild.AccountFlag
+		expected bool
+	}{
+		{"clawback needs revocable", horizon.AccountFlags{}, horizon.AccountFlags{}, txnbuild.AuthClawbackEnabled, false},
+		{"clawback with revocable in same operation", horizon.AccountFlags{}, horizon.AccountFlags{AuthRevocable: true}, txnbuild.AuthClawbackEnabled, true},
+		{"clawback with revocable already set", horizon.AccountFlags{AuthRevocable: true}, horizon.AccountFlags{AuthRevocable: true}, txnbuild.AuthClawbackEnabled, true},
+		{"revocable locked while clawback stays", horizon.AccountFlags{AuthRevocable: true, AuthClawbackEnabled: true}, horizon.AccountFlags{AuthRevocable: true, AuthClawbackEnabled: true}, txnbuild.AuthRevocable, false},
+		{"revocable cleared with clawback", horizon.AccountFlags{AuthRevocable: true, AuthClawbackEnabled: true}, horizon.AccountFlags{AuthRevocable: true}, txnbuild.AuthRevocable, true},
+		{"immutable locks everything", horizon.AccountFlags{AuthImmutable: true}, horizon.AccountFlags{AuthImmutable: true, AuthRevocable: true}, txnbuild.AuthRequired, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := allowedFlagChanges(tt.current, tt.desired)[tt.flag]
+			if change.Allowed != tt.expected {
+				t.Errorf("allowed = %v, want %v (reason %q)", change.Allowed, tt.expected, change.Reason)
+			}
+			if !change.Allowed && change.Reason == "" {
+				t.Error("disallowed change has no reason")
+			}
+		})
+	}
+}
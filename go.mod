@@ -4,12 +4,16 @@ go 1.23.4
 
 require (
 	fyne.io/fyne/v2 v2.5.3
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/stellar/go v0.0.0-20250115012512-bd7c1ad98159
+	github.com/tyler-smith/go-bip39 v0.0.0-20180618194314-52158e4697b8
+	golang.org/x/crypto v0.31.0
 )
 
 require (
 	fyne.io/systray v1.11.0 // indirect
 	github.com/BurntSushi/toml v1.4.0 // indirect
+	github.com/asaskevich/govalidator v0.0.0-20230301143203-a9d515a09cc2 // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/fredbi/uri v1.1.0 // indirect
 	github.com/fsnotify/fsnotify v1.7.0 // indirect
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestHistoryPager(t *testing.T) {
+	pager := &historyPager{fetch: pagedFetcher([][]activityRecord{
+		{{Hash: "a"}, {Hash: "b"}},
+		{{Hash: "c"}},
+	})}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			if _, ok, err := pager.next(); err != nil || !ok {
+				return
+			}
+		}
+	}()
+	// The list reads hashes while pages are still loading
+	for i := 0; i < 100; i++ {
+		pager.hash(i % 4)
+	}
+	wg.Wait()
+
+	if !pager.started() {
+		t.Error("started() = false after loading pages")
+	}
+	for id, want := range []string{"a", "b", "c"} {
+		if got, ok := pager.hash(id); !ok || got != want {
+			t.Errorf("hash(%d) = %q, %v, want %q", id, got, ok, want)
+		}
+	}
+	if _, ok := pager.hash(3); ok {
+		t.Error("hash(3) found an entry past the end")
+	}
+}
+
+func TestHistoryPagerErrorKeepsCursor(t *testing.T) {
+	failing := true
+	pager := &historyPager{fetch: func(cursor string) ([]activityRecord, string, error) {
+		if failing {
+			return nil, cursor, errors.New("timeout")
+		}
+		return pagedFetcher([][]activityRecord{{{Hash: "a"}}})(cursor)
+	}}
+
+	if _, ok, err := pager.next(); err == nil || ok {
+		t.Fatalf("next() = %v, %v, want an error", ok, err)
+	}
+	if pager.started() {
+		t.Error("a failed load moved the cursor")
+	}
+
+	failing = false
+	page, ok, err := pager.next()
+	if err != nil || !ok || len(page) != 1 {
+		t.Errorf("retry = %v, %v, %v", page, ok, err)
+	}
+}
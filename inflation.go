@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// Inflation and pool participation as recorded on an account
+type participationStatus struct {
+	InflationDestination string
+	VotesForSelf         bool
+	LiquidityPools       []string
+}
+
+// Pull the inflation destination and liquidity pool shares out of an account record
+func participationStatusFrom(account horizon.Account) participationStatus {
+	status := participationStatus{
+		InflationDestination: account.InflationDestination,
+		VotesForSelf:         account.InflationDestination != "" && account.InflationDestination == account.AccountID,
+	}
+	for _, balance := range account.Balances {
+		if balance.Asset.Type == "liquidity_pool_shares" {
+			status.LiquidityPools = append(status.LiquidityPools, balance.LiquidityPoolId)
+		}
+	}
+	return status
+}
+
+func participationStatusText(status participationStatus) string {
+	var lines []string
+	switch {
+	case status.InflationDestination == "":
+		lines = append(lines, "Inflation destination: not set")
+	case status.VotesForSelf:
+		lines = append(lines, "Inflation destination: this account")
+	default:
+		lines = append(lines, "Inflation destination: "+status.InflationDestination)
+		lines = append(lines, "Pool member: yes, votes go to the destination above")
+	}
+	lines = append(lines, "Inflation was disabled in protocol 12; this setting is kept for reference only.")
+
+	if len(status.LiquidityPools) == 0 {
+		lines = append(lines, "", "Liquidity pools: none")
+	} else {
+		lines = append(lines, "", fmt.Sprintf("Liquidity pools (%d):", len(status.LiquidityPools)))
+		lines = append(lines, status.LiquidityPools...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func showParticipationDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: wallet.PublicKey})
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+		return
+	}
+
+	label := widget.NewLabel(participationStatusText(participationStatusFrom(account)))
+	label.Wrapping = fyne.TextWrapWord
+	dialog.ShowCustom("Inflation & Pools (legacy)", "Close", label, window)
+}
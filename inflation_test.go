@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+)
+
+func TestParticipationStatus(t *testing.T) {
+	const pool = "dd7b1ab831c273310ddbec6f97870aa83c2fbd78ce22aded37ecbf4f3380fac7"
+	balances := []horizon.Balance{
+		{Balance: "100", Asset: base.Asset{Type: "native"}},
+		{Balance: "5", Asset: base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: testOther}},
+		{Balance: "1", LiquidityPoolId: pool, Asset: base.Asset{Type: "liquidity_pool_shares"}},
+	}
+	tests := []struct {
+		name     string
+		account  horizon.Account
+		want     participationStatus
+		wantText []string
+	}{
+		{"nothing set", horizon.Account{AccountID: testWallet},
+			participationStatus{},
+			[]string{"Inflation destination: not set", "Liquidity pools: none"}},
+		{"votes for self", horizon.Account{AccountID: testWallet, InflationDestination: testWallet},
+			participationStatus{InflationDestination: testWallet, VotesForSelf: true},
+			[]string{"Inflation destination: this account"}},
+		{"pool member", horizon.Account{AccountID: testWallet, InflationDestination: testOther, Balances: balances},
+			participationStatus{InflationDestination: testOther, LiquidityPools: []string{pool}},
+			[]string{"Inflation destination: " + testOther, "Pool member: yes", "Liquidity pools (1):\n" + pool}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := participationStatusFrom(tt.account)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("status = %+v, want %+v", got, tt.want)
+			}
+			text := participationStatusText(got)
+			for _, want := range append(tt.wantText, "disabled in protocol 12") {
+				if !strings.Contains(text, want) {
+					t.Errorf("text %q missing %q", text, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Package horizontest provides a fake Horizon for tests of the wallet and the
+// app, in the spirit of net/http/httptest.
+package horizontest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/txnbuild"
+)
+
+// A HorizonAPI with canned responses that records every transaction
+// submitted to it, for exercising payment logic without a network.
+// Accounts missing from Accounts are reported as not found, like Horizon does.
+type FakeHorizon struct {
+	mu sync.Mutex
+
+	Accounts           map[string]horizon.Account
+	TransactionRecords []horizon.Transaction
+	PaymentsPage       operations.OperationsPage
+	Fees               horizon.FeeStats
+
+	// Listed by ClaimableBalances and looked up by ClaimableBalance
+	ClaimableBalanceRecords []horizon.ClaimableBalance
+
+	// Returned whatever the request
+	OperationsPage operations.OperationsPage
+	OffersPage     horizon.OffersPage
+	Book           horizon.OrderBookSummary
+	Paths          horizon.PathsPage
+
+	// Returned by AccountDetail for every account when set, such as an outage
+	AccountErr error
+
+	// Returned by Root; the zero Root links to nothing
+	ServerRoot horizon.Root
+	RootErr    error
+
+	// Returned by SubmitTransaction and SubmitFeeBumpTransaction when set
+	SubmitErr         error
+	Submitted         []*txnbuild.Transaction
+	SubmittedFeeBumps []*txnbuild.FeeBumpTransaction
+}
+
+var _ wallet.HorizonAPI = (*FakeHorizon)(nil)
+
+func NewFakeHorizon(accounts ...horizon.Account) *FakeHorizon {
+	f := &FakeHorizon{Accounts: make(map[string]horizon.Account)}
+	for _, account := range accounts {
+		f.Accounts[account.AccountID] = account
+	}
+	return f
+}
+
+func (f *FakeHorizon) AccountDetail(request horizonclient.AccountRequest) (horizon.Account, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.AccountErr != nil {
+		return horizon.Account{}, f.AccountErr
+	}
+	account, ok := f.Accounts[request.AccountID]
+	if !ok {
+		return horizon.Account{}, notFound()
+	}
+	return account, nil
+}
+
+// The error Horizon gives for a missing resource
+func notFound() error {
+	return &horizonclient.Error{
+		Problem: problem.P{Type: "not_found", Title: "Resource Missing", Status: http.StatusNotFound},
+	}
+}
+
+// Record tx and, unless SubmitErr is set, report it applied
+func (f *FakeHorizon) SubmitTransaction(tx *txnbuild.Transaction) (horizon.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Submitted = append(f.Submitted, tx)
+	if f.SubmitErr != nil {
+		return horizon.Transaction{}, f.SubmitErr
+	}
+	network, _ := SignedNetwork(tx)
+	hash, err := tx.HashHex(wallet.Passphrase(network))
+	if err != nil {
+		return horizon.Transaction{}, err
+	}
+	return horizon.Transaction{Hash: hash, Successful: true}, nil
+}
+
+// Record tx and, unless SubmitErr is set, report it applied. The hash is
+// taken on testnet, since fee bump signatures aren't checked.
+func (f *FakeHorizon) SubmitFeeBumpTransaction(tx *txnbuild.FeeBumpTransaction) (horizon.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.SubmittedFeeBumps = append(f.SubmittedFeeBumps, tx)
+	if f.SubmitErr != nil {
+		return horizon.Transaction{}, f.SubmitErr
+	}
+	hash, err := tx.HashHex(wallet.Passphrase("testnet"))
+	if err != nil {
+		return horizon.Transaction{}, err
+	}
+	return horizon.Transaction{Hash: hash, Successful: true}, nil
+}
+
+func (f *FakeHorizon) Transactions(request horizonclient.TransactionRequest) (horizon.TransactionsPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var page horizon.TransactionsPage
+	page.Embedded.Records = f.TransactionRecords
+	return page, nil
+}
+
+func (f *FakeHorizon) Payments(request horizonclient.OperationRequest) (operations.OperationsPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.PaymentsPage, nil
+}
+
+func (f *FakeHorizon) FeeStats() (horizon.FeeStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Fees, nil
+}
+
+func (f *FakeHorizon) Root() (horizon.Root, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ServerRoot, f.RootErr
+}
+
+// Look up txHash in TransactionRecords
+func (f *FakeHorizon) TransactionDetail(txHash string) (horizon.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, tx := range f.TransactionRecords {
+		if tx.Hash == txHash {
+			return tx, nil
+		}
+	}
+	return horizon.Transaction{}, notFound()
+}
+
+func (f *FakeHorizon) Operations(request horizonclient.OperationRequest) (operations.OperationsPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.OperationsPage, nil
+}
+
+func (f *FakeHorizon) Offers(request horizonclient.OfferRequest) (horizon.OffersPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.OffersPage, nil
+}
+
+func (f *FakeHorizon) OrderBook(request horizonclient.OrderBookRequest) (horizon.OrderBookSummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Book, nil
+}
+
+func (f *FakeHorizon) StrictSendPaths(request horizonclient.StrictSendPathsRequest) (horizon.PathsPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Paths, nil
+}
+
+func (f *FakeHorizon) ClaimableBalances(request horizonclient.ClaimableBalanceRequest) (horizon.ClaimableBalances, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var page horizon.ClaimableBalances
+	page.Embedded.Records = f.ClaimableBalanceRecords
+	return page, nil
+}
+
+func (f *FakeHorizon) ClaimableBalance(id string) (horizon.ClaimableBalance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, balance := range f.ClaimableBalanceRecords {
+		if balance.BalanceID == id {
+			return balance, nil
+		}
+	}
+	return horizon.ClaimableBalance{}, notFound()
+}
+
+// Hand over every record in PaymentsPage, then hold the stream open until ctx
+// is cancelled
+func (f *FakeHorizon) StreamPayments(ctx context.Context, request horizonclient.OperationRequest, handler horizonclient.OperationHandler) error {
+	f.mu.Lock()
+	records := f.PaymentsPage.Embedded.Records
+	f.mu.Unlock()
+	for _, op := range records {
+		handler(op)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Network, "public" or "testnet", whose passphrase the source account signed
+// tx for. False when no signature from the source account verifies.
+func SignedNetwork(tx *txnbuild.Transaction) (string, bool) {
+	source := tx.SourceAccount()
+	kp, err := keypair.ParseAddress(source.AccountID)
+	if err != nil {
+		return "", false
+	}
+	for _, name := range []string{"public", "testnet"} {
+		hash, err := tx.Hash(wallet.Passphrase(name))
+		if err != nil {
+			return "", false
+		}
+		for _, sig := range tx.Signatures() {
+			if kp.Verify(hash[:], sig.Signature) == nil {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
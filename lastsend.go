@@ -0,0 +1,24 @@
+package main
+
+// Parameters of a payment, kept so it can be repeated. Never holds secrets.
+type sendParams struct {
+	Recipient string `json:"recipient"`
+	Amount    string `json:"amount"`
+	Asset     string `json:"asset"`
+	Memo      string `json:"memo,omitempty"`
+	MemoType  string `json:"memo_type,omitempty"`
+
+	// Chosen per send from current network fees, so never remembered
+	BaseFee int64 `json:"-"`
+}
+
+func rememberLastSend(s *Settings, params sendParams) {
+	s.LastSend = &params
+}
+
+func lastSend(s Settings) (sendParams, bool) {
+	if s.LastSend == nil || s.LastSend.Recipient == "" {
+		return sendParams{}, false
+	}
+	return *s.LastSend, true
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestLastSendRoundTrip(t *testing.T) {
+	s := defaultSettings()
+	if _, ok := lastSend(s); ok {
+		t.Fatal("fresh settings have a last send")
+	}
+
+	sent := sendParams{Recipient: testOther, Amount: "12.5", Asset: "native", Memo: "42", MemoType: "id", BaseFee: 500}
+	rememberLastSend(&s, sent)
+	sent.Amount = "99" // later edits to the caller's copy don't leak in
+
+	data, err := encodeSettings(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := decodeSettings(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := lastSend(loaded)
+	if !ok {
+		t.Fatal("last send lost across save and load")
+	}
+	want := sendParams{Recipient: testOther, Amount: "12.5", Asset: "native", Memo: "42", MemoType: "id"}
+	if got != want {
+		t.Errorf("last send = %+v, want %+v (base fee not remembered)", got, want)
+	}
+
+	rememberLastSend(&loaded, sendParams{Amount: "1"})
+	if _, ok := lastSend(loaded); ok {
+		t.Error("last send without a recipient offered for repeating")
+	}
+}
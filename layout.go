@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type layoutMode int
+
+const (
+	layoutWide layoutMode = iota
+	layoutCompact
+)
+
+// Below this width the action buttons collapse into a menu
+const compactWidth = 420
+
+func layoutForSize(size fyne.Size) layoutMode {
+	if size.Width < compactWidth {
+		return layoutCompact
+	}
+	return layoutWide
+}
+
+// Vertical layout that swaps the full set of action buttons for a single
+// menu button on narrow windows, so every action stays reachable
+type responsiveLayout struct {
+	actions    fyne.CanvasObject
+	menuButton fyne.CanvasObject
+}
+
+func (l *responsiveLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	if layoutForSize(size) == layoutCompact {
+		l.actions.Hide()
+		l.menuButton.Show()
+	} else {
+		l.menuButton.Hide()
+		l.actions.Show()
+	}
+	layout.NewVBoxLayout().Layout(objects, size)
+}
+
+func (l *responsiveLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return layout.NewVBoxLayout().MinSize(objects)
+}
+
+// Menu with an item for each of buttons that is currently offered. Hidden
+// buttons are left out and disabled ones give disabled items.
+func actionsMenu(buttons []*widget.Button) *fyne.Menu {
+	menu := fyne.NewMenu("")
+	for _, b := range buttons {
+		if b.Hidden {
+			continue
+		}
+		b := b
+		item := fyne.NewMenuItem(b.Text, func() {
+			if !b.Disabled() && !b.Hidden {
+				b.OnTapped()
+			}
+		})
+		item.Disabled = b.Disabled()
+		menu.Items = append(menu.Items, item)
+	}
+	return menu
+}
+
+// Button opening a popup menu with the same actions as buttons, following
+// their current visibility each time it opens
+func actionsMenuButton(buttons ...*widget.Button) *widget.Button {
+	var menuButton *widget.Button
+	menuButton = widget.NewButtonWithIcon("Actions", theme.MenuIcon(), func() {
+		menu := actionsMenu(buttons)
+		driver := fyne.CurrentApp().Driver()
+		position := driver.AbsolutePositionForObject(menuButton).Add(fyne.NewPos(0, menuButton.Size().Height))
+		widget.ShowPopUpMenuAtPosition(menu, driver.CanvasForObject(menuButton), position)
+	})
+	return menuButton
+}
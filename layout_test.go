@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestLayoutForSize(t *testing.T) {
+	tests := []struct {
+		width float32
+		want  layoutMode
+	}{
+		{0, layoutCompact},
+		{compactWidth - 1, layoutCompact},
+		{compactWidth, layoutWide},
+		{1200, layoutWide},
+	}
+	for _, tt := range tests {
+		if got := layoutForSize(fyne.NewSize(tt.width, 600)); got != tt.want {
+			t.Errorf("layoutForSize(width %v) = %v, want %v", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestActionsMenu(t *testing.T) {
+	tapped := ""
+	button := func(text string) *widget.Button {
+		return &widget.Button{Text: text, OnTapped: func() { tapped = text }}
+	}
+	repeat, importAccount, testnet := button("Repeat"), button("Import"), button("New Testnet Account")
+	importAccount.Disable()
+	testnet.Hide()
+
+	menu := actionsMenu([]*widget.Button{repeat, importAccount, testnet})
+	if len(menu.Items) != 2 {
+		t.Fatalf("%d menu items, want the hidden testnet action left out", len(menu.Items))
+	}
+	if menu.Items[0].Disabled || !menu.Items[1].Disabled {
+		t.Errorf("disabled = %v, %v, want false, true", menu.Items[0].Disabled, menu.Items[1].Disabled)
+	}
+	menu.Items[1].Action()
+	if tapped != "" {
+		t.Errorf("disabled item ran %q", tapped)
+	}
+	menu.Items[0].Action()
+	if tapped != "Repeat" {
+		t.Errorf("tapped %q, want Repeat", tapped)
+	}
+
+	testnet.Show()
+	if menu := actionsMenu([]*widget.Button{repeat, importAccount, testnet}); len(menu.Items) != 3 {
+		t.Errorf("%d menu items once the testnet action is shown, want 3", len(menu.Items))
+	}
+}
@@ -1,303 +1,1166 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
-	"github.com/stellar/go/clients/horizonclient"
-	"github.com/stellar/go/keypair"
-	"github.com/stellar/go/network"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/txnbuild"
 )
 
-type Wallet struct {
-	PublicKey string `json:"public_key"`
-	SecretKey string `json:"secret_key"`
-	Balance   string `json:"balance"`
-	Network   string `json:"network"` // "public" or "testnet"
-}
+// An account in the wallet file; storage lives in the wallet package
+type Wallet = core.Account
 
 const walletFile = "stellar_wallet.json"
 
 var (
-	wallet Wallet
-	client *horizonclient.Client
+	// The active account in store
+	wallet *Wallet
+	client core.HorizonAPI
+
+	// Guards wallet and store against background balance refreshes
+	walletMu sync.Mutex
+
+	// Guards lastBalances and balanceGeneration, so refreshes apply one at a
+	// time and their alerts don't interleave. Never held across a Horizon call.
+	refreshMu sync.Mutex
+
+	// Bumped when the wallet or network changes; a refresh started under an
+	// older generation is dropped when it finishes
+	balanceGeneration uint64
 )
 
 // Initialize Horizon client based on network
 func initializeClient(network string) {
-	if network == "testnet" {
-		client = horizonclient.DefaultTestNetClient
-	} else {
-		client = horizonclient.DefaultPublicNetClient
-	}
+	client = launchClient(network)
+	resetFeeStats()
+}
+
+// Network passphrase matching the selected network
+func currentPassphrase() string {
+	return core.Passphrase(wallet.Network)
 }
 
+// Problems loadWallet found and repaired, shown once the window is up
+var walletWarnings []string
+
 // Load or create new wallet
 func loadWallet() error {
-	data, err := os.ReadFile(walletFile)
+	path, err := walletPath()
 	if err != nil {
-		// Create new wallet if file doesn't exist
-		kp, err := keypair.Random()
+		return err
+	}
+	decoded, err := core.LoadStore(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Create new wallet from a fresh recovery phrase if file doesn't exist
+		phrase, kp, err := newRecoveryPhrase()
 		if err != nil {
 			return err
 		}
 
-		wallet = Wallet{
+		store = WalletStore{Wallets: []Wallet{{
 			PublicKey: kp.Address(),
 			SecretKey: kp.Seed(),
 			Network:   "testnet",
 			Balance:   "0",
-		}
+			Recovery:  &phrase,
+		}}}
+		wallet = store.Active()
+		applyLaunchNetwork()
 
-		fundAccount(kp.Address())
+		// Saved once the user has chosen a password
+		if wallet.Network == "testnet" {
+			if err := fundAccount(kp.Address()); err != nil {
+				log.Printf("could not fund new testnet account: %v", err)
+			}
+		}
 		initializeClient(wallet.Network)
-		return saveWallet()
+		return nil
 	}
 
-	err = json.Unmarshal(data, &wallet)
+	if errors.Is(err, core.ErrCorruptStore) {
+		// Unreadable: keep the file for recovery and start over with a new wallet
+		corrupt := path + ".corrupt-" + time.Now().Format("20060102-150405")
+		if renameErr := os.Rename(path, corrupt); renameErr != nil {
+			return fmt.Errorf("%v and could not be moved aside: %v", err, renameErr)
+		}
+		walletWarnings = append(walletWarnings, fmt.Sprintf("The wallet file could not be read (%v). It was kept as %s and a new wallet was created.", err, corrupt))
+		return loadWallet()
+	}
+	if err != nil {
+		return fmt.Errorf("error reading wallet: %v", err)
+	}
+	warnings, err := repairWalletStore(&decoded)
 	if err != nil {
 		return err
 	}
+	walletWarnings = append(walletWarnings, warnings...)
+	store = decoded
+	wallet = store.Active()
+	applyLaunchNetwork()
 
 	initializeClient(wallet.Network)
 	return nil
 }
 
+// Write every account with its secret encrypted; the plaintext never reaches disk
 func saveWallet() error {
-	data, err := json.MarshalIndent(wallet, "", "  ")
+	walletMu.Lock()
+	defer walletMu.Unlock()
+
+	path, err := walletPath()
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(walletFile, data, 0600)
+	return store.Save(path, walletKey)
 }
 
+// Ask friendbot to create and fund address on testnet
 func fundAccount(address string) error {
-	resp, err := http.Get("https://friendbot.stellar.org/?addr=" + address)
+	resp, err := http.Get("https://friendbot.stellar.org/?addr=" + url.QueryEscape(address))
 	if err != nil {
-		return err
+		return fmt.Errorf("friendbot request failed: %v", err)
 	}
 
 	defer resp.Body.Close()
-	_, err = io.ReadAll(resp.Body)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
 	if err != nil {
-		return err
+		return fmt.Errorf("error reading friendbot response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("friendbot returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
 	}
 	return nil
+}
 
+// Forget the balance state of the previous wallet or network, dropping any
+// refresh still waiting on Horizon
+func resetBalanceState() {
+	refreshMu.Lock()
+	balanceGeneration++
+	lastBalances = nil
+	refreshMu.Unlock()
 }
 
-func updateBalance() string {
-	account, err := client.AccountDetail(horizonclient.AccountRequest{
-		AccountID: wallet.PublicKey,
-	})
+// Fetch the wallet balance and apply it, returning the balance label text, or
+// false when the wallet or network changed while Horizon was answering
+func updateBalance() (string, bool) {
+	refreshMu.Lock()
+	generation := balanceGeneration
+	refreshMu.Unlock()
+
+	account, err := activeSession().Account()
+	return applyBalance(generation, account, err)
+}
+
+// Apply the result of a balance fetch started under generation
+func applyBalance(generation uint64, account horizon.Account, err error) (string, bool) {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+	if generation != balanceGeneration {
+		return "", false
+	}
+
+	if err != nil {
+		if isNotFound(err) {
+			setFundedState(false)
+		}
+		walletMu.Lock()
+		last := wallet.Balance
+		walletMu.Unlock()
+		return balanceErrorText(err, last), true
+	}
+	setFundedState(true)
+	checkBalanceAlerts(account)
+
+	balance, ok := nativeBalance(account)
+	if !ok {
+		return "No XLM balance found", true
+	}
+	walletMu.Lock()
+	wallet.Balance = balance
+	walletMu.Unlock()
+	saveWallet()
+	go refreshFiatValue(balance)
+	return fmt.Sprintf("Balance: %s XLM", balance), true
+}
+
+// Balance line after a failed refresh. Only a 404 means the account doesn't
+// exist; anything else is a connection problem, so the last known balance
+// stays on screen.
+func balanceErrorText(err error, lastBalance string) string {
+	if isNotFound(err) {
+		return "Account not funded"
+	}
+	log.Printf("balance refresh failed: %v", err)
+	if lastBalance == "" {
+		if message, ok := connectionProblem(err); ok {
+			return "Balance unavailable: " + message
+		}
+		return "Balance unavailable (can't reach Horizon)"
+	}
+	return fmt.Sprintf("Balance: %s XLM (offline, last known)", lastBalance)
+}
+
+// Refresh label from Horizon in the background so a slow network doesn't
+// freeze the window
+func refreshBalanceAsync(label *widget.Label) {
+	label.SetText("Refreshing…")
+	go refreshBalance(label)
+}
+
+// Refresh label from Horizon, blocking until the request finishes. A result
+// for a wallet or network no longer selected leaves label alone.
+func refreshBalance(label *widget.Label) {
+	if text, ok := updateBalance(); ok {
+		label.SetText(text)
+	}
+}
+
+// Fetch the wallet account along with its balances keyed by asset
+func updateBalances() (horizon.Account, map[string]string, error) {
+	account, err := activeSession().Account()
 	if err != nil {
-		return "Account not found (unfunded)"
+		return horizon.Account{}, nil, err
 	}
+	return account, core.Balances(account), nil
+}
 
-	for _, balance := range account.Balances {
-		if balance.Asset.Type == "native" {
-			wallet.Balance = balance.Balance
-			saveWallet()
-			return fmt.Sprintf("Balance: %s XLM", balance.Balance)
+// Assets of a balance map, XLM first and the rest alphabetically
+func sortedAssets(balances map[string]string) []string {
+	assets := make([]string, 0, len(balances))
+	for asset := range balances {
+		if asset != "XLM" {
+			assets = append(assets, asset)
 		}
 	}
-	return "No XLM balance found"
+	sort.Strings(assets)
+	return append([]string{"XLM"}, assets...)
+}
+
+func showSendDialog(balanceLabel *widget.Label, prefill sendParams) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+	items, submit, amountEntry := sendFormItems(balanceLabel, prefill)
+	d := dialog.NewForm("Send Payment", "Send", "Cancel", items, func(ok bool) {
+		if ok {
+			submit()
+		}
+	}, window)
+	amountEntry.OnSubmitted = func(string) { d.Submit() }
+	d.Show()
 }
 
-func showSendDialog(balanceLabel *widget.Label) {
+// Send tab: the send form, ready for a new payment
+func sendPanel(balanceLabel *widget.Label) fyne.CanvasObject {
+	items, submit, amountEntry := sendFormItems(balanceLabel, sendParams{})
+	form := widget.NewForm(items...)
+	form.SubmitText = "Send"
+	form.OnSubmit = submit
+	amountEntry.OnSubmitted = func(string) { submit() }
+	return container.NewVScroll(form)
+}
+
+// Fields of a payment, prefilled from prefill, the function that checks and
+// sends what was entered, and the amount field, which sends on Enter
+func sendFormItems(balanceLabel *widget.Label, prefill sendParams) ([]*widget.FormItem, func(), *widget.Entry) {
 	window := fyne.CurrentApp().Driver().AllWindows()[0]
 
 	recipientEntry := widget.NewEntry()
 	amountEntry := widget.NewEntry()
 	memoEntry := widget.NewEntry()
 
-	recipientEntry.SetPlaceHolder("Recipient address")
-	amountEntry.SetPlaceHolder("Amount (XLM)")
-	memoEntry.SetPlaceHolder("Memo (optional)")
+	recipientEntry.SetPlaceHolder("Recipient address or name*domain")
+
+	recipientEntry.SetText(prefill.Recipient)
+	amountEntry.SetText(prefill.Amount)
+	memo := initialMemo(settings.DefaultMemo, prefill)
+	memoTypeSelect := widget.NewSelect(memoTypes, func(memoType string) {
+		memoEntry.SetPlaceHolder(memoPlaceholder(memoType))
+	})
+	memoTypeSelect.SetSelected(memo.Type)
+	memoEntry.SetText(memo.Value)
+
+	// Set by the Max button and kept up to date as the asset changes, until
+	// the user edits the amount
+	var (
+		maxed        bool
+		recomputeMax func()
+	)
+
+	// Show what can actually be sent so the user doesn't try to spend the reserve
+	availableLabel := widget.NewLabel("")
+	account, balances, err := updateBalances()
+	if err != nil {
+		availableLabel.SetText("Balance unavailable")
+		balances = map[string]string{"XLM": "0"}
+	}
+
+	assetSelect := widget.NewSelect(sortedAssets(balances), func(selected string) {
+		asset, err := parseAsset(selected)
+		if err != nil {
+			return
+		}
+		amountEntry.SetPlaceHolder(fmt.Sprintf("Amount (%s)", assetCode(asset)))
+		if maxed && recomputeMax != nil {
+			recomputeMax()
+		}
+		if account.AccountID == "" {
+			return
+		}
+		if breakdown, err := assetBreakdown(account, asset); err != nil {
+			availableLabel.SetText(err.Error())
+		} else {
+			availableLabel.SetText(breakdownText(breakdown, assetCode(asset)))
+		}
+	})
+	if _, held := balances[prefill.Asset]; held {
+		assetSelect.SetSelected(prefill.Asset)
+	} else {
+		assetSelect.SetSelected("XLM")
+	}
+
+	// Picking a contact fills in their address and any memo they require
+	contactSelect := widget.NewSelect(contactNames(settings.Contacts), func(name string) {
+		c, ok := findContact(settings.Contacts, name)
+		if !ok {
+			return
+		}
+		recipientEntry.SetText(c.Address)
+		if c.MemoType != "" {
+			memoTypeSelect.SetSelected(c.MemoType)
+			memoEntry.SetText(c.Memo)
+		}
+	})
+	contactSelect.PlaceHolder = "Choose a contact"
+
+	// Federation addresses are resolved as they're typed; a memo the
+	// federation server requires is filled in and locked
+	resolvedLabel := widget.NewLabel("")
+	resolvedLabel.Wrapping = fyne.TextWrapBreak
+	resolvedLabel.Hide()
+	var (
+		resolveMu    sync.Mutex
+		resolved     *federatedRecipient
+		resolveTimer *time.Timer
+		muxedLocked  bool
+	)
+	applyResolved := func(r *federatedRecipient, err error) {
+		switch {
+		case err != nil:
+			resolvedLabel.SetText(err.Error())
+			resolvedLabel.Importance = widget.DangerImportance
+		case r != nil:
+			resolvedLabel.SetText(r.AccountID)
+			resolvedLabel.Importance = widget.MediumImportance
+			if r.Memo.Type != "none" {
+				memoTypeSelect.SetSelected(r.Memo.Type)
+				memoEntry.SetText(r.Memo.Value)
+				memoTypeSelect.Disable()
+				memoEntry.Disable()
+			}
+		}
+		resolvedLabel.Refresh()
+	}
+	recipientEntry.OnChanged = func(text string) {
+		resolveMu.Lock()
+		defer resolveMu.Unlock()
+		if resolveTimer != nil {
+			resolveTimer.Stop()
+		}
+		if (resolved != nil && resolved.Memo.Type != "none") || muxedLocked {
+			memoTypeSelect.Enable()
+			memoEntry.Enable()
+		}
+		resolved = nil
+		muxedLocked = false
+
+		// A muxed address carries its own ID in place of a memo
+		if accountID, id, err := decodeMuxedAddress(strings.TrimSpace(text)); err == nil {
+			resolvedLabel.SetText(fmt.Sprintf("Muxed address for %s, ID %d. No memo is needed.", shortAddress(accountID), id))
+			resolvedLabel.Importance = widget.MediumImportance
+			resolvedLabel.Show()
+			resolvedLabel.Refresh()
+			memoTypeSelect.SetSelected("none")
+			memoEntry.SetText("")
+			memoTypeSelect.Disable()
+			memoEntry.Disable()
+			muxedLocked = true
+			return
+		}
+		if !isFederationAddress(text) {
+			resolvedLabel.Hide()
+			return
+		}
+		resolvedLabel.SetText("Resolving...")
+		resolvedLabel.Importance = widget.MediumImportance
+		resolvedLabel.Show()
+		resolveTimer = time.AfterFunc(600*time.Millisecond, func() {
+			r, err := resolveFederationAddress(text)
+			resolveMu.Lock()
+			defer resolveMu.Unlock()
+			if recipientEntry.Text != text {
+				return
+			}
+			if err == nil {
+				resolved = &r
+			}
+			applyResolved(resolved, err)
+		})
+	}
+	recipientEntry.OnChanged(recipientEntry.Text)
+
+	// Payments can instead go into a claimable balance, optionally only
+	// claimable after a delay
+	claimAfterEntry := widget.NewEntry()
+	claimAfterEntry.SetPlaceHolder("Now, a delay like 48h, or a date")
+	claimAfterEntry.Disable()
+	deliverSelect := widget.NewSelect([]string{"Payment", "Claimable Balance"}, func(selected string) {
+		if selected == "Claimable Balance" {
+			claimAfterEntry.Enable()
+		} else {
+			claimAfterEntry.Disable()
+		}
+	})
+	deliverSelect.SetSelected("Payment")
+
+	// For cold storage: build the transaction here and sign it elsewhere
+	offlineCheck := widget.NewCheck("Export unsigned XDR instead of submitting", nil)
+
+	// Base fee from current network conditions, which the user may override
+	feeEntry := widget.NewEntry()
+	feeEntry.SetText(strconv.FormatInt(networkBaseFee(), 10))
+
+	// Fill the form from a web+stellar:pay link, taken from the clipboard when
+	// it holds one
+	pasteLinkButton := widget.NewButton("Paste Payment Link", func() {
+		linkEntry := widget.NewMultiLineEntry()
+		linkEntry.Wrapping = fyne.TextWrapBreak
+		linkEntry.SetPlaceHolder("web+stellar:pay?destination=...")
+		if clip := window.Clipboard().Content(); strings.HasPrefix(strings.TrimSpace(clip), sep7Scheme+"pay") {
+			linkEntry.SetText(strings.TrimSpace(clip))
+		}
+		dialog.ShowForm("Payment Link", "Fill In", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Link", linkEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			params, err := paymentLinkParams(linkEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if _, held := balances[params.Asset]; !held {
+				dialog.ShowError(fmt.Errorf("the request is for %s, which this account doesn't hold", assetLabel(params.Asset)), window)
+				return
+			}
+			assetSelect.SetSelected(params.Asset)
+			recipientEntry.SetText(params.Recipient)
+			amountEntry.SetText(params.Amount)
+			if params.MemoType != "none" {
+				memoTypeSelect.SetSelected(params.MemoType)
+				memoEntry.SetText(params.Memo)
+			}
+		}, window)
+	})
+
+	// Fill in everything that can be sent, keeping back the reserve and fee.
+	// Worked out in stroops, so rounding can't push it over the balance.
+	recomputeMax = func() {
+		asset, err := parseAsset(assetSelect.Selected)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		baseFee, err := strconv.ParseInt(strings.TrimSpace(feeEntry.Text), 10, 64)
+		if err != nil {
+			baseFee = networkBaseFee()
+		}
+		source, err := accountRecords.fetch(client, wallet.Network, wallet.PublicKey)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+			return
+		}
+		spendable, err := maxSendable(source, asset, baseFee)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		// A claimable balance locks one more base reserve until it's claimed
+		if asset.IsNative() && deliverSelect.Selected == "Claimable Balance" {
+			spendable = max(spendable-baseReserve, 0)
+		}
+		if spendable == 0 {
+			maxed = false
+			dialog.ShowInformation("Max", fmt.Sprintf("Nothing can be sent: the whole %s balance is reserved.", assetCode(asset)), window)
+			return
+		}
+		amountEntry.SetText(amount.StringFromInt64(spendable))
+		maxed = true
+	}
+	amountEntry.OnChanged = func(string) {
+		maxed = false
+	}
+	maxButton := widget.NewButton("Max", recomputeMax)
+
+	// Read and check the form, resolving a federation recipient
+	collect := func() (sendParams, txnbuild.Asset, error) {
+		params := sendParams{Recipient: strings.TrimSpace(recipientEntry.Text), Amount: amountEntry.Text, Asset: assetSelect.Selected, Memo: memoEntry.Text, MemoType: memoTypeSelect.Selected}
+		if err := validateRecipient(params.Recipient); err != nil {
+			return sendParams{}, nil, err
+		}
+		if isFederationAddress(params.Recipient) {
+			resolveMu.Lock()
+			if resolveTimer != nil {
+				resolveTimer.Stop()
+			}
+			r := resolved
+			resolveMu.Unlock()
+			if r == nil {
+				lookup, err := resolveFederationAddress(params.Recipient)
+				if err != nil {
+					return sendParams{}, nil, err
+				}
+				r = &lookup
+			}
+			params.Recipient = r.AccountID
+			if r.Memo.Type != "none" {
+				params.MemoType, params.Memo = r.Memo.Type, r.Memo.Value
+			}
+		}
+		asset, err := parseAsset(params.Asset)
+		if err != nil {
+			return sendParams{}, nil, err
+		}
+		if _, err := buildMemo(params.memo()); err != nil {
+			return sendParams{}, nil, err
+		}
+		if err := validateAmount(params.Amount); err != nil {
+			return sendParams{}, nil, err
+		}
+		if isSelfPayment(params.Recipient, wallet.PublicKey) {
+			return sendParams{}, nil, errSelfPayment
+		}
+		if _, _, err := decodeMuxedAddress(params.Recipient); err == nil {
+			params.Memo, params.MemoType = "", "none"
+		}
+		baseFee, err := strconv.ParseInt(strings.TrimSpace(feeEntry.Text), 10, 64)
+		if err != nil || baseFee < txnbuild.MinBaseFee || baseFee > maxBaseFee {
+			return sendParams{}, nil, fmt.Errorf("base fee must be between %d and %d stroops", txnbuild.MinBaseFee, maxBaseFee)
+		}
+		params.BaseFee = baseFee
+		return params, asset, nil
+	}
+
+	// Check the payment against current account state without sending it
+	simulateButton := widget.NewButton("Simulate", func() {
+		params, asset, err := collect()
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		var op txnbuild.Operation = &txnbuild.Payment{Destination: params.Recipient, Amount: params.Amount, Asset: asset}
+		if deliverSelect.Selected == "Claimable Balance" {
+			claimAfter, err := parseClaimAfter(claimAfterEntry.Text, time.Now())
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			op = claimableBalanceOp(params.Recipient, params.Amount, asset, claimAfter)
+		}
+		showSimulation(params.Recipient, op, asset, params.Amount, params.BaseFee)
+	})
 
 	items := []*widget.FormItem{
+		widget.NewFormItem("", pasteLinkButton),
+		widget.NewFormItem("Asset", assetSelect),
+		widget.NewFormItem("Available", availableLabel),
+		widget.NewFormItem("Contact", contactSelect),
 		widget.NewFormItem("Recipient", recipientEntry),
-		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("", resolvedLabel),
+		widget.NewFormItem("Amount", container.NewBorder(nil, nil, nil, maxButton, amountEntry)),
+		widget.NewFormItem("Deliver As", deliverSelect),
+		widget.NewFormItem("Claimable After", claimAfterEntry),
+		widget.NewFormItem("Memo Type", memoTypeSelect),
 		widget.NewFormItem("Memo", memoEntry),
+		widget.NewFormItem("Base Fee (stroops)", feeEntry),
+		widget.NewFormItem("", offlineCheck),
+		widget.NewFormItem("", simulateButton),
+		widget.NewFormItem("", widget.NewButton("Calculator", func() {
+			showCalculatorDialog(assetSelect.Selected, "", amountEntry.Text)
+		})),
+		widget.NewFormItem("", widget.NewButton("Save as Template", func() {
+			showSaveTemplateDialog(sendParams{Recipient: recipientEntry.Text, Amount: amountEntry.Text, Asset: assetSelect.Selected, Memo: memoEntry.Text, MemoType: memoTypeSelect.Selected})
+		})),
 	}
 
-	dialog.ShowForm("Send XLM", "Send", "Cancel", items, func(submit bool) {
-		if submit {
-			sendXLM(recipientEntry.Text, amountEntry.Text, memoEntry.Text, balanceLabel)
+	submit := func() {
+		params, asset, err := collect()
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
 		}
-	}, window)
+		baseFee := params.BaseFee
+		if deliverSelect.Selected == "Claimable Balance" {
+			claimAfter, err := parseClaimAfter(claimAfterEntry.Text, time.Now())
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if offlineCheck.Checked {
+				exportUnsignedTransaction([]txnbuild.Operation{claimableBalanceOp(params.Recipient, params.Amount, asset, claimAfter)}, params.memo(), baseFee)
+				return
+			}
+			confirmSend(params, func() {
+				sendClaimableBalance(params.Recipient, params.Amount, params.memo(), asset, baseFee, claimAfter, balanceLabel)
+			})
+			return
+		}
+		if offlineCheck.Checked {
+			payment := &txnbuild.Payment{Destination: params.Recipient, Amount: params.Amount, Asset: asset}
+			exportUnsignedTransaction([]txnbuild.Operation{payment}, params.memo(), baseFee)
+			return
+		}
+		confirmSend(params, func() {
+			sendPayment(params.Recipient, params.Amount, params.memo(), asset, baseFee, balanceLabel)
+		})
+	}
+	return items, submit, amountEntry
 }
 
 func createMainUI() fyne.CanvasObject {
 	// Balance display
-	balanceLabel := widget.NewLabel(updateBalance())
+	balanceLabel := widget.NewLabel("")
+	fiatLabel = widget.NewLabel("")
+	fiatLabel.Hide()
+	refreshBalanceAsync(balanceLabel)
+	startPaymentStream(balanceLabel)
+
+	// Claimable balances addressed to the wallet, shown apart from the confirmed balance
+	pendingLabel := widget.NewLabel("")
+	pendingLabel.Hide()
+	go refreshPending(pendingLabel)
+
+	// Network fee stats, refreshed in the background along with the balance
+	feeLabel := widget.NewLabel("Loading fees...")
+	startPolling(balanceLabel, feeLabel)
+
+	// Filled in once built below, so a network change can refresh them
+	var (
+		tabs              *container.AppTabs
+		receiveTab        *container.TabItem
+		showTestnetButton func(network string)
+	)
 
 	// Network selection
 	networkSelect := widget.NewSelect([]string{"testnet", "public"}, func(network string) {
+		walletMu.Lock()
 		wallet.Network = network
+		walletMu.Unlock()
 		initializeClient(network)
+		resetBalanceState()
 		saveWallet()
-		balanceLabel.SetText(updateBalance())
+		refreshBalanceAsync(balanceLabel)
+		startPaymentStream(balanceLabel)
+		startPolling(balanceLabel, feeLabel)
+		go refreshPending(pendingLabel)
+		go refreshCapabilities()
+		go checkClockSkew()
+		if tabs != nil {
+			showTestnetButton(network)
+			receiveTab.Content = receivePanel()
+			tabs.OnSelected(tabs.Selected())
+			tabs.Refresh()
+		}
 	})
 	networkSelect.SetSelected(wallet.Network)
 
-	// Address display and copy button
-	addressEntry := widget.NewEntry()
-	addressEntry.SetText(wallet.PublicKey)
-	addressEntry.Disable()
+	// Account selection; switching rebuilds the window for the new account
+	accountSelect := widget.NewSelect(walletOptions(), nil)
+	accountSelect.SetSelectedIndex(store.ActiveIndex)
+	accountSelect.OnChanged = func(string) {
+		if i := accountSelect.SelectedIndex(); i != store.ActiveIndex {
+			switchWallet(i)
+		}
+	}
 
-	copyButton := widget.NewButton("Copy Address", func() {
-		addressEntry.SetText(wallet.PublicKey)
-		window := fyne.CurrentApp().Driver().AllWindows()[0]
-		dialog.ShowInformation("Success", "Address copied to clipboard!", window)
+	// Repeat the last successful payment, editable before sending
+	repeatButton := widget.NewButton("Repeat Last Send", func() {
+		params, ok := lastSend(settings)
+		if !ok {
+			window := fyne.CurrentApp().Driver().AllWindows()[0]
+			dialog.ShowInformation("Repeat Last Send", "No previous payment to repeat.", window)
+			return
+		}
+		showSendDialog(balanceLabel, params)
 	})
 
-	// Send XLM button
-	sendButton := widget.NewButton("Send XLM", func() {
-		showSendDialog(balanceLabel)
+	addAssetButton := widget.NewButton("Add Asset", func() {
+		showAddAssetDialog(balanceLabel)
 	})
 
-	// Transaction history button
-	historyButton := widget.NewButton("Transaction History", func() {
-		showTransactionHistory()
+	templatesButton := widget.NewButton("Templates", func() {
+		showTemplatesDialog(balanceLabel)
 	})
 
-	return container.NewVBox(
-		widget.NewLabel("Stellar Wallet"),
-		container.NewHBox(widget.NewLabel("Network:"), networkSelect),
-		balanceLabel,
-		container.NewHBox(addressEntry, copyButton),
-		sendButton,
-		historyButton,
+	importButton := widget.NewButton("Import Wallet", showImportWalletDialog)
+
+	// Only offered on testnet, where Friendbot can fund it
+	testnetButton := widget.NewButton("New Testnet Account", showNewTestnetAccountDialog)
+	showTestnetButton = func(network string) {
+		if network == "testnet" {
+			testnetButton.Enable()
+			testnetButton.Show()
+		} else {
+			testnetButton.Disable()
+			testnetButton.Hide()
+		}
+	}
+	showTestnetButton(wallet.Network)
+
+	// Actions collapse behind a menu button on narrow windows
+	actions := container.NewVBox(repeatButton, templatesButton, addAssetButton, importButton, testnetButton)
+	menuButton := actionsMenuButton(repeatButton, templatesButton, addAssetButton, importButton, testnetButton)
+
+	unfunded, friendbotButton := unfundedPanel(balanceLabel)
+	unfunded.Hide()
+
+	balances := container.New(&responsiveLayout{actions: actions, menuButton: menuButton},
+		container.NewHBox(balanceLabel, fiatLabel),
+		unfunded,
+		pendingLabel,
+		feeLabel,
+		actions,
+		menuButton,
+	)
+
+	// Send and History are rebuilt whenever they're opened so they show
+	// current balances and transactions
+	sendTab := container.NewTabItem("Send", widget.NewLabel(""))
+	historyTab := container.NewTabItem("History", widget.NewLabel(""))
+	receiveTab = container.NewTabItem("Receive", receivePanel())
+	tabs = container.NewAppTabs(
+		container.NewTabItem("Balances", container.NewVScroll(balances)),
+		sendTab,
+		receiveTab,
+		historyTab,
 	)
+	tabs.OnSelected = func(tab *container.TabItem) {
+		touchActivity()
+		switch tab {
+		case sendTab:
+			sendTab.Content = sendPanel(balanceLabel)
+		case historyTab:
+			historyTab.Content = historyPanel()
+		default:
+			return
+		}
+		tabs.Refresh()
+	}
+
+	// Sending and adding trustlines can't work until the account exists
+	fundedStateChanged = func(funded bool) {
+		if funded {
+			unfunded.Hide()
+			repeatButton.Enable()
+			templatesButton.Enable()
+			addAssetButton.Enable()
+			tabs.EnableItem(sendTab)
+			return
+		}
+		if wallet.Network == "testnet" {
+			friendbotButton.Show()
+		} else {
+			friendbotButton.Hide()
+		}
+		unfunded.Show()
+		repeatButton.Disable()
+		templatesButton.Disable()
+		addAssetButton.Disable()
+		if tabs.Selected() == sendTab {
+			tabs.SelectIndex(0)
+		}
+		tabs.DisableItem(sendTab)
+	}
+
+	refreshButton := widget.NewButton("Refresh", func() {
+		accountRecords.invalidate(wallet.Network, wallet.PublicKey)
+		refreshBalanceAsync(balanceLabel)
+		go refreshPending(pendingLabel)
+		tabs.OnSelected(tabs.Selected())
+	})
+
+	addMainShortcuts(fyne.CurrentApp().Driver().AllWindows()[0].Canvas(), refreshButton.OnTapped, func() {
+		if !sendTab.Disabled() {
+			showSendDialog(balanceLabel, sendParams{})
+		}
+	})
+
+	// Account, network and refresh stay available from every tab
+	toolbar := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Account:"), refreshButton, accountSelect),
+		container.NewBorder(nil, nil, widget.NewLabel("Network:"), nil, networkSelect),
+	)
+	return newActivityTracker(container.NewBorder(toolbar, nil, nil, nil, tabs))
 }
 
-func sendXLM(recipient, amount, memo string, balanceLabel *widget.Label) {
-	window := fyne.CurrentApp().Driver().AllWindows()[0]
+// Put text on the system clipboard via the main window, returning that window
+func copyToClipboard(text string) (fyne.Window, error) {
+	windows := fyne.CurrentApp().Driver().AllWindows()
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("no window available for clipboard access")
+	}
+	window := windows[0]
+	window.Clipboard().SetContent(text)
+	return window, nil
+}
 
-	// Input validation
-	if strings.TrimSpace(recipient) == "" || strings.TrimSpace(amount) == "" {
-		dialog.ShowError(fmt.Errorf("recipient and amount are required"), window)
-		return
+func buildMainMenu() *fyne.MainMenu {
+	resetGatedMenuItems()
+	fileMenu := fyne.NewMenu("File",
+		fyne.NewMenuItem("Settings...", showSettingsDialog),
+		fyne.NewMenuItem("Export Settings...", exportSettings),
+		fyne.NewMenuItem("Import Settings...", importSettings),
+		fyne.NewMenuItem("Export Activity...", showExportActivityDialog),
+		fyne.NewMenuItem("Contacts...", showContactsDialog),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Lock Now", func() {
+			lockWallet(fyne.CurrentApp().Driver().AllWindows()[0])
+		}),
+	)
+	accountMenu := fyne.NewMenu("Account",
+		fyne.NewMenuItem("Account Flags...", showAccountFlagsDialog),
+		fyne.NewMenuItem("Account Security...", showAccountSecurityDialog),
+		fyne.NewMenuItem("Account Data...", showAccountDataDialog),
+		fyne.NewMenuItem("Muxed Address...", showMuxedAddressDialog),
+		fyne.NewMenuItem("Balance Alerts...", showBalanceAlertsDialog),
+		fyne.NewMenuItem("Inflation & Pools...", showParticipationDialog),
+		fyne.NewMenuItem("Consolidate Dust...", showDustDialog),
+		fyne.NewMenuItem("View Raw...", showRawAccountDialog),
+		fyne.NewMenuItem("Account Age...", showAccountAgeDialog),
+		fyne.NewMenuItem("New Account...", showNewAccountDialog),
+		fyne.NewMenuItem("Remove Account...", showRemoveAccountDialog),
+		fyne.NewMenuItem("Merge Account...", showMergeAccountDialog),
+		fyne.NewMenuItem("Accounts from Phrase...", showDeriveAccountsDialog),
+		fyne.NewMenuItem("Reveal Recovery Phrase...", showRevealPhraseDialog),
+	)
+	toolsMenu := fyne.NewMenu("Tools",
+		gateMenuItem(featurePaths, fyne.NewMenuItem("Path Payment...", showPathPaymentDialog)),
+		gateMenuItem(featureClaimableBalances, fyne.NewMenuItem("Claim Balance...", showClaimBalanceDialog)),
+		gateMenuItem(featureClaimableBalances, fyne.NewMenuItem("Claimable Balances...", showClaimableBalancesDialog)),
+		gateMenuItem(featureClaimableBalances, fyne.NewMenuItem("Airdrop...", showAirdropDialog)),
+		gateMenuItem(featureOffers, fyne.NewMenuItem("Offers...", showOffersDialog)),
+		gateMenuItem(featureOffers, fyne.NewMenuItem("Cancel All Offers...", showCancelAllOffersDialog)),
+		gateMenuItem(featureOrderBook, fyne.NewMenuItem("Order Book...", showOrderBookWindow)),
+		fyne.NewMenuItem("Compare Networks...", showNetworkComparison),
+		fyne.NewMenuItem("Cost Estimator...", showCostEstimator),
+		fyne.NewMenuItem("Pre-Authorized Transaction...", showPreAuthDialog),
+		fyne.NewMenuItem("Submit Transaction File...", openTransactionFile),
+		fyne.NewMenuItem("Import Signed XDR...", showImportSignedXDRDialog),
+		fyne.NewMenuItem("Open Transaction Link...", showOpenTransactionLinkDialog),
+		fyne.NewMenuItem("Fee Bump Transaction...", showFeeBumpDialog),
+		fyne.NewMenuItem("Onboard Recipient...", showOnboardDialog),
+	)
+	// Using the menu counts as activity for the auto-lock
+	for _, menu := range []*fyne.Menu{fileMenu, accountMenu, toolsMenu} {
+		for _, item := range menu.Items {
+			if action := item.Action; action != nil {
+				item.Action = func() {
+					touchActivity()
+					action()
+				}
+			}
+		}
 	}
+	return fyne.NewMainMenu(fileMenu, accountMenu, toolsMenu)
+}
 
-	// Make sure destination account exists
-	destAccountRequest := horizonclient.AccountRequest{AccountID: recipient}
-	_, err := client.AccountDetail(destAccountRequest)
-	if err != nil {
-		dialog.ShowError(fmt.Errorf("destination account does not exist: %v", err), window)
-		return
+// Check a payment of amount of asset from source to recipient and build its
+// operation and memo, looking accounts up through hc. Nothing is shown to the
+// user; the caller reports the error.
+func preparePayment(hc core.HorizonAPI, network, source, recipient, amount string, memo memoSpec, asset txnbuild.Asset, baseFee int64) (*txnbuild.Payment, txnbuild.Memo, error) {
+	if strings.TrimSpace(recipient) == "" || strings.TrimSpace(amount) == "" {
+		return nil, nil, fmt.Errorf("recipient and amount are required")
 	}
 
-	// Load the source account
-	sourceKP := keypair.MustParseFull(wallet.SecretKey)
-	sourceAccountRequest := horizonclient.AccountRequest{AccountID: sourceKP.Address()}
-	sourceAccount, err := client.AccountDetail(sourceAccountRequest)
+	// Catch addresses and amounts the network would reject before any round trip
+	if err := validateRecipient(recipient); err != nil {
+		return nil, nil, err
+	}
+	if err := validateAmount(amount); err != nil {
+		return nil, nil, err
+	}
+	if isSelfPayment(recipient, source) {
+		return nil, nil, errSelfPayment
+	}
+	payment, err := core.PaymentOp(recipient, amount, asset)
 	if err != nil {
-		dialog.ShowError(fmt.Errorf("source account does not exist: %v", err), window)
-		return
+		return nil, nil, err
 	}
-
-	// Validate amount
-	_, err = strconv.ParseFloat(amount, 64)
+	txMemo, err := buildMemo(memo)
 	if err != nil {
-		dialog.ShowError(fmt.Errorf("invalid amount: %v", err), window)
-		return
+		return nil, nil, err
 	}
 
-	// Build transaction
-	tx, err := txnbuild.NewTransaction(
-		txnbuild.TransactionParams{
-			SourceAccount:        &sourceAccount,
-			IncrementSequenceNum: true,
-			BaseFee:              txnbuild.MinBaseFee,
-			Preconditions: txnbuild.Preconditions{
-				TimeBounds: txnbuild.NewTimeout(300),
-			},
-			Operations: []txnbuild.Operation{
-				&txnbuild.Payment{
-					Destination: recipient,
-					Amount:      amount,
-					Asset:       txnbuild.NativeAsset{},
-				},
-			},
-			Memo: txnbuild.MemoText(memo),
-		},
-	)
+	// Muxed addresses are paid directly, but the account checks below are on
+	// the G... account underneath
+	destination, err := core.DestinationAccount(recipient)
 	if err != nil {
-		log.Println(err)
-		dialog.ShowError(fmt.Errorf("error building transaction: %v", err), window)
-		return
+		return nil, nil, err
 	}
-
-	// Sign the transaction
-	tx, err = tx.Sign(network.TestNetworkPassphrase, sourceKP)
+	destAccount, err := accountRecords.fetch(hc, network, destination)
 	if err != nil {
-		log.Println(err)
-		dialog.ShowError(fmt.Errorf("error signing transaction: %v", err), window)
-		return
+		return nil, nil, fmt.Errorf("destination account does not exist: %v", err)
 	}
 
-	// Submit transaction
-	resp, err := client.SubmitTransaction(tx)
-	if err != nil {
-		log.Println(err)
-		dialog.ShowError(fmt.Errorf("error submitting transaction: %v", err), window)
-		return
+	// Credit assets can only be received over an authorized trustline
+	if err := trustlineError(destAccount, asset); err != nil {
+		return nil, nil, err
 	}
 
-	dialog.ShowInformation("Success", fmt.Sprintf("Transaction successful! Hash: %s", resp.Hash), window)
-	balanceLabel.SetText(updateBalance())
+	// XLM sends must leave the minimum reserve and the fee behind
+	if asset.IsNative() {
+		sourceAccount, err := accountRecords.fetch(hc, network, source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading account: %v", err)
+		}
+		if err := checkReserve(sourceAccount, amount, baseFee); err != nil {
+			return nil, nil, err
+		}
+	}
+	return payment, txMemo, nil
 }
 
-func showTransactionHistory() {
+func sendPayment(recipient, amount string, memo memoSpec, asset txnbuild.Asset, baseFee int64, balanceLabel *widget.Label) {
 	window := fyne.CurrentApp().Driver().AllWindows()[0]
 
-	// Get transactions
-	transactions, err := client.Transactions(horizonclient.TransactionRequest{
-		ForAccount: wallet.PublicKey,
-		Limit:      20,
-	})
+	payment, txMemo, err := preparePayment(client, wallet.Network, wallet.PublicKey, recipient, amount, memo, asset, baseFee)
 	if err != nil {
-		dialog.ShowError(fmt.Errorf("error loading transactions: %v", err), window)
+		dialog.ShowError(err, window)
 		return
 	}
+	submitWithFee([]txnbuild.Operation{payment}, txMemo, baseFee, nil, func(hash string) {
+		rememberLastSend(&settings, sendParams{Recipient: recipient, Amount: amount, Asset: assetString(asset), Memo: memo.Value, MemoType: memo.Type})
+		if err := saveSettings(); err != nil {
+			log.Println(err)
+		}
+
+		if balanceLabel != nil {
+			refreshBalanceAsync(balanceLabel)
+		}
+		message := fmt.Sprintf("Transaction successful! Hash: %s", hash)
+		if _, known := contactForAddress(settings.Contacts, recipient); known {
+			dialog.ShowInformation("Success", message, window)
+			return
+		}
+		// Offer to remember a new recipient
+		label := widget.NewLabel(message)
+		label.Wrapping = fyne.TextWrapBreak
+		dialog.ShowCustomConfirm("Success", "Save as Contact", "Close", label, func(save bool) {
+			if save {
+				showSaveContactDialog(recipient, memo)
+			}
+		}, window)
+	})
+}
 
-	// Create list of transactions
-	var items []string
-	for _, tx := range transactions.Embedded.Records {
-		items = append(items, fmt.Sprintf("Hash: %s\nCreated: %s\nFee: %d XLM",
-			tx.Hash, tx.LedgerCloseTime, tx.FeeCharged))
+const historyPageSize = 20
+
+// Pages of history loaded so far. Pages load in the background while the list
+// looks up tapped entries, so both go through mu.
+type historyPager struct {
+	fetch activityFetcher
+
+	mu     sync.Mutex
+	cursor string
+	hashes []string // parallel to the list items
+}
+
+// Fetch the page after the last one loaded, returning its records, or false
+// once there are no more
+func (p *historyPager) next() ([]activityRecord, bool, error) {
+	p.mu.Lock()
+	cursor := p.cursor
+	p.mu.Unlock()
+
+	page, next, err := p.fetch(cursor)
+	if err != nil || next == cursor {
+		return nil, false, err
 	}
 
-	list := widget.NewTextGrid()
-	list.SetText(strings.Join(items, "\n\n"))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cursor = next
+	for _, record := range page {
+		p.hashes = append(p.hashes, record.Hash)
+	}
+	return page, true, nil
+}
 
-	dialog.ShowCustom("Transaction History", "Close",
-		container.NewScroll(list), window)
+// Whether any page has been loaded yet
+func (p *historyPager) started() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cursor != ""
+}
+
+// Transaction hash of list entry id
+func (p *historyPager) hash(id int) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if id < 0 || id >= len(p.hashes) {
+		return "", false
+	}
+	return p.hashes[id], true
+}
+
+// Payments in and out of the wallet, with the transaction each belongs to,
+// loaded a page at a time in the background
+func historyPanel() fyne.CanvasObject {
+	session := activeSession()
+	pager := &historyPager{fetch: func(cursor string) ([]activityRecord, string, error) {
+		return session.Payments(cursor, historyPageSize)
+	}}
+
+	items := binding.NewStringList()
+	list := widget.NewListWithData(items,
+		func() fyne.CanvasObject { return widget.NewLabel("\n\n") },
+		func(item binding.DataItem, object fyne.CanvasObject) {
+			object.(*widget.Label).Bind(item.(binding.String))
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		list.Unselect(id)
+		if hash, ok := pager.hash(id); ok {
+			openInExplorer("tx", hash)
+		}
+	}
+
+	// Load errors stay in the panel rather than popping up over whatever
+	// tab is open by the time the request fails
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	status.Importance = widget.DangerImportance
+	status.Hide()
+
+	var loadMore *widget.Button
+	load := func() {
+		loadMore.Disable()
+		status.Hide()
+		go func() {
+			started := pager.started()
+			page, ok, err := pager.next()
+			if err != nil {
+				status.SetText(fmt.Sprintf("Error loading transactions: %v", err))
+				status.Show()
+				loadMore.Enable()
+				return
+			}
+			if !ok {
+				if started {
+					loadMore.SetText("No More Transactions")
+				} else {
+					loadMore.SetText("No Transactions")
+				}
+				return
+			}
+			for _, record := range page {
+				items.Append(historyItem(record))
+			}
+			loadMore.Enable()
+		}()
+	}
+	loadMore = widget.NewButton("Load More", load)
+	load()
+
+	exportButton := widget.NewButton("Export CSV", showExportActivityDialog)
+
+	hint := widget.NewLabel("Tap a transaction to open it in the explorer.")
+	bottom := container.NewVBox(status, container.NewGridWithColumns(2, loadMore, exportButton))
+	return container.NewBorder(hint, bottom, nil, nil, list)
 }
 
 func main() {
+	opts, err := parseLaunchOptions(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	launch = opts
+
 	myApp := app.New()
 	myWindow := myApp.NewWindow("Stellar Wallet")
 
+	if err := loadSettings(); err != nil {
+		log.Printf("error loading settings, using defaults: %v", err)
+	}
+	applyTheme()
+
+	// Horizon requests time out, and any still running are abandoned when
+	// the window closes
+	core.SetRequestTimeout(horizonTimeout())
+	ctx, cancelRequests := context.WithCancel(context.Background())
+	core.SetContext(ctx)
+
+	// A wallet that can't be loaded safely is explained, then the app quits
 	if err := loadWallet(); err != nil {
-		log.Fatal(err)
+		log.Println(err)
+		myWindow.SetContent(lockedContent())
+		errDialog := dialog.NewError(fmt.Errorf("error loading wallet: %v", err), myWindow)
+		errDialog.SetOnClosed(myApp.Quit)
+		errDialog.Show()
+		myWindow.ShowAndRun()
+		return
 	}
 
-	myWindow.SetContent(createMainUI())
-	myWindow.Resize(fyne.NewSize(360, 640))
+	myWindow.SetOnClosed(func() {
+		stopPaymentStream()
+		stopPolling()
+		cancelRequests()
+		recordExportProgress()
+		size := myWindow.Canvas().Size()
+		settings.WindowWidth, settings.WindowHeight = size.Width, size.Height
+		if err := saveSettings(); err != nil {
+			log.Println(err)
+		}
+	})
+	myWindow.SetContent(lockedContent())
+	myWindow.Resize(windowSize(settings))
+	myWindow.Canvas().SetOnTypedKey(func(*fyne.KeyEvent) { touchActivity() })
+	unlockWallet(myWindow, func() { showMainWindow(myWindow) })
+	if len(walletWarnings) > 0 {
+		dialog.ShowInformation("Wallet Repaired", strings.Join(walletWarnings, "\n\n"), myWindow)
+	}
 	myWindow.ShowAndRun()
 }
+
+// Fill the window once the wallet is unlocked
+func showMainWindow(window fyne.Window) {
+	window.SetMainMenu(buildMainMenu())
+	window.SetContent(createMainUI())
+	go refreshCapabilities()
+	go checkClockSkew()
+	startAutoLock(window)
+}
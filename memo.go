@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+var memoTypes = []string{"none", "text", "id", "hash", "return"}
+
+// A memo as entered by the user, before it is parsed
+type memoSpec struct {
+	Type  string `json:"type"` // one of memoTypes
+	Value string `json:"value,omitempty"`
+}
+
+// Parse a memo spec into the transaction memo it describes
+func buildMemo(spec memoSpec) (txnbuild.Memo, error) {
+	value := strings.TrimSpace(spec.Value)
+	switch spec.Type {
+	case "", "none":
+		return nil, nil
+	case "text":
+		if len(value) > 28 {
+			return nil, fmt.Errorf("text memo must be at most 28 bytes, got %d", len(value))
+		}
+		return txnbuild.MemoText(value), nil
+	case "id":
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("ID memo must be a whole number between 0 and %d", uint64(math.MaxUint64))
+		}
+		return txnbuild.MemoID(id), nil
+	case "hash", "return":
+		raw, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s memo must be hexadecimal", spec.Type)
+		}
+		if len(raw) != 32 {
+			return nil, fmt.Errorf("%s memo must be 32 bytes (64 hex characters), got %d bytes", spec.Type, len(raw))
+		}
+		var h [32]byte
+		copy(h[:], raw)
+		if spec.Type == "hash" {
+			return txnbuild.MemoHash(h), nil
+		}
+		return txnbuild.MemoReturn(h), nil
+	}
+	return nil, fmt.Errorf("unknown memo type %q", spec.Type)
+}
+
+// Hint for the memo value entry for each memo type
+func memoPlaceholder(memoType string) string {
+	switch memoType {
+	case "text":
+		return "Up to 28 characters"
+	case "id":
+		return "Numeric ID"
+	case "hash", "return":
+		return "64 hex characters"
+	}
+	return "No memo"
+}
+
+// Memo of a send; older saved sends only had text memos
+func (p sendParams) memo() memoSpec {
+	switch {
+	case p.MemoType != "":
+		return memoSpec{Type: p.MemoType, Value: p.Memo}
+	case p.Memo != "":
+		return memoSpec{Type: "text", Value: p.Memo}
+	}
+	return memoSpec{Type: "none"}
+}
+
+// Memo to start a send with: the prefilled send's own memo if it has one,
+// otherwise the configured default. Whatever the user then enters wins.
+func initialMemo(defaultMemo *memoSpec, prefill sendParams) memoSpec {
+	if prefill.Memo != "" || prefill.MemoType != "" {
+		return prefill.memo()
+	}
+	if defaultMemo != nil && defaultMemo.Type != "" {
+		return *defaultMemo
+	}
+	return memoSpec{Type: "none"}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestBuildMemo(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	var h [32]byte
+	for i := range h {
+		h[i] = 0xab
+	}
+
+	tests := []struct {
+		name    string
+		spec    memoSpec
+		want    txnbuild.Memo
+		wantErr bool
+	}{
+		{"empty type", memoSpec{}, nil, false},
+		{"none", memoSpec{Type: "none", Value: "ignored"}, nil, false},
+		{"text", memoSpec{Type: "text", Value: " hello "}, txnbuild.MemoText("hello"), false},
+		{"text at limit", memoSpec{Type: "text", Value: strings.Repeat("x", 28)}, txnbuild.MemoText(strings.Repeat("x", 28)), false},
+		{"text too long", memoSpec{Type: "text", Value: strings.Repeat("x", 29)}, nil, true},
+		{"text counts bytes", memoSpec{Type: "text", Value: strings.Repeat("é", 15)}, nil, true},
+		{"id", memoSpec{Type: "id", Value: "18446744073709551615"}, txnbuild.MemoID(18446744073709551615), false},
+		{"id negative", memoSpec{Type: "id", Value: "-1"}, nil, true},
+		{"id overflow", memoSpec{Type: "id", Value: "18446744073709551616"}, nil, true},
+		{"hash", memoSpec{Type: "hash", Value: hash}, txnbuild.MemoHash(h), false},
+		{"return", memoSpec{Type: "return", Value: hash}, txnbuild.MemoReturn(h), false},
+		{"hash not hex", memoSpec{Type: "hash", Value: strings.Repeat("zz", 32)}, nil, true},
+		{"hash too short", memoSpec{Type: "hash", Value: "abcd"}, nil, true},
+		{"unknown type", memoSpec{Type: "binary", Value: "1"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildMemo(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildMemo(%+v) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildMemo(%+v) = %#v, want %#v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoPlaceholder(t *testing.T) {
+	tests := map[string]string{
+		"none":   "No memo",
+		"text":   "Up to 28 characters",
+		"id":     "Numeric ID",
+		"hash":   "64 hex characters",
+		"return": "64 hex characters",
+	}
+	for memoType, want := range tests {
+		if got := memoPlaceholder(memoType); got != want {
+			t.Errorf("memoPlaceholder(%q) = %q, want %q", memoType, got, want)
+		}
+	}
+}
+
+func TestInitialMemo(t *testing.T) {
+	defaultMemo := &memoSpec{Type: "id", Value: "12345"}
+	tests := []struct {
+		name        string
+		defaultMemo *memoSpec
+		prefill     sendParams
+		want        memoSpec
+	}{
+		{"link memo over default", defaultMemo, sendParams{Recipient: testOther, Memo: "42", MemoType: "id"}, memoSpec{Type: "id", Value: "42"}},
+		{"federation text memo over default", defaultMemo, sendParams{Recipient: testOther, Memo: "alice"}, memoSpec{Type: "text", Value: "alice"}},
+		{"explicit none over default", defaultMemo, sendParams{Recipient: testOther, MemoType: "none"}, memoSpec{Type: "none"}},
+		{"default without link memo", defaultMemo, sendParams{Recipient: testOther}, *defaultMemo},
+		{"empty default", &memoSpec{}, sendParams{}, memoSpec{Type: "none"}},
+		{"no default", nil, sendParams{}, memoSpec{Type: "none"}},
+		{"link memo without default", nil, sendParams{Memo: "x", MemoType: "text"}, memoSpec{Type: "text", Value: "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := initialMemo(tt.defaultMemo, tt.prefill); got != tt.want {
+				t.Errorf("initialMemo = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
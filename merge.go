@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Why an account can't be merged yet, if it can be seen before submitting
+func mergeBlocker(subentries int32) string {
+	if subentries == 0 {
+		return ""
+	}
+	return fmt.Sprintf("This account still has %d trustlines, offers, signers or data entries. Remove them all before merging.", subentries)
+}
+
+// Sweep the whole XLM balance to another account and close this one
+func showMergeAccountDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	destinationEntry := widget.NewEntry()
+	destinationEntry.SetPlaceHolder("Destination address")
+	warning := widget.NewLabel("Merging sends all of this account's XLM to the destination and closes the account permanently. " +
+		"All other balances, trustlines, offers and data entries must be removed first.")
+	warning.Wrapping = fyne.TextWrapWord
+	warning.Importance = widget.DangerImportance
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", warning),
+		widget.NewFormItem("Destination", destinationEntry),
+	}
+
+	dialog.ShowForm("Merge Account", "Continue", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		destination := strings.TrimSpace(destinationEntry.Text)
+		if !strkey.IsValidEd25519PublicKey(destination) && !strkey.IsValidMuxedAccountEd25519PublicKey(destination) {
+			dialog.ShowError(fmt.Errorf("invalid destination address"), window)
+			return
+		}
+		if isSelfPayment(destination, wallet.PublicKey) {
+			dialog.ShowError(fmt.Errorf("cannot merge an account into itself"), window)
+			return
+		}
+
+		account, err := activeSession().Account()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+			return
+		}
+		if blocker := mergeBlocker(account.SubentryCount); blocker != "" {
+			dialog.ShowError(fmt.Errorf("%s", blocker), window)
+			return
+		}
+		balance, _ := nativeBalance(account)
+
+		message := fmt.Sprintf("Close %s\nand send its %s XLM (less the fee) to\n%s\non %s?\n\nThis cannot be undone.",
+			wallet.PublicKey, balance, destination, wallet.Network)
+		dialog.ShowConfirm("Confirm Merge", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			merge := &txnbuild.AccountMerge{Destination: destination}
+			submitWithFeedback([]txnbuild.Operation{merge}, nil, func(hash string) {
+				dialog.ShowInformation("Account Merged",
+					fmt.Sprintf("The account was merged and no longer exists.\nHash: %s\n\nYou can remove it from the wallet with Account > Remove Account.", hash), window)
+			})
+		}, window)
+	}, window)
+}
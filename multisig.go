@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Threshold category an operation is checked against: "low", "medium" or "high"
+func operationThreshold(op txnbuild.Operation) string {
+	switch o := op.(type) {
+	case *txnbuild.AccountMerge:
+		return "high"
+	case *txnbuild.SetOptions:
+		if o.MasterWeight != nil || o.LowThreshold != nil || o.MediumThreshold != nil || o.HighThreshold != nil || o.Signer != nil {
+			return "high"
+		}
+	case *txnbuild.AllowTrust, *txnbuild.SetTrustLineFlags, *txnbuild.BumpSequence, *txnbuild.ClaimClaimableBalance:
+		return "low"
+	}
+	return "medium"
+}
+
+// Signature weight a transaction of ops needs from account. Any signature
+// must have a weight of at least one, even with a zero threshold.
+func requiredWeight(account horizon.Account, ops []txnbuild.Operation) int {
+	need := 1
+	for _, op := range ops {
+		var t byte
+		switch operationThreshold(op) {
+		case "high":
+			t = account.Thresholds.HighThreshold
+		case "low":
+			t = account.Thresholds.LowThreshold
+		default:
+			t = account.Thresholds.MedThreshold
+		}
+		if int(t) > need {
+			need = int(t)
+		}
+	}
+	return need
+}
+
+// Combined weight of the given keys as signers of account, each counted once
+func signatureWeight(account horizon.Account, addresses []string) int {
+	seen := make(map[string]bool, len(addresses))
+	total := 0
+	for _, address := range addresses {
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+		for _, signer := range account.Signers {
+			if signer.Key == address {
+				total += int(signer.Weight)
+			}
+		}
+	}
+	return total
+}
+
+func isBadAuth(codes *horizon.TransactionResultCodes) bool {
+	if transactionCode(codes) == "tx_bad_auth" {
+		return true
+	}
+	for _, f := range operationFailures(codes) {
+		if f.Code == "op_bad_auth" {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse secret seeds typed one per line
+func parseCosignerSeeds(text string) ([]*keypair.Full, error) {
+	var kps []*keypair.Full
+	for _, line := range strings.Fields(text) {
+		kp, err := keypair.ParseFull(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret seed %s...: it should start with S and be 56 characters long", shortSeedPrefix(line))
+		}
+		kps = append(kps, kp)
+	}
+	if len(kps) == 0 {
+		return nil, fmt.Errorf("enter at least one secret seed")
+	}
+	return kps, nil
+}
+
+// Enough of a mistyped seed to find it without showing the secret
+func shortSeedPrefix(seed string) string {
+	if len(seed) > 4 {
+		return seed[:4]
+	}
+	return seed
+}
+
+// Explain a rejection for missing signatures and offer to add more signers
+// or export the partially signed envelope for the others
+func showMoreSignersDialog(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, cosigners []*keypair.Full, onSuccess func(hash string)) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	addresses := []string{wallet.PublicKey}
+	for _, kp := range cosigners {
+		addresses = append(addresses, kp.Address())
+	}
+	message := "The transaction doesn't have enough signatures."
+	if account, err := activeSession().Account(); err == nil {
+		have, need := signatureWeight(account, addresses), requiredWeight(account, ops)
+		message = fmt.Sprintf("This transaction needs signature weight %d, but the keys used so far have weight %d.\n"+
+			"%d more is needed from this account's other signers.", need, have, need-have)
+	}
+
+	seedsEntry := widget.NewMultiLineEntry()
+	seedsEntry.SetPlaceHolder("Secret seeds of other signers, one per line")
+	info := widget.NewLabel(message)
+	info.Wrapping = fyne.TextWrapWord
+
+	d := dialog.NewForm("More Signatures Needed", "Sign & Submit", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("", info),
+		widget.NewFormItem("Signers", seedsEntry),
+		widget.NewFormItem("", widget.NewButton("Export Partially Signed...", func() {
+			extra, _ := parseCosignerSeeds(seedsEntry.Text)
+			exportPartiallySigned(ops, memo, baseFee, append(cosigners, extra...))
+		})),
+	}, func(submit bool) {
+		if !submit {
+			return
+		}
+		extra, err := parseCosignerSeeds(seedsEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		submitWithFee(ops, memo, baseFee, append(cosigners, extra...), onSuccess)
+	}, window)
+	d.Resize(fyne.NewSize(scaled(420), d.MinSize().Height))
+	d.Show()
+}
+
+// Sign with the wallet key and any cosigners, then hand the envelope over for
+// the remaining signers to add theirs
+func exportPartiallySigned(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, cosigners []*keypair.Full) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	kp, err := keypair.ParseFull(wallet.SecretKey)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("invalid wallet secret key: %v", err), window)
+		return
+	}
+	session := activeSession()
+	tx, err := session.Build(ops, memo, baseFee, kp.Address())
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	tx, err = tx.Sign(session.Passphrase(), append([]*keypair.Full{kp}, cosigners...)...)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error signing transaction: %v", err), window)
+		return
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error encoding transaction: %v", err), window)
+		return
+	}
+	showEnvelope("Partially Signed Transaction", envelope,
+		fmt.Sprintf("Signed by %d key(s). Have the remaining signers sign it, then submit it with Import Signed XDR.", len(tx.Signatures())))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// M... address combining a G... account with a numeric ID
+func muxedAddress(accountID string, id uint64) (string, error) {
+	muxed, err := xdr.MuxedAccountFromAccountId(accountID, id)
+	if err != nil {
+		return "", fmt.Errorf("invalid account %q: %v", accountID, err)
+	}
+	return muxed.GetAddress()
+}
+
+var errSelfPayment = errors.New("cannot send to your own address")
+
+// Whether paying recipient, a G... or M... address, would pay accountID itself
+func isSelfPayment(recipient, accountID string) bool {
+	recipient = strings.TrimSpace(recipient)
+	if recipient == accountID {
+		return true
+	}
+	if underlying, _, err := decodeMuxedAddress(recipient); err == nil {
+		return underlying == accountID
+	}
+	return false
+}
+
+// Check a recipient is a well-formed G... or M... address, or a federation
+// address to resolve, before anything is looked up on the network
+func validateRecipient(address string) error {
+	address = strings.TrimSpace(address)
+	switch {
+	case address == "":
+		return errors.New("recipient is required")
+	case isFederationAddress(address):
+		return nil
+	case strkey.IsValidEd25519PublicKey(address), strkey.IsValidMuxedAccountEd25519PublicKey(address):
+		return nil
+	case strings.HasPrefix(address, "S"):
+		return errors.New("invalid Stellar address: that looks like a secret seed, never share it")
+	case !strings.HasPrefix(address, "G") && !strings.HasPrefix(address, "M"):
+		return errors.New("invalid Stellar address: it should start with G or M, or be a name*domain address")
+	case len(address) != 56 && len(address) != 69:
+		return fmt.Errorf("invalid Stellar address: it is %d characters long, expected 56 (G...) or 69 (M...)", len(address))
+	}
+	return errors.New("invalid Stellar address: the checksum doesn't match, check it for typos")
+}
+
+// Split an M... address into its underlying G... account and ID
+func decodeMuxedAddress(address string) (string, uint64, error) {
+	muxed, err := xdr.AddressToMuxedAccount(address)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid address %q: %v", address, err)
+	}
+	if muxed.Type != xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+		return "", 0, fmt.Errorf("%s is not a muxed address", address)
+	}
+
+	id, err := muxed.GetId()
+	if err != nil {
+		return "", 0, err
+	}
+	accountID := muxed.ToAccountId()
+	return accountID.Address(), id, nil
+}
+
+func showMuxedAddressDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	idEntry := widget.NewEntry()
+	idEntry.SetPlaceHolder("Numeric ID")
+	muxedEntry := widget.NewEntry()
+	muxedEntry.SetPlaceHolder("M... address")
+	resultLabel := widget.NewLabel("")
+	resultLabel.Wrapping = fyne.TextWrapBreak
+
+	// Encode as the user types an ID, decode as they type an M address
+	idEntry.OnChanged = func(text string) {
+		id, err := strconv.ParseUint(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			resultLabel.SetText("ID must be a number between 0 and 18446744073709551615")
+			return
+		}
+		address, err := muxedAddress(wallet.PublicKey, id)
+		if err != nil {
+			resultLabel.SetText(err.Error())
+			return
+		}
+		muxedEntry.SetText(address)
+	}
+	muxedEntry.OnChanged = func(text string) {
+		accountID, id, err := decodeMuxedAddress(strings.TrimSpace(text))
+		if err != nil {
+			resultLabel.SetText(err.Error())
+			return
+		}
+		resultLabel.SetText(fmt.Sprintf("Account: %s\nID: %d", accountID, id))
+	}
+
+	copyButton := widget.NewButton("Copy Muxed Address", func() {
+		if muxedEntry.Text == "" {
+			return
+		}
+		window.Clipboard().SetContent(muxedEntry.Text)
+		dialog.ShowInformation("Success", "Muxed address copied to clipboard!", window)
+	})
+
+	content := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("ID", idEntry),
+			widget.NewFormItem("Muxed", muxedEntry),
+		),
+		resultLabel,
+		copyButton,
+	)
+	dialog.ShowCustom("Muxed Address", "Close", content, window)
+}
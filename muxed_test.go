@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRecipient(t *testing.T) {
+	muxed, err := muxedAddress(testOther, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typo := testOther[:55] + "A"
+
+	tests := []struct {
+		name    string
+		address string
+		errText string // substring of the error, empty when valid
+	}{
+		{"account", testOther, ""},
+		{"account with spaces", " " + testOther + " ", ""},
+		{"muxed", muxed, ""},
+		{"federation", "alice*example.com", ""},
+		{"empty", "  ", "required"},
+		{"secret seed", "SBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", "secret seed"},
+		{"wrong prefix", "XAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7", "start with G or M"},
+		{"too short", testOther[:40], "40 characters"},
+		{"checksum", typo, "checksum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRecipient(tt.address)
+			switch {
+			case tt.errText == "" && err != nil:
+				t.Errorf("validateRecipient(%q) = %v", tt.address, err)
+			case tt.errText != "" && (err == nil || !strings.Contains(err.Error(), tt.errText)):
+				t.Errorf("validateRecipient(%q) = %v, want an error mentioning %q", tt.address, err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestMuxedAddressRoundTrip(t *testing.T) {
+	for _, id := range []uint64{0, 42, 18446744073709551615} {
+		address, err := muxedAddress(testOther, id)
+		if err != nil {
+			t.Fatalf("muxedAddress(%d): %v", id, err)
+		}
+		if !strings.HasPrefix(address, "M") || len(address) != 69 {
+			t.Errorf("muxedAddress(%d) = %q", id, address)
+		}
+		accountID, gotID, err := decodeMuxedAddress(address)
+		if err != nil || accountID != testOther || gotID != id {
+			t.Errorf("decodeMuxedAddress(%q) = %q, %d, %v, want %q, %d", address, accountID, gotID, err, testOther, id)
+		}
+	}
+
+	if _, err := muxedAddress("GBAD", 1); err == nil {
+		t.Error("muxedAddress accepted an invalid account")
+	}
+	for _, address := range []string{testOther, "", "MBAD"} {
+		if _, _, err := decodeMuxedAddress(address); err == nil {
+			t.Errorf("decodeMuxedAddress(%q) accepted", address)
+		}
+	}
+}
+
+func TestIsSelfPayment(t *testing.T) {
+	ownMuxed, _ := muxedAddress(testWallet, 7)
+	otherMuxed, _ := muxedAddress(testOther, 7)
+
+	tests := []struct {
+		name      string
+		recipient string
+		expected  bool
+	}{
+		{"own account", testWallet, true},
+		{"own account with spaces", " " + testWallet + "\n", true},
+		{"own muxed", ownMuxed, true},
+		{"other account", testOther, false},
+		{"other muxed", otherMuxed, false},
+		{"federation", "me*example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSelfPayment(tt.recipient, testWallet); got != tt.expected {
+				t.Errorf("isSelfPayment(%q) = %v, want %v", tt.recipient, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/price"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// All open offers of an account, following Horizon's paging
+func fetchOffers(accountID string) ([]horizon.Offer, error) {
+	var offers []horizon.Offer
+	cursor := ""
+	for {
+		page, err := client.Offers(horizonclient.OfferRequest{
+			ForAccount: accountID,
+			Cursor:     cursor,
+			Limit:      200,
+		})
+		if err != nil {
+			return nil, err
+		}
+		records := page.Embedded.Records
+		if len(records) == 0 {
+			return offers, nil
+		}
+		offers = append(offers, records...)
+		cursor = records[len(records)-1].PagingToken()
+	}
+}
+
+// Operation deleting an existing offer by setting its amount to zero
+func cancelOfferOp(offer horizon.Offer) *txnbuild.ManageSellOffer {
+	return &txnbuild.ManageSellOffer{
+		Selling: assetFromHorizon(offer.Selling.Type, offer.Selling.Code, offer.Selling.Issuer),
+		Buying:  assetFromHorizon(offer.Buying.Type, offer.Buying.Code, offer.Buying.Issuer),
+		Amount:  "0",
+		Price:   xdr.Price{N: xdr.Int32(offer.PriceR.N), D: xdr.Int32(offer.PriceR.D)},
+		OfferID: offer.ID,
+	}
+}
+
+// Operations cancelling every offer, grouped into transactions within the op limit
+func cancelAllOffersOps(offers []horizon.Offer) [][]txnbuild.Operation {
+	ops := make([]txnbuild.Operation, 0, len(offers))
+	for _, offer := range offers {
+		ops = append(ops, cancelOfferOp(offer))
+	}
+	return chunkOperations(ops, maxOpsPerTx)
+}
+
+func showCancelAllOffersDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	offers, err := fetchOffers(wallet.PublicKey)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading offers: %v", err), window)
+		return
+	}
+	if len(offers) == 0 {
+		dialog.ShowInformation("Cancel All Offers", "There are no open offers.", window)
+		return
+	}
+
+	batches := cancelAllOffersOps(offers)
+	message := fmt.Sprintf("Cancel all %d open offers? This needs %d transaction(s).", len(offers), len(batches))
+	dialog.ShowConfirm("Cancel All Offers", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		submitBatches(batches, nil, func(hashes []string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Cancelled %d offers in %d transaction(s).", len(offers), len(hashes)), window)
+		}, nil)
+	}, window)
+}
+
+// A new offer on a base/counter pair, priced in counter per unit of base.
+// Buy offers buy Amount of base; sell offers sell Amount of base.
+type offerDraft struct {
+	Buy     bool
+	Base    txnbuild.Asset
+	Counter txnbuild.Asset
+	Price   string
+	Amount  string
+}
+
+func offerOp(d offerDraft) (txnbuild.Operation, error) {
+	p, err := price.Parse(strings.TrimSpace(d.Price))
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q: %v", d.Price, err)
+	}
+	if stroops, err := amount.ParseInt64(strings.TrimSpace(d.Amount)); err != nil || stroops <= 0 {
+		return nil, fmt.Errorf("invalid amount %q", d.Amount)
+	}
+	if d.Buy {
+		return &txnbuild.ManageBuyOffer{
+			Selling: d.Counter,
+			Buying:  d.Base,
+			Amount:  strings.TrimSpace(d.Amount),
+			Price:   p,
+		}, nil
+	}
+	return &txnbuild.ManageSellOffer{
+		Selling: d.Base,
+		Buying:  d.Counter,
+		Amount:  strings.TrimSpace(d.Amount),
+		Price:   p,
+	}, nil
+}
+
+// Review and place an offer, starting from the given draft
+func showOfferDialog(draft offerDraft) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	sideSelect := widget.NewSelect([]string{"Buy", "Sell"}, nil)
+	if draft.Buy {
+		sideSelect.SetSelected("Buy")
+	} else {
+		sideSelect.SetSelected("Sell")
+	}
+	priceEntry := widget.NewEntry()
+	priceEntry.SetText(draft.Price)
+	amountEntry := widget.NewEntry()
+	amountEntry.SetText(draft.Amount)
+	amountEntry.SetPlaceHolder("Amount of " + assetCode(draft.Base))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Pair", widget.NewLabel(assetCode(draft.Base)+" / "+assetCode(draft.Counter))),
+		widget.NewFormItem("Side", sideSelect),
+		widget.NewFormItem("Price", priceEntry),
+		widget.NewFormItem("Amount", amountEntry),
+	}
+
+	dialog.ShowForm("Place Offer", "Place", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		draft.Buy = sideSelect.Selected == "Buy"
+		draft.Price = priceEntry.Text
+		draft.Amount = amountEntry.Text
+
+		op, err := offerOp(draft)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		submitWithFeedback([]txnbuild.Operation{op}, nil, func(hash string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Offer placed! Hash: %s", hash), window)
+		})
+	}, window)
+}
+
+// One line describing an open offer, e.g. "Sell 10 XLM for USD at 0.25"
+func offerText(offer horizon.Offer) string {
+	selling := assetCode(assetFromHorizon(offer.Selling.Type, offer.Selling.Code, offer.Selling.Issuer))
+	buying := assetCode(assetFromHorizon(offer.Buying.Type, offer.Buying.Code, offer.Buying.Issuer))
+	return fmt.Sprintf("Sell %s %s for %s at %s", offer.Amount, selling, buying, offer.Price)
+}
+
+// Error naming the first asset the account has no trustline for, if any
+func missingTrustline(balances map[string]string, assets ...txnbuild.Asset) error {
+	for _, asset := range assets {
+		if asset.IsNative() {
+			continue
+		}
+		if _, ok := balances[assetString(asset)]; !ok {
+			return fmt.Errorf("no trustline for %s; add the asset first", assetCode(asset))
+		}
+	}
+	return nil
+}
+
+// Open offers of the wallet, each with a Cancel button, and a way to place new ones
+func showOffersDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	offers, err := fetchOffers(wallet.PublicKey)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading offers: %v", err), window)
+		return
+	}
+
+	var d dialog.Dialog
+	list := container.NewVBox()
+	if len(offers) == 0 {
+		list.Add(widget.NewLabel("There are no open offers."))
+	}
+	for _, offer := range offers {
+		offer := offer
+		cancel := widget.NewButton("Cancel", func() {
+			dialog.ShowConfirm("Cancel Offer", offerText(offer)+"\n\nCancel this offer?", func(ok bool) {
+				if !ok {
+					return
+				}
+				d.Hide()
+				submitWithFeedback([]txnbuild.Operation{cancelOfferOp(offer)}, nil, func(hash string) {
+					dialog.ShowInformation("Success", fmt.Sprintf("Offer cancelled! Hash: %s", hash), window)
+				})
+			}, window)
+		})
+		list.Add(container.NewBorder(nil, nil, nil, cancel, widget.NewLabel(offerText(offer))))
+	}
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(scaled(380), scaled(240)))
+	newOffer := widget.NewButton("New Offer...", func() {
+		d.Hide()
+		showNewOfferDialog()
+	})
+	d = dialog.NewCustom("Offers", "Close", container.NewBorder(nil, newOffer, nil, nil, scroll), window)
+	d.Show()
+}
+
+// Place an offer on any pair. Sell offers sell an exact amount of the selling
+// asset; buy offers buy an exact amount of the buying asset.
+func showNewOfferDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	_, balances, err := updateBalances()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading balances: %v", err), window)
+		return
+	}
+	assets := sortedAssets(balances)
+
+	typeSelect := widget.NewSelect([]string{"Sell", "Buy"}, nil)
+	typeSelect.SetSelected("Sell")
+	sellingEntry := widget.NewSelectEntry(assets)
+	sellingEntry.SetText("XLM")
+	buyingEntry := widget.NewSelectEntry(assets)
+	buyingEntry.SetPlaceHolder("CODE:ISSUER")
+	amountEntry := widget.NewEntry()
+	priceEntry := widget.NewEntry()
+
+	hint := widget.NewLabel("")
+	hint.Wrapping = fyne.TextWrapWord
+	updateHint := func(string) {
+		if typeSelect.Selected == "Buy" {
+			amountEntry.SetPlaceHolder("Amount to buy")
+			priceEntry.SetPlaceHolder("Selling asset paid per unit bought")
+			hint.SetText("Buys exactly this amount of the buying asset, paying at most the price in the selling asset.")
+			return
+		}
+		amountEntry.SetPlaceHolder("Amount to sell")
+		priceEntry.SetPlaceHolder("Buying asset received per unit sold")
+		hint.SetText("Sells exactly this amount of the selling asset, receiving at least the price in the buying asset.")
+	}
+	typeSelect.OnChanged = updateHint
+	updateHint("")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Type", typeSelect),
+		widget.NewFormItem("Selling", sellingEntry),
+		widget.NewFormItem("Buying", buyingEntry),
+		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("Price", priceEntry),
+		widget.NewFormItem("", hint),
+	}
+
+	dialog.ShowForm("New Offer", "Place", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		selling, err := parseAsset(sellingEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("selling asset: %v", err), window)
+			return
+		}
+		buying, err := parseAsset(buyingEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("buying asset: %v", err), window)
+			return
+		}
+		if assetString(selling) == assetString(buying) {
+			dialog.ShowError(fmt.Errorf("selling and buying assets must differ"), window)
+			return
+		}
+		if err := missingTrustline(balances, selling, buying); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		draft := offerDraft{Base: selling, Counter: buying, Price: priceEntry.Text, Amount: amountEntry.Text}
+		if typeSelect.Selected == "Buy" {
+			draft = offerDraft{Buy: true, Base: buying, Counter: selling, Price: priceEntry.Text, Amount: amountEntry.Text}
+		}
+		op, err := offerOp(draft)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		message := fmt.Sprintf("%s %s %s for %s at %s?", typeSelect.Selected, strings.TrimSpace(amountEntry.Text),
+			assetCode(draft.Base), assetCode(draft.Counter), strings.TrimSpace(priceEntry.Text))
+		dialog.ShowConfirm("Confirm Offer", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			submitWithFeedback([]txnbuild.Operation{op}, nil, func(hash string) {
+				dialog.ShowInformation("Success", fmt.Sprintf("Offer placed! Hash: %s", hash), window)
+			})
+		}, window)
+	}, window)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestOfferOp(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+	xlm := txnbuild.NativeAsset{}
+
+	t.Run("buy", func(t *testing.T) {
+		op, err := offerOp(offerDraft{Buy: true, Base: xlm, Counter: usd, Price: "0.25", Amount: " 100 "})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buy, ok := op.(*txnbuild.ManageBuyOffer)
+		if !ok {
+			t.Fatalf("offerOp() = %T, want *txnbuild.ManageBuyOffer", op)
+		}
+		if buy.Buying != xlm || buy.Selling != usd || buy.Amount != "100" || buy.Price != (xdr.Price{N: 1, D: 4}) {
+			t.Errorf("buy offer = %+v", buy)
+		}
+	})
+
+	t.Run("sell", func(t *testing.T) {
+		op, err := offerOp(offerDraft{Base: xlm, Counter: usd, Price: "4", Amount: "2.5"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sell, ok := op.(*txnbuild.ManageSellOffer)
+		if !ok {
+			t.Fatalf("offerOp() = %T, want *txnbuild.ManageSellOffer", op)
+		}
+		if sell.Selling != xlm || sell.Buying != usd || sell.Amount != "2.5" || sell.Price != (xdr.Price{N: 4, D: 1}) || sell.OfferID != 0 {
+			t.Errorf("sell offer = %+v", sell)
+		}
+	})
+
+	for _, d := range []offerDraft{
+		{Price: "", Amount: "1"},
+		{Price: "abc", Amount: "1"},
+		{Price: "1", Amount: ""},
+		{Price: "1", Amount: "0"},
+		{Price: "1", Amount: "-5"},
+		{Price: "1", Amount: "0.00000001"},
+	} {
+		d.Base, d.Counter = xlm, usd
+		if _, err := offerOp(d); err == nil {
+			t.Errorf("offerOp(price %q, amount %q) accepted", d.Price, d.Amount)
+		}
+	}
+}
+
+func TestMissingTrustline(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+	eur := txnbuild.CreditAsset{Code: "EUR", Issuer: testOther}
+	balances := map[string]string{"XLM": "10", "USD:" + testOther: "5"}
+
+	if err := missingTrustline(balances, txnbuild.NativeAsset{}, usd); err != nil {
+		t.Errorf("missingTrustline() with trustlines = %v", err)
+	}
+	if err := missingTrustline(balances, usd, eur); err == nil {
+		t.Error("missingTrustline() missed EUR")
+	}
+}
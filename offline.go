@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Build a transaction from the wallet account without signing it and hand
+// over its envelope for signing on an offline device
+func exportUnsignedTransaction(ops []txnbuild.Operation, memo memoSpec, baseFee int64) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	txMemo, err := buildMemo(memo)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	tx, err := activeSession().Build(ops, txMemo, baseFee, wallet.PublicKey)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error encoding transaction: %v", err), window)
+		return
+	}
+	showEnvelope("Unsigned Transaction", envelope, "Sign this envelope on your offline device, then submit it with Import Signed XDR.")
+}
+
+// Envelope to copy or save. It uses the account's next sequence number, so
+// any other transaction sent first makes it invalid.
+func showEnvelope(title, envelope, instructions string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	envelopeEntry := widget.NewMultiLineEntry()
+	envelopeEntry.SetText(envelope)
+	envelopeEntry.Wrapping = fyne.TextWrapBreak
+	info := widget.NewLabel(instructions + " Sending anything else from this account first will invalidate it.")
+	info.Wrapping = fyne.TextWrapWord
+
+	copyButton := widget.NewButton("Copy", func() {
+		window.Clipboard().SetContent(envelope)
+	})
+	saveButton := widget.NewButton("Save to File", func() {
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write([]byte(envelope + "\n")); err != nil {
+				dialog.ShowError(fmt.Errorf("error writing transaction: %v", err), window)
+				return
+			}
+			dialog.ShowInformation("Success", "Transaction saved!", window)
+		}, window)
+		save.SetFileName("transaction.xdr")
+		save.SetFilter(storage.NewExtensionFileFilter([]string{".xdr", ".txt"}))
+		save.Show()
+	})
+
+	content := container.NewBorder(info, container.NewHBox(copyButton, saveButton), nil, nil, envelopeEntry)
+	d := dialog.NewCustom(title, "Close", content, window)
+	d.Resize(fyne.NewSize(scaled(480), scaled(320)))
+	d.Show()
+}
+
+// Paste a base64 envelope signed elsewhere and review it before submitting
+func showImportSignedXDRDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	xdrEntry := widget.NewMultiLineEntry()
+	xdrEntry.SetPlaceHolder("Signed transaction XDR (base64)")
+	xdrEntry.Wrapping = fyne.TextWrapBreak
+
+	dialog.ShowForm("Import Signed XDR", "Review", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Transaction", xdrEntry),
+	}, func(submit bool) {
+		if !submit {
+			return
+		}
+		tx, err := parseTransactionFile([]byte(xdrEntry.Text))
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		showTransactionReview(tx, "")
+	}, window)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+// A new account funded with XLM, a trustline and an initial asset payment
+type onboardingBundle struct {
+	Recipient       string
+	Asset           txnbuild.Asset
+	Amount          string
+	StartingBalance string
+	Sponsored       bool // the wallet pays the new account's reserves
+}
+
+// Operations for an onboarding bundle, with the wallet as transaction source.
+// The trustline (and the end of any sponsorship) is sourced from the new
+// account, so the transaction must also be signed by it.
+func onboardingOps(b onboardingBundle) ([]txnbuild.Operation, error) {
+	if b.Asset == nil || b.Asset.IsNative() {
+		return nil, fmt.Errorf("onboarding asset must be a credit asset")
+	}
+	if sent, err := amount.ParseInt64(b.Amount); err != nil || sent <= 0 {
+		return nil, fmt.Errorf("invalid amount %q", b.Amount)
+	}
+	starting, err := amount.ParseInt64(b.StartingBalance)
+	if err != nil || starting < 0 {
+		return nil, fmt.Errorf("invalid starting balance %q", b.StartingBalance)
+	}
+	// Base account reserve plus one trustline, unless sponsored
+	if needed := int64(3 * baseReserve); !b.Sponsored && starting < needed {
+		return nil, fmt.Errorf("starting balance must be at least %s XLM to cover the account and trustline reserves", stroopsToXLM(needed))
+	}
+
+	var ops []txnbuild.Operation
+	if b.Sponsored {
+		ops = append(ops, &txnbuild.BeginSponsoringFutureReserves{SponsoredID: b.Recipient})
+	}
+	ops = append(ops,
+		&txnbuild.CreateAccount{Destination: b.Recipient, Amount: b.StartingBalance},
+		&txnbuild.ChangeTrust{Line: b.Asset.MustToChangeTrustAsset(), SourceAccount: b.Recipient},
+	)
+	if b.Sponsored {
+		ops = append(ops, &txnbuild.EndSponsoringFutureReserves{SourceAccount: b.Recipient})
+	}
+	ops = append(ops, &txnbuild.Payment{Destination: b.Recipient, Amount: b.Amount, Asset: b.Asset})
+	return ops, nil
+}
+
+func showOnboardDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	secretEntry := widget.NewPasswordEntry()
+	secretEntry.SetPlaceHolder("New account secret key (S...)")
+	generateButton := widget.NewButton("Generate", func() {
+		kp, err := keypair.Random()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error generating keypair: %v", err), window)
+			return
+		}
+		secretEntry.SetText(kp.Seed())
+	})
+
+	assetEntry := widget.NewEntry()
+	assetEntry.SetPlaceHolder("CODE:ISSUER")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Amount to send")
+	startingEntry := widget.NewEntry()
+	startingEntry.SetText(stroopsToXLM(3 * baseReserve))
+
+	sponsorCheck := widget.NewCheck("Sponsor reserves", func(checked bool) {
+		if checked {
+			startingEntry.SetText("0")
+		} else {
+			startingEntry.SetText(stroopsToXLM(3 * baseReserve))
+		}
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Recipient", container.NewBorder(nil, nil, nil, generateButton, secretEntry)),
+		widget.NewFormItem("Asset", assetEntry),
+		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("Starting XLM", startingEntry),
+		widget.NewFormItem("", sponsorCheck),
+	}
+
+	dialog.ShowForm("Onboard Recipient", "Review", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		recipientKP, err := keypair.ParseFull(strings.TrimSpace(secretEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid recipient secret key: %v", err), window)
+			return
+		}
+		asset, err := parseAsset(assetEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		bundle := onboardingBundle{
+			Recipient:       recipientKP.Address(),
+			Asset:           asset,
+			Amount:          strings.TrimSpace(amountEntry.Text),
+			StartingBalance: strings.TrimSpace(startingEntry.Text),
+			Sponsored:       sponsorCheck.Checked,
+		}
+		ops, err := onboardingOps(bundle)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		var lines []string
+		for _, op := range ops {
+			lines = append(lines, describeOperation(op))
+		}
+		message := fmt.Sprintf("Create %s with:\n\n%s", bundle.Recipient, strings.Join(lines, "\n"))
+		dialog.ShowConfirm("Confirm Onboarding", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			submitWithCosigners(ops, nil, []*keypair.Full{recipientKP}, func(hash string) {
+				showOnboardedAccount(recipientKP, hash)
+			})
+		}, window)
+	}, window)
+}
+
+// Show the new account's keys so they can be handed to the recipient
+func showOnboardedAccount(kp *keypair.Full, hash string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	info := widget.NewLabel(fmt.Sprintf("Account created in transaction %s.\n\nPublic key: %s\n\nGive the secret key to the recipient; it is not stored in this wallet.", hash, kp.Address()))
+	info.Wrapping = fyne.TextWrapBreak
+	copyButton := widget.NewButton("Copy Secret Key", func() {
+		window.Clipboard().SetContent(kp.Seed())
+	})
+	dialog.ShowCustom("Recipient Onboarded", "Close", container.NewVBox(info, copyButton), window)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestOnboardingOps(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testWallet}
+	minimum := stroopsToXLM(3 * baseReserve)
+
+	// Type and source account of each operation, "tx" for the transaction source
+	steps := func(ops []txnbuild.Operation) []string {
+		var out []string
+		for _, op := range ops {
+			source := op.GetSourceAccount()
+			if source == "" {
+				source = "tx"
+			} else if source == testOther {
+				source = "new"
+			}
+			out = append(out, fmt.Sprintf("%T@%s", op, source))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		bundle  onboardingBundle
+		want    []string
+		wantErr string
+	}{
+		{"funded", onboardingBundle{Recipient: testOther, Asset: usd, Amount: "10", StartingBalance: minimum},
+			[]string{"*txnbuild.CreateAccount@tx", "*txnbuild.ChangeTrust@new", "*txnbuild.Payment@tx"}, ""},
+		{"sponsored with nothing", onboardingBundle{Recipient: testOther, Asset: usd, Amount: "10", StartingBalance: "0", Sponsored: true},
+			[]string{"*txnbuild.BeginSponsoringFutureReserves@tx", "*txnbuild.CreateAccount@tx",
+				"*txnbuild.ChangeTrust@new", "*txnbuild.EndSponsoringFutureReserves@new", "*txnbuild.Payment@tx"}, ""},
+		{"below reserves", onboardingBundle{Recipient: testOther, Asset: usd, Amount: "10", StartingBalance: "1"},
+			nil, "starting balance must be at least " + minimum},
+		{"native asset", onboardingBundle{Recipient: testOther, Asset: txnbuild.NativeAsset{}, Amount: "10", StartingBalance: minimum},
+			nil, "must be a credit asset"},
+		{"no asset", onboardingBundle{Recipient: testOther, Amount: "10", StartingBalance: minimum},
+			nil, "must be a credit asset"},
+		{"zero amount", onboardingBundle{Recipient: testOther, Asset: usd, Amount: "0", StartingBalance: minimum},
+			nil, "invalid amount"},
+		{"negative starting balance", onboardingBundle{Recipient: testOther, Asset: usd, Amount: "1", StartingBalance: "-1", Sponsored: true},
+			nil, "invalid starting balance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := onboardingOps(tt.bundle)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := steps(ops); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("steps = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
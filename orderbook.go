@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// One price level of an order book side, with the depth up to and including it
+type depthRow struct {
+	Price      string
+	Amount     string
+	Cumulative string
+}
+
+// Map Horizon price levels, best first, to rows with a running total
+func orderBookRows(levels []horizon.PriceLevel) ([]depthRow, error) {
+	rows := make([]depthRow, 0, len(levels))
+	var total int64
+	for _, level := range levels {
+		stroops, err := amount.ParseInt64(level.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q at price %s: %v", level.Amount, level.Price, err)
+		}
+		total += stroops
+		rows = append(rows, depthRow{
+			Price:      level.Price,
+			Amount:     level.Amount,
+			Cumulative: amount.StringFromInt64(total),
+		})
+	}
+	return rows, nil
+}
+
+// Gap between the best ask and best bid, in the counter asset and as a
+// percentage of the mid price. False when either side is empty.
+func bookSpread(book horizon.OrderBookSummary) (string, string, bool) {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return "", "", false
+	}
+	bid, ask := priceRat(book.Bids[0].PriceR), priceRat(book.Asks[0].PriceR)
+	if bid == nil || ask == nil {
+		return "", "", false
+	}
+	spread := new(big.Rat).Sub(ask, bid)
+	mid := new(big.Rat).Add(ask, bid)
+	mid.Quo(mid, big.NewRat(2, 1))
+	percent := new(big.Rat).Quo(spread, mid)
+	percent.Mul(percent, big.NewRat(100, 1))
+	return spread.FloatString(7), percent.FloatString(2), true
+}
+
+// Tappable list of depth rows; onTap gets the tapped row
+func depthList(rows []depthRow, onTap func(row depthRow)) fyne.CanvasObject {
+	list := container.NewVBox()
+	for _, row := range rows {
+		row := row
+		label := fmt.Sprintf("%s  |  %s  |  %s", row.Price, row.Amount, row.Cumulative)
+		button := widget.NewButton(label, func() { onTap(row) })
+		button.Alignment = widget.ButtonAlignLeading
+		list.Add(button)
+	}
+	if len(rows) == 0 {
+		list.Add(widget.NewLabel("No offers"))
+	}
+	return list
+}
+
+func showOrderBookWindow() {
+	window := fyne.CurrentApp().NewWindow("Order Book")
+
+	baseEntry := widget.NewEntry()
+	baseEntry.SetText("XLM")
+	counterEntry := widget.NewEntry()
+	counterEntry.SetPlaceHolder("CODE:ISSUER")
+	bidsBox := container.NewVBox()
+	asksBox := container.NewVBox()
+	spreadLabel := widget.NewLabel("")
+
+	load := func() {
+		base, err := parseAsset(baseEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("selling: %v", err), window)
+			return
+		}
+		counter, err := parseAsset(counterEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("buying: %v", err), window)
+			return
+		}
+
+		baseType, baseCode, baseIssuer := horizonAssetParams(base)
+		counterType, counterCode, counterIssuer := horizonAssetParams(counter)
+		book, err := client.OrderBook(horizonclient.OrderBookRequest{
+			SellingAssetType:   baseType,
+			SellingAssetCode:   baseCode,
+			SellingAssetIssuer: baseIssuer,
+			BuyingAssetType:    counterType,
+			BuyingAssetCode:    counterCode,
+			BuyingAssetIssuer:  counterIssuer,
+			Limit:              20,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading order book: %v", err), window)
+			return
+		}
+		bids, err := orderBookRows(book.Bids)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		asks, err := orderBookRows(book.Asks)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		// Tapping a bid sells into it, tapping an ask buys from it
+		prefill := func(buy bool) func(row depthRow) {
+			return func(row depthRow) {
+				showOfferDialog(offerDraft{Buy: buy, Base: base, Counter: counter, Price: row.Price})
+			}
+		}
+
+		baseCodeText, counterCodeText := assetCode(base), assetCode(counter)
+		bidsBox.Objects = []fyne.CanvasObject{
+			widget.NewLabelWithStyle(fmt.Sprintf("Bids: price (%s) | amount (%s) | total", counterCodeText, counterCodeText), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			depthList(bids, prefill(false)),
+		}
+		asksBox.Objects = []fyne.CanvasObject{
+			widget.NewLabelWithStyle(fmt.Sprintf("Asks: price (%s) | amount (%s) | total", counterCodeText, baseCodeText), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			depthList(asks, prefill(true)),
+		}
+		bidsBox.Refresh()
+		asksBox.Refresh()
+		if spread, percent, ok := bookSpread(book); ok {
+			spreadLabel.SetText(fmt.Sprintf("Spread: %s %s (%s%%)", spread, counterCodeText, percent))
+		} else {
+			spreadLabel.SetText("Spread: no offers on one side")
+		}
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem("Selling", baseEntry),
+		widget.NewFormItem("Buying", counterEntry),
+	)
+	top := container.NewVBox(form, widget.NewButton("Load", load), spreadLabel)
+	sides := container.NewGridWithColumns(2, container.NewVScroll(bidsBox), container.NewVScroll(asksBox))
+
+	window.SetContent(container.NewBorder(top, nil, nil, nil, sides))
+	window.Resize(fyne.NewSize(scaled(640), scaled(480)))
+	window.Show()
+}
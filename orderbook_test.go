@@ -0,0 +1,63 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+)
+
+func TestOrderBookRows(t *testing.T) {
+	levels := []horizon.PriceLevel{
+		{Price: "0.1000000", Amount: "10.0000000"},
+		{Price: "0.0990000", Amount: "0.0000001"},
+		{Price: "0.0950000", Amount: "2.5"},
+	}
+	got, err := orderBookRows(levels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []depthRow{
+		{Price: "0.1000000", Amount: "10.0000000", Cumulative: "10.0000000"},
+		{Price: "0.0990000", Amount: "0.0000001", Cumulative: "10.0000001"},
+		{Price: "0.0950000", Amount: "2.5", Cumulative: "12.5000001"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %+v\nwant %+v", got, want)
+	}
+
+	if rows, err := orderBookRows(nil); err != nil || len(rows) != 0 {
+		t.Errorf("empty side = %+v, %v", rows, err)
+	}
+	if _, err := orderBookRows([]horizon.PriceLevel{{Price: "1", Amount: "lots"}}); err == nil || !strings.Contains(err.Error(), `invalid amount "lots" at price 1`) {
+		t.Errorf("error = %v, want an invalid amount error", err)
+	}
+}
+
+func TestBookSpread(t *testing.T) {
+	level := func(n, d int32) []horizon.PriceLevel {
+		return []horizon.PriceLevel{{PriceR: horizon.Price{N: n, D: d}}}
+	}
+	tests := []struct {
+		name        string
+		book        horizon.OrderBookSummary
+		wantSpread  string
+		wantPercent string
+		wantOK      bool
+	}{
+		{"normal", horizon.OrderBookSummary{Bids: level(99, 1000), Asks: level(101, 1000)}, "0.0020000", "2.00", true},
+		{"locked", horizon.OrderBookSummary{Bids: level(1, 2), Asks: level(1, 2)}, "0.0000000", "0.00", true},
+		{"no bids", horizon.OrderBookSummary{Asks: level(1, 1)}, "", "", false},
+		{"no asks", horizon.OrderBookSummary{Bids: level(1, 1)}, "", "", false},
+		{"zero price", horizon.OrderBookSummary{Bids: level(0, 1), Asks: level(1, 1)}, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spread, percent, ok := bookSpread(tt.book)
+			if spread != tt.wantSpread || percent != tt.wantPercent || ok != tt.wantOK {
+				t.Errorf("bookSpread = %q, %q, %v, want %q, %q, %v", spread, percent, ok, tt.wantSpread, tt.wantPercent, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+var slippagePresets = []string{"0.5%", "1%", "2%", "Custom"}
+
+// Default slippage preset for a quote; every intermediate hop adds price risk
+func defaultSlippage(hops int) string {
+	switch {
+	case hops == 0:
+		return "0.5%"
+	case hops == 1:
+		return "1%"
+	default:
+		return "2%"
+	}
+}
+
+// Parse a slippage percentage like "0.5" or "1%" into basis points
+func parseSlippage(s string) (int64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid slippage %q", s)
+	}
+	bps := int64(percent*100 + 0.5)
+	if bps <= 0 || bps > 5000 {
+		return 0, fmt.Errorf("slippage must be between 0.01%% and 50%%")
+	}
+	return bps, nil
+}
+
+// Bound to put on a path payment given the quoted amount and slippage in basis points.
+// Strict send lowers the minimum received (rounding down); strict receive raises the
+// maximum sent (rounding up).
+func slippageBound(estimate string, bps int64, strictSend bool) (string, error) {
+	stroops, err := amount.ParseInt64(estimate)
+	if err != nil {
+		return "", fmt.Errorf("invalid estimate %q: %v", estimate, err)
+	}
+
+	bound := new(big.Int).SetInt64(stroops)
+	if strictSend {
+		bound.Mul(bound, big.NewInt(10000-bps))
+		bound.Quo(bound, big.NewInt(10000))
+	} else {
+		bound.Mul(bound, big.NewInt(10000+bps))
+		bound.Add(bound, big.NewInt(9999))
+		bound.Quo(bound, big.NewInt(10000))
+	}
+	if !bound.IsInt64() || bound.Int64() <= 0 {
+		return "", fmt.Errorf("amount out of range after slippage")
+	}
+	return amount.StringFromInt64(bound.Int64()), nil
+}
+
+// Best strict-send path from the source asset/amount to the destination asset
+func bestStrictSendPath(sendAsset txnbuild.Asset, sendAmount string, destAsset txnbuild.Asset) (horizon.Path, error) {
+	assetType, code, issuer := horizonAssetParams(sendAsset)
+	paths, err := client.StrictSendPaths(horizonclient.StrictSendPathsRequest{
+		SourceAssetType:   assetType,
+		SourceAssetCode:   code,
+		SourceAssetIssuer: issuer,
+		SourceAmount:      sendAmount,
+		DestinationAssets: horizonAssetList(destAsset),
+	})
+	if err != nil {
+		return horizon.Path{}, fmt.Errorf("error finding payment path: %v", err)
+	}
+
+	var best horizon.Path
+	var bestAmount int64
+	for _, path := range paths.Embedded.Records {
+		received, err := amount.ParseInt64(path.DestinationAmount)
+		if err != nil {
+			continue
+		}
+		if received > bestAmount {
+			best, bestAmount = path, received
+		}
+	}
+	if bestAmount == 0 {
+		return horizon.Path{}, fmt.Errorf("no payment path found")
+	}
+	return best, nil
+}
+
+func pathAssets(path horizon.Path) []txnbuild.Asset {
+	assets := make([]txnbuild.Asset, 0, len(path.Path))
+	for _, a := range path.Path {
+		assets = append(assets, assetFromHorizon(a.Type, a.Code, a.Issuer))
+	}
+	return assets
+}
+
+func showPathPaymentDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	recipientEntry := widget.NewEntry()
+	recipientEntry.SetPlaceHolder("Recipient address")
+	sendAssetEntry := widget.NewEntry()
+	sendAssetEntry.SetText("XLM")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Amount to send")
+	destAssetEntry := widget.NewEntry()
+	destAssetEntry.SetPlaceHolder("CODE:ISSUER or XLM")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Recipient", recipientEntry),
+		widget.NewFormItem("Send Asset", sendAssetEntry),
+		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("Receive Asset", destAssetEntry),
+		widget.NewFormItem("", widget.NewButton("Calculator", func() {
+			showCalculatorDialog(sendAssetEntry.Text, destAssetEntry.Text, amountEntry.Text)
+		})),
+	}
+
+	dialog.ShowForm("Path Payment", "Get Quote", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		// The same checks as a plain send, before anything is quoted
+		recipient := strings.TrimSpace(recipientEntry.Text)
+		if err := validateRecipient(recipient); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		memo := memoSpec{Type: "none"}
+		if isFederationAddress(recipient) {
+			resolved, err := resolveFederationAddress(recipient)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			recipient, memo = resolved.AccountID, resolved.Memo
+		}
+		sendAmount := strings.TrimSpace(amountEntry.Text)
+		if err := validateAmount(sendAmount); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if isSelfPayment(recipient, wallet.PublicKey) {
+			dialog.ShowError(errSelfPayment, window)
+			return
+		}
+		if _, err := buildMemo(memo); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		sendAsset, err := parseAsset(sendAssetEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("send asset: %v", err), window)
+			return
+		}
+		destAsset, err := parseAsset(destAssetEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("receive asset: %v", err), window)
+			return
+		}
+
+		destination := recipient
+		if accountID, _, err := decodeMuxedAddress(recipient); err == nil {
+			destination = accountID
+		}
+		if err := checkDestinationTrustline(destination, destAsset); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		payment := &txnbuild.PathPaymentStrictSend{
+			SendAsset:   sendAsset,
+			SendAmount:  sendAmount,
+			Destination: recipient,
+			DestAsset:   destAsset,
+		}
+
+		quote, err := bestStrictSendPath(sendAsset, payment.SendAmount, destAsset)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		showPathPaymentQuote(payment, memo, quote)
+	}, window)
+}
+
+// Let the user pick a slippage tolerance for a quoted path payment and submit it
+func showPathPaymentQuote(payment *txnbuild.PathPaymentStrictSend, memo memoSpec, quote horizon.Path) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+	destCode := assetCode(payment.DestAsset)
+
+	minLabel := widget.NewLabel("")
+	customEntry := widget.NewEntry()
+	customEntry.SetPlaceHolder("Custom slippage %")
+	customEntry.Hide()
+
+	slippageSelect := widget.NewSelect(slippagePresets, nil)
+	currentSlippage := func() string {
+		if slippageSelect.Selected == "Custom" {
+			return customEntry.Text
+		}
+		return slippageSelect.Selected
+	}
+	updateBound := func() {
+		bps, err := parseSlippage(currentSlippage())
+		if err != nil {
+			minLabel.SetText(err.Error())
+			return
+		}
+		bound, err := slippageBound(quote.DestinationAmount, bps, true)
+		if err != nil {
+			minLabel.SetText(err.Error())
+			return
+		}
+		minLabel.SetText(fmt.Sprintf("%s %s", bound, destCode))
+	}
+	slippageSelect.OnChanged = func(choice string) {
+		if choice == "Custom" {
+			customEntry.Show()
+		} else {
+			customEntry.Hide()
+		}
+		updateBound()
+	}
+	customEntry.OnChanged = func(string) { updateBound() }
+	slippageSelect.SetSelected(defaultSlippage(len(quote.Path)))
+
+	form := widget.NewForm(
+		widget.NewFormItem("Send", widget.NewLabel(fmt.Sprintf("%s %s", payment.SendAmount, assetCode(payment.SendAsset)))),
+		widget.NewFormItem("Estimated", widget.NewLabel(fmt.Sprintf("%s %s", quote.DestinationAmount, destCode))),
+		widget.NewFormItem("Slippage", container.NewVBox(slippageSelect, customEntry)),
+		widget.NewFormItem("Minimum", minLabel),
+	)
+
+	dialog.ShowCustomConfirm("Confirm Path Payment", "Send", "Cancel", form, func(confirm bool) {
+		if !confirm {
+			return
+		}
+
+		bps, err := parseSlippage(currentSlippage())
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		destMin, err := slippageBound(quote.DestinationAmount, bps, true)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		// Re-quote right before submitting and refuse if the market already moved past the bound
+		fresh, err := bestStrictSendPath(payment.SendAsset, payment.SendAmount, payment.DestAsset)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		freshAmount, _ := amount.ParseInt64(fresh.DestinationAmount)
+		minAmount, _ := amount.ParseInt64(destMin)
+		if freshAmount < minAmount {
+			dialog.ShowError(fmt.Errorf("price moved beyond your slippage tolerance: now %s %s, minimum %s %s",
+				fresh.DestinationAmount, destCode, destMin, destCode), window)
+			return
+		}
+
+		txMemo, err := buildMemo(memo)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		payment.DestMin = destMin
+		payment.Path = pathAssets(fresh)
+		params := sendParams{
+			Recipient: payment.Destination,
+			Amount:    payment.SendAmount,
+			Asset:     assetString(payment.SendAsset),
+			Memo:      memo.Value,
+			MemoType:  memo.Type,
+			BaseFee:   networkBaseFee(),
+		}
+		confirmSend(params, func() {
+			submitWithFee([]txnbuild.Operation{payment}, txMemo, params.BaseFee, nil, func(hash string) {
+				dialog.ShowInformation("Success", fmt.Sprintf("Transaction successful! Hash: %s", hash), window)
+			})
+		})
+	}, window)
+}
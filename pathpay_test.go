@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestSlippageBound(t *testing.T) {
+	tests := []struct {
+		name       string
+		estimate   string
+		slippage   string
+		strictSend bool
+		expected   string
+	}{
+		{"strict send rounds down", "100", "1%", true, "99.0000000"},
+		{"strict send half percent", "0.0000010", "0.5", true, "0.0000009"},
+		{"strict receive rounds up", "0.0000010", "0.5%", false, "0.0000011"},
+		{"strict receive", "100", "2%", false, "102.0000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bps, err := parseSlippage(tt.slippage)
+			if err != nil {
+				t.Fatalf("parseSlippage(%q): %v", tt.slippage, err)
+			}
+			got, err := slippageBound(tt.estimate, bps, tt.strictSend)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("slippageBound() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSlippageRejects(t *testing.T) {
+	for _, s := range []string{"", "abc", "0", "-1%", "51%"} {
+		if _, err := parseSlippage(s); err == nil {
+			t.Errorf("parseSlippage(%q) accepted", s)
+		}
+	}
+}
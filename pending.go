@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// Total of claimable balances accountID can claim, per asset ("XLM" or CODE:ISSUER)
+func pendingTotals(balances []horizon.ClaimableBalance, accountID string) (map[string]string, error) {
+	sums := make(map[string]int64)
+	for _, balance := range balances {
+		if !isClaimant(balance, accountID) {
+			continue
+		}
+		asset, err := parseAsset(balance.Asset)
+		if err != nil {
+			return nil, fmt.Errorf("balance %s: %v", balance.BalanceID, err)
+		}
+		stroops, err := amount.ParseInt64(balance.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("balance %s: invalid amount %q", balance.BalanceID, balance.Amount)
+		}
+		sums[assetString(asset)] += stroops
+	}
+
+	totals := make(map[string]string, len(sums))
+	for asset, stroops := range sums {
+		totals[asset] = amount.StringFromInt64(stroops)
+	}
+	return totals, nil
+}
+
+func pendingText(totals map[string]string) string {
+	assets := make([]string, 0, len(totals))
+	for asset := range totals {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+
+	parts := make([]string, len(assets))
+	for i, asset := range assets {
+		parts[i] = fmt.Sprintf("%s %s", totals[asset], assetLabel(asset))
+	}
+	return "Pending: " + strings.Join(parts, ", ")
+}
+
+// Show claimable balances waiting for the wallet, hiding the line when there are none
+func refreshPending(label *widget.Label) {
+	balances, err := fetchClaimableBalances(wallet.PublicKey)
+	if err != nil {
+		label.Hide()
+		return
+	}
+	totals, err := pendingTotals(balances, wallet.PublicKey)
+	if err != nil || len(totals) == 0 {
+		label.Hide()
+		return
+	}
+	label.SetText(pendingText(totals))
+	label.Show()
+}
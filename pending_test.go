@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+)
+
+func TestPendingTotals(t *testing.T) {
+	usd := "USD:" + testOther
+	balance := func(id, asset, amount string, claimants ...string) horizon.ClaimableBalance {
+		b := horizon.ClaimableBalance{BalanceID: id, Asset: asset, Amount: amount}
+		for _, c := range claimants {
+			b.Claimants = append(b.Claimants, horizon.Claimant{Destination: c})
+		}
+		return b
+	}
+	tests := []struct {
+		name     string
+		balances []horizon.ClaimableBalance
+		want     map[string]string
+		wantText string
+		wantErr  string
+	}{
+		{"none", nil, map[string]string{}, "", ""},
+		{"summed per asset", []horizon.ClaimableBalance{
+			balance("a", "native", "1.5000000", testWallet),
+			balance("b", "native", "0.0000001", testOther, testWallet),
+			balance("c", usd, "10.0000000", testWallet),
+		}, map[string]string{"XLM": "1.5000001", usd: "10.0000000"}, "Pending: 10.0000000 USD, 1.5000001 XLM", ""},
+		{"others' balances skipped", []horizon.ClaimableBalance{
+			balance("a", "native", "3", testOther),
+			balance("b", "GARBAGE", "3", testOther),
+		}, map[string]string{}, "", ""},
+		{"bad asset", []horizon.ClaimableBalance{balance("b", "GARBAGE", "3", testWallet)}, nil, "", "balance b:"},
+		{"bad amount", []horizon.ClaimableBalance{balance("c", "native", "x", testWallet)}, nil, "", `balance c: invalid amount "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pendingTotals(tt.balances, testWallet)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("totals = %v, want %v", got, tt.want)
+			}
+			if tt.wantText != "" {
+				if text := pendingText(got); text != tt.wantText {
+					t.Errorf("text = %q, want %q", text, tt.wantText)
+				}
+			}
+		})
+	}
+}
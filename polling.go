@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	minPollInterval     = 10 * time.Second
+
+	// Spread refreshes by ±10% so many wallets don't hit Horizon in lockstep
+	pollJitter = 0.1
+)
+
+// base shifted by up to ±jitter of itself; r is a random number in [0, 1)
+func jitteredInterval(base time.Duration, jitter, r float64) time.Duration {
+	if r < 0 {
+		r = 0
+	}
+	if r > 1 {
+		r = 1
+	}
+	offset := (2*r - 1) * jitter * float64(base)
+	return base + time.Duration(offset)
+}
+
+// Configured refresh interval, falling back to the default
+func pollInterval() time.Duration {
+	if settings.PollSeconds <= 0 {
+		return defaultPollInterval
+	}
+	interval := time.Duration(settings.PollSeconds) * time.Second
+	if interval < minPollInterval {
+		return minPollInterval
+	}
+	return interval
+}
+
+func nextPollDelay() time.Duration {
+	return jitteredInterval(pollInterval(), pollJitter, rand.Float64())
+}
+
+var (
+	pollMu        sync.Mutex
+	cancelPolling context.CancelFunc
+)
+
+// Refresh fee stats now, then the balance and fee stats every poll interval
+// until ctx is cancelled. The payment stream keeps the balance current between
+// polls; this catches anything it missed while reconnecting.
+func pollNetwork(ctx context.Context, balanceLabel, feeLabel *widget.Label) {
+	showFeeStats(feeLabel)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(nextPollDelay()):
+		}
+		refreshBalance(balanceLabel)
+		showFeeStats(feeLabel)
+	}
+}
+
+// Start the background poller, replacing any already running, so rebuilding
+// the window or switching network never leaves a second one behind
+func startPolling(balanceLabel, feeLabel *widget.Label) {
+	pollMu.Lock()
+	defer pollMu.Unlock()
+	if cancelPolling != nil {
+		cancelPolling()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelPolling = cancel
+	go pollNetwork(ctx, balanceLabel, feeLabel)
+}
+
+func stopPolling() {
+	pollMu.Lock()
+	defer pollMu.Unlock()
+	if cancelPolling != nil {
+		cancelPolling()
+		cancelPolling = nil
+	}
+}
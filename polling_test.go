@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitteredInterval(t *testing.T) {
+	base := time.Minute
+	tests := []struct {
+		name     string
+		r        float64
+		expected time.Duration
+	}{
+		{"lowest", 0, 54 * time.Second},
+		{"middle", 0.5, time.Minute},
+		{"highest", 1, 66 * time.Second},
+		{"clamped below", -3, 54 * time.Second},
+		{"clamped above", 7, 66 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jitteredInterval(base, pollJitter, tt.r); got != tt.expected {
+				t.Errorf("jitteredInterval() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNextPollDelayStaysInBounds(t *testing.T) {
+	saved := settings
+	defer func() { settings = saved }()
+
+	for _, seconds := range []int{0, 5, 30, 300} {
+		settings.PollSeconds = seconds
+		base := pollInterval()
+		low := base - time.Duration(pollJitter*float64(base))
+		high := base + time.Duration(pollJitter*float64(base))
+		for i := 0; i < 100; i++ {
+			if delay := nextPollDelay(); delay < low || delay > high {
+				t.Fatalf("PollSeconds %d: delay %v outside [%v, %v]", seconds, delay, low, high)
+			}
+		}
+		if base < minPollInterval {
+			t.Errorf("PollSeconds %d: interval %v below the minimum", seconds, base)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Build a transaction that will be valid at exactly the given sequence number.
+// It has no time bounds since it is meant to be submitted at some later point.
+func buildPreAuthTransaction(accountID string, sequence int64, ops []txnbuild.Operation) (*txnbuild.Transaction, error) {
+	if sequence <= 0 {
+		return nil, fmt.Errorf("invalid sequence number %d", sequence)
+	}
+
+	source := txnbuild.NewSimpleAccount(accountID, sequence-1)
+	return txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &source,
+			IncrementSequenceNum: true,
+			BaseFee:              txnbuild.MinBaseFee,
+			Preconditions: txnbuild.Preconditions{
+				TimeBounds: txnbuild.NewInfiniteTimeout(),
+			},
+			Operations: ops,
+		},
+	)
+}
+
+// Pre-authorized payment of amount XLM to recipient at sequence, checked
+// before it is built since it can't be corrected once its hash is a signer
+func preAuthPaymentTransaction(accountID string, sequence int64, recipient, amount string) (*txnbuild.Transaction, error) {
+	if err := validateRecipient(recipient); err != nil {
+		return nil, err
+	}
+	if isFederationAddress(recipient) {
+		return nil, fmt.Errorf("a pre-authorized payment needs a G... or M... address")
+	}
+	if err := validateAmount(amount); err != nil {
+		return nil, err
+	}
+	payment, err := core.PaymentOp(strings.TrimSpace(recipient), strings.TrimSpace(amount), txnbuild.NativeAsset{})
+	if err != nil {
+		return nil, err
+	}
+	return buildPreAuthTransaction(accountID, sequence, []txnbuild.Operation{payment})
+}
+
+// Derive the T... signer key for a pre-authorized transaction
+func preAuthSignerKey(tx *txnbuild.Transaction, passphrase string) (string, error) {
+	hash, err := tx.Hash(passphrase)
+	if err != nil {
+		return "", err
+	}
+	return strkey.Encode(strkey.VersionByteHashTx, hash[:])
+}
+
+// SetOptions operation adding a pre-authorized transaction as a signer
+func preAuthSignerOp(signerKey string, weight txnbuild.Threshold) *txnbuild.SetOptions {
+	return &txnbuild.SetOptions{
+		Signer: &txnbuild.Signer{
+			Address: signerKey,
+			Weight:  weight,
+		},
+	}
+}
+
+func showPreAuthDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: wallet.PublicKey})
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+		return
+	}
+
+	// The SetOptions transaction adding the signer consumes the next sequence
+	// number, so the earliest usable one is two ahead of the current
+	sequence, err := account.GetSequenceNumber()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error reading sequence number: %v", err), window)
+		return
+	}
+
+	sequenceEntry := widget.NewEntry()
+	sequenceEntry.SetText(strconv.FormatInt(sequence+2, 10))
+	recipientEntry := widget.NewEntry()
+	recipientEntry.SetPlaceHolder("Recipient address")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Amount (XLM)")
+	weightEntry := widget.NewEntry()
+	weightEntry.SetText("1")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Sequence", sequenceEntry),
+		widget.NewFormItem("Recipient", recipientEntry),
+		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("Signer Weight", weightEntry),
+	}
+
+	dialog.ShowForm("Pre-Authorized Transaction", "Create", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		seq, err := strconv.ParseInt(strings.TrimSpace(sequenceEntry.Text), 10, 64)
+		if err != nil || seq <= sequence+1 {
+			dialog.ShowError(fmt.Errorf("sequence must be greater than %d", sequence+1), window)
+			return
+		}
+		weight, err := strconv.ParseUint(strings.TrimSpace(weightEntry.Text), 10, 8)
+		if err != nil || weight == 0 {
+			dialog.ShowError(fmt.Errorf("signer weight must be between 1 and 255"), window)
+			return
+		}
+
+		tx, err := preAuthPaymentTransaction(wallet.PublicKey, seq, recipientEntry.Text, amountEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error building transaction: %v", err), window)
+			return
+		}
+
+		signerKey, err := preAuthSignerKey(tx, currentPassphrase())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error hashing transaction: %v", err), window)
+			return
+		}
+
+		envelope, err := tx.Base64()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error encoding transaction: %v", err), window)
+			return
+		}
+
+		submitWithFeedback([]txnbuild.Operation{
+			preAuthSignerOp(signerKey, txnbuild.Threshold(weight)),
+		}, nil, func(string) {
+			savePreAuthTransaction(envelope, window)
+		})
+	}, window)
+}
+
+// Store the pre-authorized envelope so it can be submitted later. The signer
+// is already on the account, so when the envelope isn't saved it stays on
+// screen to be copied instead of being lost.
+func savePreAuthTransaction(envelope string, window fyne.Window) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			showPreAuthEnvelope(envelope, err, window)
+			return
+		}
+		if writer == nil {
+			showPreAuthEnvelope(envelope, nil, window)
+			return
+		}
+		defer writer.Close()
+
+		if _, err := writer.Write([]byte(envelope)); err != nil {
+			showPreAuthEnvelope(envelope, fmt.Errorf("error writing transaction: %v", err), window)
+			return
+		}
+		dialog.ShowInformation("Success", "Pre-authorized transaction saved. Submit it once the account reaches its sequence number.", window)
+	}, window)
+	save.SetFileName("preauth_tx.xdr")
+	save.SetFilter(storage.NewExtensionFileFilter([]string{".xdr", ".txt"}))
+	save.Show()
+}
+
+// Show an unsaved pre-authorized envelope with ways to copy or save it, along
+// with why saving failed, if it did
+func showPreAuthEnvelope(envelope string, saveErr error, window fyne.Window) {
+	message := "The pre-authorized transaction was not saved. Copy it now; it can't be built again once the signer is in use."
+	if saveErr != nil {
+		message = fmt.Sprintf("Saving failed (%v). Copy the pre-authorized transaction now; it can't be built again once the signer is in use.", saveErr)
+	}
+	info := widget.NewLabel(message)
+	info.Wrapping = fyne.TextWrapWord
+
+	envelopeEntry := widget.NewMultiLineEntry()
+	envelopeEntry.SetText(envelope)
+	envelopeEntry.Wrapping = fyne.TextWrapBreak
+	envelopeEntry.SetMinRowsVisible(4)
+
+	var d dialog.Dialog
+	buttons := container.NewGridWithColumns(2,
+		widget.NewButton("Copy", func() {
+			window.Clipboard().SetContent(envelope)
+		}),
+		widget.NewButton("Save...", func() {
+			d.Hide()
+			savePreAuthTransaction(envelope, window)
+		}),
+	)
+	d = dialog.NewCustom("Pre-Authorized Transaction", "Close", container.NewVBox(info, envelopeEntry, buttons), window)
+	d.Resize(fyne.NewSize(scaled(480), scaled(320)))
+	d.Show()
+}
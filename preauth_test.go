@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestPreAuthPaymentTransaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		sequence  int64
+		recipient string
+		amount    string
+		wantErr   bool
+	}{
+		{"valid", 101, testOther, "10", false},
+		{"recipient with spaces", 7, " " + testOther + " ", " 1.5 ", false},
+		{"zero sequence", 0, testOther, "10", true},
+		{"negative sequence", -5, testOther, "10", true},
+		{"bad recipient", 101, "GBAD", "10", true},
+		{"federation recipient", 101, "bob*example.com", "10", true},
+		{"no amount", 101, testOther, "", true},
+		{"bad amount", 101, testOther, "-1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := preAuthPaymentTransaction(testWallet, tt.sequence, tt.recipient, tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("preAuthPaymentTransaction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tx.SequenceNumber() != tt.sequence {
+				t.Errorf("sequence = %d, want %d", tx.SequenceNumber(), tt.sequence)
+			}
+			if tb := tx.Timebounds(); tb.MaxTime != 0 {
+				t.Errorf("max time = %d, want no expiry", tb.MaxTime)
+			}
+			if payment, ok := tx.Operations()[0].(*txnbuild.Payment); !ok || payment.Destination != testOther {
+				t.Errorf("operation = %#v, want a payment to %s", tx.Operations()[0], testOther)
+			}
+
+			for _, passphrase := range []string{network.TestNetworkPassphrase, network.PublicNetworkPassphrase} {
+				signerKey, err := preAuthSignerKey(tx, passphrase)
+				if err != nil {
+					t.Fatal(err)
+				}
+				decoded, err := strkey.Decode(strkey.VersionByteHashTx, signerKey)
+				if err != nil {
+					t.Fatalf("signer key %q: %v", signerKey, err)
+				}
+				hash, _ := tx.Hash(passphrase)
+				if !bytes.Equal(decoded, hash[:]) {
+					t.Errorf("signer key %s doesn't match transaction hash %x", signerKey, hash)
+				}
+			}
+		})
+	}
+}
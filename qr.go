@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// QR code image for content, sized to follow the font scale setting
+func qrImage(content string) (*canvas.Image, error) {
+	level := qrcode.Medium
+	if len(content) > 1000 {
+		// Transaction envelopes are long; trade error correction for capacity
+		level = qrcode.Low
+	}
+
+	png, err := qrcode.Encode(content, level, 512)
+	if err != nil {
+		return nil, err
+	}
+
+	img := canvas.NewImageFromResource(fyne.NewStaticResource("qr.png", png))
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(fyne.NewSize(scaled(240), scaled(240)))
+	return img, nil
+}
+
+// Receive tab: the wallet address with copy and explorer buttons, and a QR
+// code of the address or of a SEP-7 payment request for it. Built from the
+// wallet each time so it follows account switches.
+func receivePanel() fyne.CanvasObject {
+	address := wallet.PublicKey
+
+	addressLabel := widget.NewLabel(address)
+	addressLabel.Wrapping = fyne.TextWrapBreak
+
+	copyButton := widget.NewButton("Copy Address", func() {
+		window, err := copyToClipboard(address)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		dialog.ShowInformation("Success", "Address copied to clipboard!", window)
+	})
+	explorerButton := widget.NewButton("Explorer", func() {
+		openInExplorer("account", address)
+	})
+	buttons := container.NewHBox(copyButton, explorerButton)
+
+	plain, err := qrImage(address)
+	if err != nil {
+		return container.NewVBox(addressLabel, buttons, widget.NewLabel(fmt.Sprintf("error generating QR code: %v", err)))
+	}
+	request, err := qrImage(buildSEP7PayURI(address, currentPassphrase()))
+	if err != nil {
+		return container.NewVBox(addressLabel, buttons, plain)
+	}
+	request.Hide()
+
+	sep7Check := widget.NewCheck("Payment request (SEP-7)", func(on bool) {
+		if on {
+			plain.Hide()
+			request.Show()
+		} else {
+			request.Hide()
+			plain.Show()
+		}
+	})
+
+	return container.NewVScroll(container.NewVBox(container.NewStack(plain, request), sep7Check, addressLabel, buttons))
+}
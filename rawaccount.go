@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// Pretty-print an account record as Horizon returned it. The record only
+// holds public data; the wallet's secret key never goes through here.
+func accountJSON(account horizon.Account) (string, error) {
+	data, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func showRawAccountDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: wallet.PublicKey})
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+		return
+	}
+	raw, err := accountJSON(account)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error formatting account: %v", err), window)
+		return
+	}
+
+	text := widget.NewMultiLineEntry()
+	text.SetText(raw)
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+	text.Wrapping = fyne.TextWrapOff
+
+	copyButton := widget.NewButton("Copy", func() {
+		window.Clipboard().SetContent(raw)
+	})
+
+	scroll := container.NewScroll(text)
+	scroll.SetMinSize(fyne.NewSize(scaled(320), scaled(400)))
+	dialog.ShowCustom("Raw Account", "Close", container.NewBorder(nil, copyButton, nil, nil, scroll), window)
+}
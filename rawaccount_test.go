@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+)
+
+func TestAccountJSON(t *testing.T) {
+	account := horizon.Account{
+		ID:            testWallet,
+		AccountID:     testWallet,
+		Sequence:      9007199254740993, // beyond float64 precision
+		SubentryCount: 1,
+		HomeDomain:    "example.com",
+		Balances: []horizon.Balance{
+			{Balance: "12.5000000", Asset: base.Asset{Type: "native"}},
+			{Balance: "3.0000000", Limit: "1000.0000000", Asset: base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: testOther}},
+		},
+		Signers: []horizon.Signer{{Key: testWallet, Weight: 1, Type: "ed25519_public_key"}},
+		Data:    map[string]string{"config": "dmFsdWU="},
+	}
+
+	raw, err := accountJSON(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(raw, "{\n  \"_links\"") {
+		t.Errorf("not indented like Horizon's response: %.40q", raw)
+	}
+	if !strings.Contains(raw, `"sequence": "9007199254740993"`) {
+		t.Error("sequence not written as a string, as Horizon does")
+	}
+
+	var decoded horizon.Account
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		t.Fatalf("output doesn't decode: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, account) {
+		t.Errorf("decoded = %+v\nwant %+v", decoded, account)
+	}
+}
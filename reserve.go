@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Base reserve in stroops (0.5 XLM)
+const baseReserve = 5000000
+
+// Total, reserved and spendable amounts of a single asset, in stroops
+type balanceBreakdown struct {
+	Total     int64
+	Reserved  int64
+	Spendable int64
+}
+
+// Minimum XLM balance an account must hold: (2 + subentries + sponsoring - sponsored) base reserves
+func minimumReserve(account horizon.Account) int64 {
+	entries := 2 + int64(account.SubentryCount) + int64(account.NumSponsoring) - int64(account.NumSponsored)
+	return entries * baseReserve
+}
+
+func balanceMatches(balance horizon.Balance, asset txnbuild.Asset) bool {
+	if asset.IsNative() {
+		return balance.Asset.Type == "native"
+	}
+	return balance.Asset.Code == asset.GetCode() && balance.Asset.Issuer == asset.GetIssuer()
+}
+
+// Split an account's holding of asset into what is locked and what can be sent.
+// XLM keeps the minimum reserve; every asset keeps its selling liabilities.
+func assetBreakdown(account horizon.Account, asset txnbuild.Asset) (balanceBreakdown, error) {
+	for _, balance := range account.Balances {
+		if !balanceMatches(balance, asset) {
+			continue
+		}
+
+		total, err := amount.ParseInt64(balance.Balance)
+		if err != nil {
+			return balanceBreakdown{}, fmt.Errorf("invalid balance %q: %v", balance.Balance, err)
+		}
+
+		var reserved int64
+		if balance.SellingLiabilities != "" {
+			reserved, err = amount.ParseInt64(balance.SellingLiabilities)
+			if err != nil {
+				return balanceBreakdown{}, fmt.Errorf("invalid liabilities %q: %v", balance.SellingLiabilities, err)
+			}
+		}
+		if asset.IsNative() {
+			reserved += minimumReserve(account)
+		}
+
+		spendable := total - reserved
+		if spendable < 0 {
+			spendable = 0
+		}
+		return balanceBreakdown{Total: total, Reserved: reserved, Spendable: spendable}, nil
+	}
+	return balanceBreakdown{}, fmt.Errorf("no %s balance on account", assetCode(asset))
+}
+
+func assetCode(asset txnbuild.BasicAsset) string {
+	if asset.IsNative() {
+		return "XLM"
+	}
+	return asset.GetCode()
+}
+
+func breakdownText(b balanceBreakdown, code string) string {
+	return fmt.Sprintf("Total: %s %s\nReserved: %s %s\nSpendable: %s %s",
+		amount.StringFromInt64(b.Total), code,
+		amount.StringFromInt64(b.Reserved), code,
+		amount.StringFromInt64(b.Spendable), code)
+}
+
+// Most of asset account can send in a single-operation transaction at
+// baseFee, leaving the reserve and, for XLM, the fee
+func maxSendable(account horizon.Account, asset txnbuild.Asset, baseFee int64) (int64, error) {
+	breakdown, err := assetBreakdown(account, asset)
+	if err != nil {
+		return 0, err
+	}
+	spendable := breakdown.Spendable
+	if asset.IsNative() {
+		spendable -= baseFee
+	}
+	return max(spendable, 0), nil
+}
+
+// Refuse an XLM payment that, with its fee, would take account below its
+// minimum reserve
+func checkReserve(account horizon.Account, sendAmount string, baseFee int64) error {
+	sent, err := amount.ParseInt64(sendAmount)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %v", sendAmount, err)
+	}
+	spendable, err := maxSendable(account, txnbuild.NativeAsset{}, baseFee)
+	if err != nil {
+		return err
+	}
+	if sent > spendable {
+		return fmt.Errorf("this would leave you below the %s XLM minimum reserve; you can send at most %s XLM",
+			stroopsToXLM(minimumReserve(account)), amount.StringFromInt64(spendable))
+	}
+	return nil
+}
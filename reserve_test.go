@@ -0,0 +1,145 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Account holding xlm and, when usd isn't empty, a USD trustline
+func reserveAccount(subentries, sponsoring, sponsored uint32, xlm, xlmLiabilities, usd string) horizon.Account {
+	account := horizon.Account{
+		AccountID:     testWallet,
+		SubentryCount: int32(subentries),
+		NumSponsoring: sponsoring,
+		NumSponsored:  sponsored,
+		Balances: []horizon.Balance{{
+			Balance:            xlm,
+			SellingLiabilities: xlmLiabilities,
+			Asset:              base.Asset{Type: "native"},
+		}},
+	}
+	if usd != "" {
+		account.Balances = append(account.Balances, horizon.Balance{
+			Balance: usd,
+			Asset:   base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: testOther},
+		})
+	}
+	return account
+}
+
+func TestMinimumReserve(t *testing.T) {
+	tests := []struct {
+		name                              string
+		subentries, sponsoring, sponsored uint32
+		expected                          int64
+	}{
+		{"bare account", 0, 0, 0, 10000000},
+		{"subentries", 3, 0, 0, 25000000},
+		{"sponsoring", 1, 2, 0, 25000000},
+		{"sponsored", 2, 0, 2, 10000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := reserveAccount(tt.subentries, tt.sponsoring, tt.sponsored, "0", "", "")
+			if got := minimumReserve(account); got != tt.expected {
+				t.Errorf("minimumReserve() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMaxSendable(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+	tests := []struct {
+		name     string
+		account  horizon.Account
+		asset    txnbuild.Asset
+		expected int64
+		wantErr  bool
+	}{
+		{"xlm keeps reserve and fee", reserveAccount(0, 0, 0, "10", "", ""), txnbuild.NativeAsset{}, 90000000 - 100, false},
+		{"xlm keeps liabilities", reserveAccount(1, 0, 0, "10", "2", ""), txnbuild.NativeAsset{}, 65000000 - 100, false},
+		{"xlm below reserve", reserveAccount(0, 0, 0, "0.5", "", ""), txnbuild.NativeAsset{}, 0, false},
+		{"xlm fee takes the rest", reserveAccount(0, 0, 0, "1.0000050", "", ""), txnbuild.NativeAsset{}, 0, false},
+		{"unreadable balance", reserveAccount(0, 0, 0, "lots", "", ""), txnbuild.NativeAsset{}, 0, true},
+		{"credit asset has no reserve", reserveAccount(1, 0, 0, "1", "", "25"), usd, 250000000, false},
+		{"no trustline", reserveAccount(0, 0, 0, "10", "", ""), usd, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := maxSendable(tt.account, tt.asset, 100)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("maxSendable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("maxSendable() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckReserve(t *testing.T) {
+	account := reserveAccount(0, 0, 0, "10", "", "")
+	tests := []struct {
+		amount string
+		valid  bool
+	}{
+		{"1", true},
+		{"8.9999900", true},
+		{"8.9999901", false},
+		{"10", false},
+		{"abc", false},
+	}
+
+	for _, tt := range tests {
+		err := checkReserve(account, tt.amount, 100)
+		if (err == nil) != tt.valid {
+			t.Errorf("checkReserve(%q) = %v, want valid %v", tt.amount, err, tt.valid)
+		}
+	}
+}
+
+func TestAssetBreakdown(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+	withOffer := reserveAccount(2, 0, 0, "20", "3", "50")
+	withOffer.Balances[1].SellingLiabilities = "12.5"
+
+	tests := []struct {
+		name     string
+		account  horizon.Account
+		asset    txnbuild.Asset
+		expected balanceBreakdown
+		wantErr  bool
+	}{
+		{"native", reserveAccount(0, 0, 0, "10", "", ""), txnbuild.NativeAsset{},
+			balanceBreakdown{Total: 100000000, Reserved: 10000000, Spendable: 90000000}, false},
+		{"trustline adds a reserve", reserveAccount(1, 0, 0, "10", "", "5"), txnbuild.NativeAsset{},
+			balanceBreakdown{Total: 100000000, Reserved: 15000000, Spendable: 85000000}, false},
+		{"offer locks native liabilities", withOffer, txnbuild.NativeAsset{},
+			balanceBreakdown{Total: 200000000, Reserved: 50000000, Spendable: 150000000}, false},
+		{"offer locks trustline liabilities", withOffer, usd,
+			balanceBreakdown{Total: 500000000, Reserved: 125000000, Spendable: 375000000}, false},
+		{"sponsored entries are free", reserveAccount(2, 0, 2, "10", "", ""), txnbuild.NativeAsset{},
+			balanceBreakdown{Total: 100000000, Reserved: 10000000, Spendable: 90000000}, false},
+		{"sponsoring costs reserves", reserveAccount(0, 4, 0, "2", "", ""), txnbuild.NativeAsset{},
+			balanceBreakdown{Total: 20000000, Reserved: 30000000, Spendable: 0}, false},
+		{"missing trustline", reserveAccount(0, 0, 0, "10", "", ""), usd, balanceBreakdown{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := assetBreakdown(tt.account, tt.asset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("assetBreakdown() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("assetBreakdown() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
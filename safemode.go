@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/txnbuild"
+)
+
+const defaultSafeModeCap = "500"
+
+var errSafeModeHeld = errors.New("safe mode: this mainnet send is above the cap and needs your wallet password")
+
+// A safe mode cap in stroops (or the fiat equivalent); an unreadable cap is
+// zero so it holds back everything rather than nothing
+func safeModeLimit(capValue string) int64 {
+	limit, err := amount.ParseInt64(capValue)
+	if err != nil {
+		return 0
+	}
+	return limit
+}
+
+// The XLM cap in effect, the default when none is set
+func safeModeCapXLM(s Settings) string {
+	if s.SafeModeCap == "" {
+		return defaultSafeModeCap
+	}
+	return s.SafeModeCap
+}
+
+// Whether safe mode requires extra authentication before sending stroops of
+// XLM. Only mainnet sends above the XLM cap, or above the fiat cap at
+// xlmPrice, are held back. With a fiat cap but no price (xlmPrice 0) the
+// value can't be checked, so the send is held back too.
+func safeModeBlocks(s Settings, network string, stroops int64, xlmPrice float64) bool {
+	if !s.SafeMode || network != "public" || stroops == 0 {
+		return false
+	}
+	if stroops > safeModeLimit(safeModeCapXLM(s)) {
+		return true
+	}
+	if s.SafeModeFiatCap == "" {
+		return false
+	}
+	if xlmPrice <= 0 {
+		return true
+	}
+	return float64(stroops)*xlmPrice > float64(safeModeLimit(s.SafeModeFiatCap))
+}
+
+// "500 XLM" or "500 XLM or €100", for telling the user what safe mode allows
+func safeModeCapText(s Settings) string {
+	text := safeModeCapXLM(s) + " XLM"
+	if s.SafeModeFiatCap != "" {
+		text += " or " + fiatSymbols[fiatCurrencyOf(s)] + s.SafeModeFiatCap
+	}
+	return text
+}
+
+// Whether going from old to next settings lets larger sends through without
+// the password: turning safe mode off, raising a cap, dropping the fiat cap
+// or changing the currency it is counted in
+func weakensSafeMode(old, next Settings) bool {
+	if !old.SafeMode {
+		return false
+	}
+	if !next.SafeMode {
+		return true
+	}
+	if safeModeLimit(safeModeCapXLM(next)) > safeModeLimit(safeModeCapXLM(old)) {
+		return true
+	}
+	if old.SafeModeFiatCap == "" {
+		return false
+	}
+	return next.SafeModeFiatCap == "" ||
+		fiatCurrencyOf(next) != fiatCurrencyOf(old) ||
+		safeModeLimit(next.SafeModeFiatCap) > safeModeLimit(old.SafeModeFiatCap)
+}
+
+// Run apply to move from old to next settings, first asking for the wallet
+// password if the change weakens safe mode
+func confirmSafeModeChange(old, next Settings, window fyne.Window, apply func()) {
+	if !weakensSafeMode(old, next) {
+		apply()
+		return
+	}
+
+	passwordEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		widget.NewFormItem("", widget.NewLabel("Turning off safe mode or raising its cap needs your wallet password.")),
+		widget.NewFormItem("Password", passwordEntry),
+	}
+	dialog.ShowForm("Confirm Identity", "Apply", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		if !checkWalletPassword(passwordEntry.Text) {
+			dialog.ShowError(fmt.Errorf("wrong password; settings not changed"), window)
+			return
+		}
+		apply()
+	}, window)
+}
+
+// Stroops of XLM the operations move out of account, with txSource standing in
+// for operations that don't set their own source. A merge hands over the whole
+// balance, and an amount that can't be read counts as unlimited, so both always
+// exceed any cap.
+func outgoingXLM(ops []txnbuild.Operation, txSource, account string) int64 {
+	var total int64
+	add := func(asset txnbuild.Asset, value string) {
+		if asset == nil || !asset.IsNative() {
+			return
+		}
+		stroops, err := amount.ParseInt64(strings.TrimSpace(value))
+		if err != nil || stroops > math.MaxInt64-total {
+			total = math.MaxInt64
+			return
+		}
+		total += stroops
+	}
+
+	for _, op := range ops {
+		source := op.GetSourceAccount()
+		if source == "" {
+			source = txSource
+		}
+		if accountID, _, err := decodeMuxedAddress(source); err == nil {
+			source = accountID
+		}
+		if source != account {
+			continue
+		}
+
+		switch op := op.(type) {
+		case *txnbuild.Payment:
+			add(op.Asset, op.Amount)
+		case *txnbuild.PathPaymentStrictSend:
+			add(op.SendAsset, op.SendAmount)
+		case *txnbuild.PathPaymentStrictReceive:
+			add(op.SendAsset, op.SendMax)
+		case *txnbuild.CreateAccount:
+			add(txnbuild.NativeAsset{}, op.Amount)
+		case *txnbuild.CreateClaimableBalance:
+			add(op.Asset, op.Amount)
+		case *txnbuild.AccountMerge:
+			total = math.MaxInt64
+		}
+	}
+	return total
+}
+
+// Whether safe mode holds back sending stroops of XLM from the wallet account
+// when approved stroops have already been cleared with the password
+func safeModeHolds(stroops, approved int64) bool {
+	var price float64
+	if settings.SafeModeFiatCap != "" {
+		price, _ = lastXLMPrice(fiatCurrency())
+	}
+	return stroops > approved && safeModeBlocks(settings, wallet.Network, stroops, price)
+}
+
+// Run send with the amount of XLM it may move, first asking for the wallet
+// password when safe mode holds stroops back
+func authorizeSpend(stroops int64, send func(approved int64)) {
+	if !safeModeHolds(stroops, 0) {
+		send(0)
+		return
+	}
+
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+	passwordEntry := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", widget.NewLabel(fmt.Sprintf("Safe mode: sends above %s need your wallet password.", safeModeCapText(settings)))),
+		widget.NewFormItem("Password", passwordEntry),
+	}
+	dialog.ShowForm("Confirm Identity", "Send", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		if !checkWalletPassword(passwordEntry.Text) {
+			dialog.ShowError(fmt.Errorf("wrong password; send cancelled"), window)
+			return
+		}
+		send(stroops)
+	}, window)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+const (
+	testWallet = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	testOther  = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+)
+
+func TestSafeModeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		safeMode bool
+		capXLM   string
+		capFiat  string
+		price    float64
+		network  string
+		stroops  int64
+		expected bool
+	}{
+		{"off", false, "100", "", 0, "public", 1000 * 10000000, false},
+		{"testnet", true, "100", "", 0, "testnet", 1000 * 10000000, false},
+		{"at cap", true, "100", "", 0, "public", 100 * 10000000, false},
+		{"one stroop over cap", true, "100", "", 0, "public", 100*10000000 + 1, true},
+		{"default cap", true, "", "", 0, "public", 501 * 10000000, true},
+		{"nothing sent", true, "100", "", 0, "public", 0, false},
+		{"unreadable cap", true, "lots", "", 0, "public", 1, true},
+		{"under both caps", true, "100", "20", 0.1, "public", 100 * 10000000, false},
+		{"at fiat cap", true, "1000", "20", 0.1, "public", 200 * 10000000, false},
+		{"over fiat cap only", true, "1000", "20", 0.1, "public", 201 * 10000000, true},
+		{"over XLM cap only", true, "100", "1000", 0.1, "public", 101 * 10000000, true},
+		{"fiat cap without a price", true, "1000", "20", 0, "public", 1 * 10000000, true},
+		{"fiat cap on testnet", true, "1000", "20", 0, "testnet", 1 * 10000000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Settings{SafeMode: tt.safeMode, SafeModeCap: tt.capXLM, SafeModeFiatCap: tt.capFiat}
+			if got := safeModeBlocks(s, tt.network, tt.stroops, tt.price); got != tt.expected {
+				t.Errorf("safeModeBlocks() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOutgoingXLM(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+
+	tests := []struct {
+		name     string
+		ops      []txnbuild.Operation
+		expected int64
+	}{
+		{"payment", []txnbuild.Operation{&txnbuild.Payment{Destination: testOther, Amount: "10", Asset: txnbuild.NativeAsset{}}}, 10 * 10000000},
+		{"credit payment", []txnbuild.Operation{&txnbuild.Payment{Destination: testOther, Amount: "10", Asset: usd}}, 0},
+		{"batch adds up", []txnbuild.Operation{
+			&txnbuild.Payment{Destination: testOther, Amount: "1", Asset: txnbuild.NativeAsset{}},
+			&txnbuild.CreateAccount{Destination: testOther, Amount: "2"},
+			&txnbuild.PathPaymentStrictSend{SendAsset: txnbuild.NativeAsset{}, SendAmount: "3", Destination: testOther, DestAsset: usd, DestMin: "1"},
+		}, 6 * 10000000},
+		{"other source", []txnbuild.Operation{&txnbuild.Payment{Destination: testWallet, Amount: "10", Asset: txnbuild.NativeAsset{}, SourceAccount: testOther}}, 0},
+		{"merge", []txnbuild.Operation{&txnbuild.AccountMerge{Destination: testOther}}, math.MaxInt64},
+		{"unreadable amount", []txnbuild.Operation{&txnbuild.Payment{Destination: testOther, Amount: "ten", Asset: txnbuild.NativeAsset{}}}, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outgoingXLM(tt.ops, testWallet, testWallet); got != tt.expected {
+				t.Errorf("outgoingXLM() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWeakensSafeMode(t *testing.T) {
+	on := Settings{SafeMode: true, SafeModeCap: "100", SafeModeFiatCap: "50", FiatCurrency: "usd"}
+	change := func(edit func(s *Settings)) Settings {
+		s := on
+		edit(&s)
+		return s
+	}
+	tests := []struct {
+		name     string
+		old      Settings
+		next     Settings
+		expected bool
+	}{
+		{"unchanged", on, on, false},
+		{"turned off", on, change(func(s *Settings) { s.SafeMode = false }), true},
+		{"turned on", Settings{}, on, false},
+		{"changed while off", Settings{SafeModeCap: "10"}, Settings{SafeModeCap: "99999"}, false},
+		{"cap raised", on, change(func(s *Settings) { s.SafeModeCap = "100.0000001" }), true},
+		{"cap lowered", on, change(func(s *Settings) { s.SafeModeCap = "10" }), false},
+		{"cap cleared to a higher default", on, change(func(s *Settings) { s.SafeModeCap = "" }), true},
+		{"default made explicit", Settings{SafeMode: true}, Settings{SafeMode: true, SafeModeCap: defaultSafeModeCap}, false},
+		{"unreadable cap replaced", change(func(s *Settings) { s.SafeModeCap = "lots" }), on, true},
+		{"fiat cap raised", on, change(func(s *Settings) { s.SafeModeFiatCap = "60" }), true},
+		{"fiat cap lowered", on, change(func(s *Settings) { s.SafeModeFiatCap = "5" }), false},
+		{"fiat cap removed", on, change(func(s *Settings) { s.SafeModeFiatCap = "" }), true},
+		{"fiat cap added", change(func(s *Settings) { s.SafeModeFiatCap = "" }), on, false},
+		{"fiat currency changed", on, change(func(s *Settings) { s.FiatCurrency = "eur" }), true},
+		{"currency changed without fiat cap", Settings{SafeMode: true}, Settings{SafeMode: true, FiatCurrency: "eur"}, false},
+		{"unrelated setting", on, change(func(s *Settings) { s.Theme = "dark" }), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weakensSafeMode(tt.old, tt.next); got != tt.expected {
+				t.Errorf("weakensSafeMode() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSafeModeCapText(t *testing.T) {
+	if got := safeModeCapText(Settings{}); got != "500 XLM" {
+		t.Errorf("default = %q", got)
+	}
+	if got := safeModeCapText(Settings{SafeModeCap: "20", SafeModeFiatCap: "100", FiatCurrency: "eur"}); got != "20 XLM or €100" {
+		t.Errorf("with fiat cap = %q", got)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Protocol limit on the home domain length
+const maxHomeDomainLen = 32
+
+// Security options of an account that SetOptions can change
+type accountSecurity struct {
+	MasterWeight int
+	Low          int
+	Medium       int
+	High         int
+	HomeDomain   string
+	Signers      map[string]int // other signers by key
+}
+
+func currentSecurity(account horizon.Account) accountSecurity {
+	s := accountSecurity{
+		Low:        int(account.Thresholds.LowThreshold),
+		Medium:     int(account.Thresholds.MedThreshold),
+		High:       int(account.Thresholds.HighThreshold),
+		HomeDomain: account.HomeDomain,
+		Signers:    make(map[string]int),
+	}
+	for _, signer := range account.Signers {
+		if signer.Key == account.AccountID {
+			s.MasterWeight = int(signer.Weight)
+		} else {
+			s.Signers[signer.Key] = int(signer.Weight)
+		}
+	}
+	return s
+}
+
+func securityText(s accountSecurity) string {
+	lines := []string{
+		fmt.Sprintf("Master weight: %d", s.MasterWeight),
+		fmt.Sprintf("Thresholds: low %d, medium %d, high %d", s.Low, s.Medium, s.High),
+		fmt.Sprintf("Home domain: %s", s.HomeDomain),
+	}
+	if len(s.Signers) == 0 {
+		lines = append(lines, "No other signers")
+	}
+	keys := make([]string, 0, len(s.Signers))
+	for key := range s.Signers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("Signer %s: weight %d", shortAddress(key), s.Signers[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Total weight of every key that can sign for the account
+func totalSignerWeight(s accountSecurity) int {
+	total := s.MasterWeight
+	for _, weight := range s.Signers {
+		total += weight
+	}
+	return total
+}
+
+// SetOptions moving from current to desired, or nil if nothing changes. Only
+// one signer can be added, updated or removed (weight 0) per operation.
+func setOptionsOp(current, desired accountSecurity) (*txnbuild.SetOptions, error) {
+	op := &txnbuild.SetOptions{}
+	changed := false
+	threshold := func(from, to int, field **txnbuild.Threshold) {
+		if from != to {
+			*field = txnbuild.NewThreshold(txnbuild.Threshold(to))
+			changed = true
+		}
+	}
+	threshold(current.MasterWeight, desired.MasterWeight, &op.MasterWeight)
+	threshold(current.Low, desired.Low, &op.LowThreshold)
+	threshold(current.Medium, desired.Medium, &op.MediumThreshold)
+	threshold(current.High, desired.High, &op.HighThreshold)
+	if current.HomeDomain != desired.HomeDomain {
+		domain := desired.HomeDomain
+		op.HomeDomain = &domain
+		changed = true
+	}
+
+	for key, weight := range desired.Signers {
+		if current.Signers[key] == weight {
+			continue
+		}
+		if op.Signer != nil {
+			return nil, fmt.Errorf("only one signer can change at a time")
+		}
+		op.Signer = &txnbuild.Signer{Address: key, Weight: txnbuild.Threshold(weight)}
+		changed = true
+	}
+	if !changed {
+		return nil, nil
+	}
+	return op, nil
+}
+
+// Parse a weight or threshold field, which the protocol limits to 0-255
+func parseWeight(name, text string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || n < 0 || n > 255 {
+		return 0, fmt.Errorf("%s must be a number from 0 to 255", name)
+	}
+	return n, nil
+}
+
+// Ways desired could leave the account unusable, worth a second confirmation
+func securityWarnings(desired accountSecurity) []string {
+	var warnings []string
+	if desired.MasterWeight == 0 {
+		warnings = append(warnings, "The master key will no longer be able to sign for this account.")
+	}
+	if total := totalSignerWeight(desired); total < desired.High || total == 0 {
+		warnings = append(warnings, fmt.Sprintf("All signers together will have weight %d, below the high threshold of %d. "+
+			"The account would be locked and its settings could never change again.", total, desired.High))
+	} else if total < desired.Medium {
+		warnings = append(warnings, fmt.Sprintf("All signers together will have weight %d, below the medium threshold of %d. "+
+			"Payments would no longer be possible.", total, desired.Medium))
+	}
+	return warnings
+}
+
+func showAccountSecurityDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	account, err := activeSession().Account()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading account: %v", err), window)
+		return
+	}
+	current := currentSecurity(account)
+
+	weightEntry := func(n int) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetText(strconv.Itoa(n))
+		return e
+	}
+	masterEntry := weightEntry(current.MasterWeight)
+	lowEntry := weightEntry(current.Low)
+	mediumEntry := weightEntry(current.Medium)
+	highEntry := weightEntry(current.High)
+	domainEntry := widget.NewEntry()
+	domainEntry.SetText(current.HomeDomain)
+	domainEntry.SetPlaceHolder("example.com")
+	signerEntry := widget.NewEntry()
+	signerEntry.SetPlaceHolder("G... signer to add, update or remove (optional)")
+	signerWeightEntry := widget.NewEntry()
+	signerWeightEntry.SetPlaceHolder("Weight, 0 to remove")
+
+	currentLabel := widget.NewLabel(securityText(current))
+	currentLabel.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Current", currentLabel),
+		widget.NewFormItem("Master Weight", masterEntry),
+		widget.NewFormItem("Low Threshold", lowEntry),
+		widget.NewFormItem("Medium Threshold", mediumEntry),
+		widget.NewFormItem("High Threshold", highEntry),
+		widget.NewFormItem("Home Domain", domainEntry),
+		widget.NewFormItem("Signer", signerEntry),
+		widget.NewFormItem("Signer Weight", signerWeightEntry),
+	}
+
+	dialog.ShowForm("Account Security", "Review", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		desired := accountSecurity{HomeDomain: strings.TrimSpace(domainEntry.Text), Signers: make(map[string]int)}
+		for key, weight := range current.Signers {
+			desired.Signers[key] = weight
+		}
+		for _, field := range []struct {
+			name  string
+			entry *widget.Entry
+			value *int
+		}{
+			{"master weight", masterEntry, &desired.MasterWeight},
+			{"low threshold", lowEntry, &desired.Low},
+			{"medium threshold", mediumEntry, &desired.Medium},
+			{"high threshold", highEntry, &desired.High},
+		} {
+			n, err := parseWeight(field.name, field.entry.Text)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			*field.value = n
+		}
+		if len(desired.HomeDomain) > maxHomeDomainLen {
+			dialog.ShowError(fmt.Errorf("home domain must be at most %d characters", maxHomeDomainLen), window)
+			return
+		}
+		if signer := strings.TrimSpace(signerEntry.Text); signer != "" {
+			if !strkey.IsValidEd25519PublicKey(signer) {
+				dialog.ShowError(fmt.Errorf("invalid signer address"), window)
+				return
+			}
+			if signer == wallet.PublicKey {
+				dialog.ShowError(fmt.Errorf("use Master Weight to change the account's own key"), window)
+				return
+			}
+			weight, err := parseWeight("signer weight", signerWeightEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if weight == 0 {
+				delete(desired.Signers, signer)
+				if _, ok := current.Signers[signer]; ok {
+					desired.Signers[signer] = 0
+				}
+			} else {
+				desired.Signers[signer] = weight
+			}
+		}
+
+		op, err := setOptionsOp(current, desired)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if op == nil {
+			dialog.ShowInformation("Account Security", "Nothing to change.", window)
+			return
+		}
+		for key, weight := range desired.Signers {
+			if weight == 0 {
+				delete(desired.Signers, key)
+			}
+		}
+		confirmSecurityChange(current, desired, op)
+	}, window)
+}
+
+// Show the before and after state, with a second confirmation for changes
+// that could lock the account
+func confirmSecurityChange(current, desired accountSecurity, op *txnbuild.SetOptions) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	submit := func() {
+		submitWithFeedback([]txnbuild.Operation{op}, nil, func(hash string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Account security updated! Hash: %s", hash), window)
+		})
+	}
+
+	grid := widget.NewTextGrid()
+	grid.SetText("Before:\n" + securityText(current) + "\n\nAfter:\n" + securityText(desired))
+	dialog.ShowCustomConfirm("Confirm Changes", "Apply", "Cancel", container.NewScroll(grid), func(ok bool) {
+		if !ok {
+			return
+		}
+		warnings := securityWarnings(desired)
+		if len(warnings) == 0 {
+			submit()
+			return
+		}
+		message := strings.Join(warnings, "\n\n") + "\n\nThis may not be reversible. Apply anyway?"
+		dialog.ShowConfirm("Warning", message, func(ok bool) {
+			if ok {
+				submit()
+			}
+		}, window)
+	}, window)
+}
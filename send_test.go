@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestPreparePayment(t *testing.T) {
+	source, destination, unfunded := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+	muxed, _ := muxedAddress(destination.Address(), 9)
+
+	fake := horizontest.NewFakeHorizon(
+		horizon.Account{AccountID: source.Address(), Balances: []horizon.Balance{
+			{Balance: "10.0000000", Asset: base.Asset{Type: "native"}},
+		}},
+		horizon.Account{AccountID: destination.Address(), Balances: []horizon.Balance{
+			{Balance: "1.0000000", Asset: base.Asset{Type: "native"}},
+		}},
+	)
+	accountRecords.clear()
+	defer accountRecords.clear()
+
+	text := memoSpec{Type: "text", Value: "rent"}
+	tests := []struct {
+		name      string
+		recipient string
+		amount    string
+		memo      memoSpec
+		asset     txnbuild.Asset
+		wantErr   error // matched with errors.Is when set
+		wantFail  bool
+	}{
+		{name: "xlm", recipient: destination.Address(), amount: "5", memo: text, asset: txnbuild.NativeAsset{}},
+		{name: "muxed", recipient: muxed, amount: "5", memo: memoSpec{Type: "none"}, asset: txnbuild.NativeAsset{}},
+		{name: "empty recipient", recipient: " ", amount: "5", asset: txnbuild.NativeAsset{}, wantFail: true},
+		{name: "bad amount", recipient: destination.Address(), amount: "1.123456789", asset: txnbuild.NativeAsset{}, wantFail: true},
+		{name: "self", recipient: source.Address(), amount: "1", asset: txnbuild.NativeAsset{}, wantErr: errSelfPayment},
+		{name: "bad memo", recipient: destination.Address(), amount: "1", memo: memoSpec{Type: "id", Value: "x"}, asset: txnbuild.NativeAsset{}, wantFail: true},
+		{name: "unfunded destination", recipient: unfunded.Address(), amount: "1", asset: txnbuild.NativeAsset{}, wantFail: true},
+		{name: "no trustline", recipient: destination.Address(), amount: "1", asset: usd, wantFail: true},
+		{name: "below reserve", recipient: destination.Address(), amount: "9.5", asset: txnbuild.NativeAsset{}, wantFail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment, memo, err := preparePayment(fake, "testnet", source.Address(), tt.recipient, tt.amount, tt.memo, tt.asset, txnbuild.MinBaseFee)
+			if tt.wantErr != nil || tt.wantFail {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Errorf("preparePayment() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if payment.Destination != tt.recipient || payment.Amount != tt.amount || payment.Asset != tt.asset {
+				t.Errorf("payment = %+v", payment)
+			}
+			want, _ := buildMemo(tt.memo)
+			if memo != want {
+				t.Errorf("memo = %#v, want %#v", memo, want)
+			}
+		})
+	}
+	if len(fake.Submitted) != 0 {
+		t.Errorf("preparePayment submitted %d transactions", len(fake.Submitted))
+	}
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+const sep7Scheme = "web+stellar:"
+
+// A SEP-7 "tx" request asking another wallet to sign an envelope
+type sep7Tx struct {
+	XDR               string
+	Callback          string
+	OriginDomain      string
+	NetworkPassphrase string
+}
+
+// How long a SEP-7 callback gets to accept a signed transaction
+const sep7CallbackTimeout = 30 * time.Second
+
+// Signed envelopes are only posted over HTTPS, so they can't be read or
+// swapped on the way
+func checkSEP7Callback(callback string) error {
+	u, err := url.Parse(callback)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("callback %q must be an https URL", callback)
+	}
+	return nil
+}
+
+// Encode a transaction request as a web+stellar:tx URI. The passphrase is only
+// included for networks other than the public one, as the spec requires.
+func buildSEP7TxURI(req sep7Tx) (string, error) {
+	if req.XDR == "" {
+		return "", fmt.Errorf("transaction XDR is required")
+	}
+
+	params := url.Values{}
+	params.Set("xdr", req.XDR)
+	if req.Callback != "" {
+		if err := checkSEP7Callback(req.Callback); err != nil {
+			return "", err
+		}
+		params.Set("callback", "url:"+req.Callback)
+	}
+	if req.OriginDomain != "" {
+		params.Set("origin_domain", req.OriginDomain)
+	}
+	if req.NetworkPassphrase != "" && req.NetworkPassphrase != network.PublicNetworkPassphrase {
+		params.Set("network_passphrase", req.NetworkPassphrase)
+	}
+	return sep7Scheme + "tx?" + params.Encode(), nil
+}
+
+// A web+stellar:pay URI asking for a payment to destination, with no amount
+// or asset so the payer chooses
+func buildSEP7PayURI(destination, networkPassphrase string) string {
+	params := url.Values{}
+	params.Set("destination", destination)
+	if networkPassphrase != "" && networkPassphrase != network.PublicNetworkPassphrase {
+		params.Set("network_passphrase", networkPassphrase)
+	}
+	return sep7Scheme + "pay?" + params.Encode()
+}
+
+// Operation ("tx" or "pay") and parameters of a SEP-7 URI
+func splitSEP7URI(uri string) (string, url.Values, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(uri), sep7Scheme)
+	if !ok {
+		return "", nil, fmt.Errorf("not a %s URI", sep7Scheme)
+	}
+	operation, query, _ := strings.Cut(rest, "?")
+	if operation != "tx" && operation != "pay" {
+		return "", nil, fmt.Errorf("unsupported SEP-7 operation %q", operation)
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid URI parameters: %v", err)
+	}
+	return operation, params, nil
+}
+
+func parseSEP7TxURI(uri string) (sep7Tx, error) {
+	operation, params, err := splitSEP7URI(uri)
+	if err != nil {
+		return sep7Tx{}, err
+	}
+	if operation != "tx" {
+		return sep7Tx{}, fmt.Errorf("not a transaction link")
+	}
+
+	req := sep7Tx{
+		XDR:               params.Get("xdr"),
+		OriginDomain:      params.Get("origin_domain"),
+		NetworkPassphrase: params.Get("network_passphrase"),
+	}
+	if req.XDR == "" {
+		return sep7Tx{}, fmt.Errorf("missing xdr parameter")
+	}
+	if callback := params.Get("callback"); callback != "" {
+		target, ok := strings.CutPrefix(callback, "url:")
+		if !ok {
+			return sep7Tx{}, fmt.Errorf("unsupported callback %q", callback)
+		}
+		if err := checkSEP7Callback(target); err != nil {
+			return sep7Tx{}, err
+		}
+		req.Callback = target
+	}
+	if req.NetworkPassphrase == "" {
+		req.NetworkPassphrase = network.PublicNetworkPassphrase
+	}
+	return req, nil
+}
+
+// POST a signed envelope to a SEP-7 callback as the xdr form field
+func postSEP7Callback(ctx context.Context, hc *http.Client, callback, envelope string) error {
+	if err := checkSEP7Callback(callback); err != nil {
+		return err
+	}
+	form := url.Values{"xdr": {envelope}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callback, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending to callback: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign gtx and hand it on: to the link's callback when it named one, as SEP-7
+// asks, or else to the network through hc. The hash is empty for a callback,
+// which decides itself whether and when to submit.
+func signAndDeliver(hc core.HorizonAPI, httpClient *http.Client, gtx *txnbuild.GenericTransaction, approved int64, callback string) (string, error) {
+	if callback == "" {
+		return signAndSubmitGeneric(hc, gtx, approved)
+	}
+
+	tx, feeBump, err := signGeneric(gtx, approved)
+	if err != nil {
+		return "", err
+	}
+	var envelope string
+	if feeBump != nil {
+		envelope, err = feeBump.Base64()
+	} else {
+		envelope, err = tx.Base64()
+	}
+	if err != nil {
+		return "", fmt.Errorf("error encoding transaction: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sep7CallbackTimeout)
+	defer cancel()
+	return "", postSEP7Callback(ctx, httpClient, callback, envelope)
+}
+
+// A SEP-7 "pay" request. Amount and asset may be left for the payer to choose.
+type sep7Pay struct {
+	Destination       string
+	Amount            string
+	AssetCode         string
+	AssetIssuer       string
+	Memo              string
+	MemoType          string // MEMO_TEXT, MEMO_ID, MEMO_HASH or MEMO_RETURN
+	NetworkPassphrase string
+}
+
+func parseSEP7PayURI(uri string) (sep7Pay, error) {
+	operation, params, err := splitSEP7URI(uri)
+	if err != nil {
+		return sep7Pay{}, err
+	}
+	if operation != "pay" {
+		return sep7Pay{}, fmt.Errorf("not a payment link")
+	}
+
+	req := sep7Pay{
+		Destination:       params.Get("destination"),
+		Amount:            params.Get("amount"),
+		AssetCode:         params.Get("asset_code"),
+		AssetIssuer:       params.Get("asset_issuer"),
+		Memo:              params.Get("memo"),
+		MemoType:          params.Get("memo_type"),
+		NetworkPassphrase: params.Get("network_passphrase"),
+	}
+	if req.Destination == "" {
+		return sep7Pay{}, fmt.Errorf("missing destination parameter")
+	}
+	if req.AssetCode != "" && req.AssetIssuer == "" && !strings.EqualFold(req.AssetCode, "XLM") {
+		return sep7Pay{}, fmt.Errorf("asset %s has no asset_issuer", req.AssetCode)
+	}
+	if req.Memo != "" && req.MemoType == "" {
+		req.MemoType = "MEMO_TEXT"
+	}
+	if req.NetworkPassphrase == "" {
+		req.NetworkPassphrase = network.PublicNetworkPassphrase
+	}
+	return req, nil
+}
+
+// Send form values for a payment request. SEP-7 carries hash and return
+// memos as base64, where the send form takes hex.
+func (p sep7Pay) sendParams() (sendParams, error) {
+	params := sendParams{Recipient: p.Destination, Amount: p.Amount, Asset: "XLM"}
+	if p.AssetCode != "" && !strings.EqualFold(p.AssetCode, "XLM") {
+		asset, err := parseAsset(p.AssetCode + ":" + p.AssetIssuer)
+		if err != nil {
+			return sendParams{}, err
+		}
+		params.Asset = assetString(asset)
+	}
+
+	memo := memoSpec{Type: "none"}
+	switch p.MemoType {
+	case "":
+	case "MEMO_TEXT":
+		memo = memoSpec{Type: "text", Value: p.Memo}
+	case "MEMO_ID":
+		memo = memoSpec{Type: "id", Value: p.Memo}
+	case "MEMO_HASH", "MEMO_RETURN":
+		raw, err := base64.StdEncoding.DecodeString(p.Memo)
+		if err != nil {
+			return sendParams{}, fmt.Errorf("invalid %s memo: %v", p.MemoType, err)
+		}
+		memo = memoSpec{Type: strings.ToLower(strings.TrimPrefix(p.MemoType, "MEMO_")), Value: hex.EncodeToString(raw)}
+	default:
+		return sendParams{}, fmt.Errorf("unknown memo type %q", p.MemoType)
+	}
+	if _, err := buildMemo(memo); err != nil {
+		return sendParams{}, err
+	}
+	params.Memo, params.MemoType = memo.Value, memo.Type
+	return params, nil
+}
+
+// Parse a payment link into send form values for the current network
+func paymentLinkParams(uri string) (sendParams, error) {
+	req, err := parseSEP7PayURI(uri)
+	if err != nil {
+		return sendParams{}, err
+	}
+	if req.NetworkPassphrase != currentPassphrase() {
+		return sendParams{}, fmt.Errorf("payment request is for another network (%s)", req.NetworkPassphrase)
+	}
+	return req.sendParams()
+}
+
+// Let the user hand an envelope to another signer as a link and QR code
+func showShareTransactionDialog(envelope string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	callbackEntry := widget.NewEntry()
+	callbackEntry.SetPlaceHolder("Callback URL (optional)")
+	originEntry := widget.NewEntry()
+	originEntry.SetPlaceHolder("Origin domain (optional)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Callback", callbackEntry),
+		widget.NewFormItem("Origin", originEntry),
+	}
+
+	dialog.ShowForm("Share as Link", "Create", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		uri, err := buildSEP7TxURI(sep7Tx{
+			XDR:               envelope,
+			Callback:          strings.TrimSpace(callbackEntry.Text),
+			OriginDomain:      strings.TrimSpace(originEntry.Text),
+			NetworkPassphrase: currentPassphrase(),
+		})
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		uriEntry := widget.NewMultiLineEntry()
+		uriEntry.SetText(uri)
+		uriEntry.Wrapping = fyne.TextWrapBreak
+
+		content := container.NewVBox(uriEntry, widget.NewButton("Copy Link", func() {
+			window.Clipboard().SetContent(uri)
+		}))
+		if img, err := qrImage(uri); err == nil {
+			content.Add(img)
+		} else {
+			content.Add(widget.NewLabel("Too long for a QR code; share the link instead"))
+		}
+		dialog.ShowCustom("Transaction Link", "Close", content, window)
+	}, window)
+}
+
+func showOpenTransactionLinkDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	uriEntry := widget.NewMultiLineEntry()
+	uriEntry.SetPlaceHolder("web+stellar:tx?xdr=... or web+stellar:pay?destination=...")
+	uriEntry.Wrapping = fyne.TextWrapBreak
+
+	dialog.ShowForm("Open Transaction Link", "Open", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Link", uriEntry),
+	}, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		// Payment requests go to the send form for the user to review
+		if operation, _, err := splitSEP7URI(uriEntry.Text); err == nil && operation == "pay" {
+			params, err := paymentLinkParams(uriEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			showSendDialog(nil, params)
+			return
+		}
+
+		req, err := parseSEP7TxURI(uriEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if req.NetworkPassphrase != currentPassphrase() {
+			dialog.ShowError(fmt.Errorf("transaction is for another network (%s)", req.NetworkPassphrase), window)
+			return
+		}
+
+		tx, err := txnbuild.TransactionFromXDR(req.XDR)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid transaction XDR: %v", err), window)
+			return
+		}
+		showTransactionReview(tx, req.Callback)
+	}, window)
+}
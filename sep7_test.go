@@ -0,0 +1,249 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestSEP7TxURIRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		req  sep7Tx
+	}{
+		{"public", sep7Tx{XDR: "AAAA+/=", NetworkPassphrase: network.PublicNetworkPassphrase}},
+		{"testnet with callback", sep7Tx{
+			XDR:               "AAAA",
+			Callback:          "https://example.com/sign?id=1",
+			OriginDomain:      "example.com",
+			NetworkPassphrase: network.TestNetworkPassphrase,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := buildSEP7TxURI(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hasPassphrase := strings.Contains(uri, "network_passphrase"); hasPassphrase != (tt.req.NetworkPassphrase != network.PublicNetworkPassphrase) {
+				t.Errorf("%s: network_passphrase included = %v", uri, hasPassphrase)
+			}
+			got, err := parseSEP7TxURI(uri)
+			if err != nil {
+				t.Fatalf("parseSEP7TxURI(%s): %v", uri, err)
+			}
+			if got != tt.req {
+				t.Errorf("round trip = %+v, want %+v", got, tt.req)
+			}
+		})
+	}
+
+	if _, err := buildSEP7TxURI(sep7Tx{}); err == nil {
+		t.Error("buildSEP7TxURI accepted a request without XDR")
+	}
+	if _, err := buildSEP7TxURI(sep7Tx{XDR: "AAAA", Callback: "http://example.com"}); err == nil {
+		t.Error("buildSEP7TxURI accepted a plain HTTP callback")
+	}
+}
+
+func TestParseSEP7TxURIRejects(t *testing.T) {
+	for _, uri := range []string{
+		"",
+		"stellar:tx?xdr=AAAA",
+		"web+stellar:pay?destination=" + testWallet,
+		"web+stellar:sign?xdr=AAAA",
+		"web+stellar:tx?callback=url:https://example.com",
+		"web+stellar:tx?xdr=%zz",
+		"web+stellar:tx?xdr=AAAA&callback=https://example.com",
+		"web+stellar:tx?xdr=AAAA&callback=url:http://example.com/sign",
+		"web+stellar:tx?xdr=AAAA&callback=url:https:///nohost",
+	} {
+		if _, err := parseSEP7TxURI(uri); err == nil {
+			t.Errorf("parseSEP7TxURI(%q) accepted", uri)
+		}
+	}
+}
+
+func TestParseSEP7PayURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    sep7Pay
+		wantErr bool
+	}{
+		{
+			name: "destination only",
+			uri:  buildSEP7PayURI(testWallet, ""),
+			want: sep7Pay{Destination: testWallet, NetworkPassphrase: network.PublicNetworkPassphrase},
+		},
+		{
+			name: "testnet",
+			uri:  buildSEP7PayURI(testWallet, network.TestNetworkPassphrase),
+			want: sep7Pay{Destination: testWallet, NetworkPassphrase: network.TestNetworkPassphrase},
+		},
+		{
+			name: "asset and text memo",
+			uri:  "web+stellar:pay?destination=" + testWallet + "&amount=12.5&asset_code=USD&asset_issuer=" + testOther + "&memo=rent%20may",
+			want: sep7Pay{
+				Destination: testWallet, Amount: "12.5", AssetCode: "USD", AssetIssuer: testOther,
+				Memo: "rent may", MemoType: "MEMO_TEXT", NetworkPassphrase: network.PublicNetworkPassphrase,
+			},
+		},
+		{name: "no destination", uri: "web+stellar:pay?amount=1", wantErr: true},
+		{name: "asset without issuer", uri: "web+stellar:pay?destination=" + testWallet + "&asset_code=USD", wantErr: true},
+		{name: "transaction link", uri: "web+stellar:tx?xdr=AAAA", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSEP7PayURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSEP7PayURI(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseSEP7PayURI(%q) = %+v, want %+v", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSEP7PaySendParams(t *testing.T) {
+	hashBase64 := "q6urq6urq6urq6urq6urq6urq6urq6urq6urq6urq6s="
+	tests := []struct {
+		name    string
+		pay     sep7Pay
+		want    sendParams
+		wantErr bool
+	}{
+		{"xlm", sep7Pay{Destination: testWallet, Amount: "1"}, sendParams{Recipient: testWallet, Amount: "1", Asset: "XLM", MemoType: "none"}, false},
+		{"credit asset", sep7Pay{Destination: testWallet, AssetCode: "USD", AssetIssuer: testOther},
+			sendParams{Recipient: testWallet, Asset: "USD:" + testOther, MemoType: "none"}, false},
+		{"id memo", sep7Pay{Destination: testWallet, Memo: "42", MemoType: "MEMO_ID"},
+			sendParams{Recipient: testWallet, Asset: "XLM", Memo: "42", MemoType: "id"}, false},
+		{"hash memo is base64", sep7Pay{Destination: testWallet, Memo: hashBase64, MemoType: "MEMO_HASH"},
+			sendParams{Recipient: testWallet, Asset: "XLM", Memo: strings.Repeat("ab", 32), MemoType: "hash"}, false},
+		{"bad base64", sep7Pay{Destination: testWallet, Memo: "!!", MemoType: "MEMO_RETURN"}, sendParams{}, true},
+		{"unknown memo type", sep7Pay{Destination: testWallet, Memo: "x", MemoType: "MEMO_BLOB"}, sendParams{}, true},
+		{"bad issuer", sep7Pay{Destination: testWallet, AssetCode: "USD", AssetIssuer: "GBAD"}, sendParams{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pay.sendParams()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sendParams() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignAndDeliverTransactionLink(t *testing.T) {
+	kp := keypair.MustRandom()
+	savedWallet := wallet
+	wallet = &Wallet{PublicKey: kp.Address(), SecretKey: kp.Seed(), Network: "testnet"}
+	t.Cleanup(func() {
+		wallet = savedWallet
+		accountRecords.clear()
+	})
+
+	source := txnbuild.NewSimpleAccount(kp.Address(), 1)
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &source,
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 5}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := tx.Base64()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var posted []string
+	status := http.StatusOK
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("%s with content type %q, want a form POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		posted = append(posted, r.FormValue("xdr"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	open := func(t *testing.T, uri string) (*txnbuild.GenericTransaction, string) {
+		t.Helper()
+		req, err := parseSEP7TxURI(uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gtx, err := txnbuild.TransactionFromXDR(req.XDR)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gtx, req.Callback
+	}
+
+	t.Run("callback", func(t *testing.T) {
+		posted = nil
+		uri, err := buildSEP7TxURI(sep7Tx{XDR: unsigned, Callback: server.URL + "/sign", NetworkPassphrase: network.TestNetworkPassphrase})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gtx, callback := open(t, uri)
+		fake := horizontest.NewFakeHorizon()
+
+		hash, err := signAndDeliver(fake, server.Client(), gtx, 0, callback)
+		if err != nil || hash != "" {
+			t.Fatalf("signAndDeliver = %q, %v", hash, err)
+		}
+		if len(fake.Submitted) != 0 {
+			t.Error("transaction with a callback was also submitted to Horizon")
+		}
+		if len(posted) != 1 {
+			t.Fatalf("%d envelopes posted, want 1", len(posted))
+		}
+		signed, err := txnbuild.TransactionFromXDR(posted[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signedTx, ok := signed.Transaction(); !ok || !signedBy(signedTx.Signatures(), kp) {
+			t.Error("posted envelope isn't signed by the wallet")
+		}
+	})
+
+	t.Run("callback refuses", func(t *testing.T) {
+		status = http.StatusBadRequest
+		defer func() { status = http.StatusOK }()
+		gtx, _ := open(t, "web+stellar:tx?xdr="+url.QueryEscape(unsigned))
+		if _, err := signAndDeliver(horizontest.NewFakeHorizon(), server.Client(), gtx, 0, server.URL); err == nil || !strings.Contains(err.Error(), "400") {
+			t.Errorf("error = %v, want the callback's status", err)
+		}
+	})
+
+	t.Run("no callback", func(t *testing.T) {
+		posted = nil
+		gtx, callback := open(t, "web+stellar:tx?xdr="+url.QueryEscape(unsigned))
+		fake := horizontest.NewFakeHorizon()
+		hash, err := signAndDeliver(fake, server.Client(), gtx, 0, callback)
+		if err != nil || hash == "" {
+			t.Fatalf("signAndDeliver = %q, %v", hash, err)
+		}
+		if len(fake.Submitted) != 1 || len(posted) != 0 {
+			t.Errorf("submitted %d and posted %d, want it submitted only", len(fake.Submitted), len(posted))
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// What balance, history and send need from a network connection. Implemented by
+// Session and by fakes that don't talk to Horizon.
+type walletSession interface {
+	NetworkName() string
+	Passphrase() string
+	Account() (horizon.Account, error)
+	Transactions(limit uint) ([]horizon.Transaction, error)
+	Payments(cursor string, limit uint) ([]activityRecord, string, error)
+	Build(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, sourceID string) (*txnbuild.Transaction, error)
+	Submit(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, kp *keypair.Full, cosigners ...*keypair.Full) (string, error)
+}
+
+// A Horizon client bound to one network and one account
+type Session struct {
+	Network   string
+	Client    core.HorizonAPI
+	AccountID string
+}
+
+func newSession(networkName, accountID string) *Session {
+	return &Session{
+		Network:   networkName,
+		Client:    core.HorizonClient(networkName),
+		AccountID: accountID,
+	}
+}
+
+// Session for the wallet and network currently selected in the main window
+func activeSession() walletSession {
+	return &Session{
+		Network:   wallet.Network,
+		Client:    client,
+		AccountID: wallet.PublicKey,
+	}
+}
+
+func (s *Session) NetworkName() string {
+	return s.Network
+}
+
+func (s *Session) Passphrase() string {
+	return core.Passphrase(s.Network)
+}
+
+func (s *Session) Account() (horizon.Account, error) {
+	return accountRecords.fetch(s.Client, s.Network, s.AccountID)
+}
+
+func (s *Session) Transactions(limit uint) ([]horizon.Transaction, error) {
+	page, err := s.Client.Transactions(horizonclient.TransactionRequest{
+		ForAccount: s.AccountID,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page.Embedded.Records, nil
+}
+
+// Payment-like operations involving the account, newest first, older than
+// cursor (empty for the newest), and the cursor to continue from
+func (s *Session) Payments(cursor string, limit uint) ([]activityRecord, string, error) {
+	page, err := s.Client.Payments(horizonclient.OperationRequest{
+		ForAccount: s.AccountID,
+		Order:      horizonclient.OrderDesc,
+		Cursor:     cursor,
+		Limit:      limit,
+		Join:       "transactions",
+	})
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	ops := page.Embedded.Records
+	if len(ops) == 0 {
+		return nil, cursor, nil
+	}
+	var records []activityRecord
+	for _, op := range ops {
+		if record, ok := activityFromOperation(op, s.AccountID); ok {
+			records = append(records, record)
+		}
+	}
+	return records, ops[len(ops)-1].PagingToken(), nil
+}
+
+// Times a transaction is rebuilt after tx_bad_seq before giving up
+const maxBadSeqRetries = 1
+
+// Build, sign and submit a transaction from kp's account, returning its hash.
+// A transaction rejected for a stale sequence number, such as when another
+// send went through in between, is rebuilt from a fresh account record.
+func (s *Session) Submit(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, kp *keypair.Full, cosigners ...*keypair.Full) (string, error) {
+	for attempt := 0; ; attempt++ {
+		hash, err := s.submitOnce(ops, memo, baseFee, kp, cosigners...)
+		if err == nil || attempt >= maxBadSeqRetries || transactionCode(resultCodes(err)) != "tx_bad_seq" {
+			return hash, err
+		}
+	}
+}
+
+// Headless client for the session's network, building transactions valid
+// for the window the clock settings allow
+func (s *Session) core() *core.Client {
+	c := core.NewClient(s.Client, s.Network)
+	c.TimeBounds = transactionTimeBounds
+	return c
+}
+
+// Build an unsigned transaction from sourceID's account at its next sequence
+// number, for signing here or on another device. Bypasses accountRecords for
+// the current sequence number.
+func (s *Session) Build(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, sourceID string) (*txnbuild.Transaction, error) {
+	return s.core().Build(ops, memo, baseFee, sourceID)
+}
+
+func (s *Session) submitOnce(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, kp *keypair.Full, cosigners ...*keypair.Full) (string, error) {
+	c := s.core()
+	tx, err := c.Build(ops, memo, baseFee, kp.Address())
+	if err != nil {
+		return "", err
+	}
+	tx, err = c.Sign(tx, append([]*keypair.Full{kp}, cosigners...)...)
+	if err != nil {
+		return "", err
+	}
+	rememberSubmitted(tx, s.Passphrase())
+
+	hash, err := c.SubmitTransaction(tx)
+	if err != nil {
+		return "", err
+	}
+	accountRecords.clear()
+	return hash, nil
+}
+
+// Native balance from an account record
+func nativeBalance(account horizon.Account) (string, bool) {
+	for _, balance := range account.Balances {
+		if balance.Asset.Type == "native" {
+			return balance.Balance, true
+		}
+	}
+	return "", false
+}
+
+func sessionBalanceText(s walletSession) string {
+	account, err := s.Account()
+	if err != nil {
+		return balanceErrorText(err, "")
+	}
+	balance, ok := nativeBalance(account)
+	if !ok {
+		return "No XLM balance found"
+	}
+	return fmt.Sprintf("Balance: %s XLM", balance)
+}
+
+func sessionHistoryText(s walletSession) string {
+	records, _, err := s.Payments("", 20)
+	if err != nil {
+		return fmt.Sprintf("error loading transactions: %v", err)
+	}
+	if len(records) == 0 {
+		return "No transactions"
+	}
+	return historyText(records)
+}
+
+// Balance and history of one session, loaded in the background
+func sessionPanel(s walletSession) fyne.CanvasObject {
+	balanceLabel := widget.NewLabel("Loading...")
+	history := widget.NewTextGrid()
+
+	go func() {
+		balanceLabel.SetText(sessionBalanceText(s))
+		history.SetText(sessionHistoryText(s))
+	}()
+
+	return container.NewBorder(
+		container.NewVBox(widget.NewLabel("Network: "+s.NetworkName()), balanceLabel),
+		nil, nil, nil,
+		container.NewScroll(history),
+	)
+}
+
+// Side by side view of the wallet account on testnet and the public network
+func showNetworkComparison() {
+	window := fyne.CurrentApp().NewWindow("Compare Networks")
+	window.SetContent(container.NewHSplit(
+		sessionPanel(newSession("testnet", wallet.PublicKey)),
+		sessionPanel(newSession("public", wallet.PublicKey)),
+	))
+	window.Resize(fyne.NewSize(scaled(720), scaled(480)))
+	window.Show()
+}
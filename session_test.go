@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestCurrentPassphrase(t *testing.T) {
+	saved := wallet
+	defer func() { wallet = saved }()
+
+	tests := []struct {
+		network  string
+		expected string
+	}{
+		{"public", network.PublicNetworkPassphrase},
+		{"testnet", network.TestNetworkPassphrase},
+	}
+
+	for _, tt := range tests {
+		wallet = &Wallet{Network: tt.network}
+		if got := currentPassphrase(); got != tt.expected {
+			t.Errorf("currentPassphrase() on %s = %q, want %q", tt.network, got, tt.expected)
+		}
+	}
+}
+
+func TestSessionSubmitSignsForItsNetwork(t *testing.T) {
+	source := keypair.MustRandom()
+	destination := keypair.MustRandom()
+
+	for _, name := range []string{"public", "testnet"} {
+		t.Run(name, func(t *testing.T) {
+			fake := horizontest.NewFakeHorizon(horizon.Account{AccountID: source.Address(), Sequence: 7})
+			s := &Session{Network: name, Client: fake, AccountID: source.Address()}
+
+			payment := &txnbuild.Payment{Destination: destination.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}}
+			if _, err := s.Submit([]txnbuild.Operation{payment}, nil, txnbuild.MinBaseFee, source); err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.Submitted) != 1 {
+				t.Fatalf("submitted %d transactions, want 1", len(fake.Submitted))
+			}
+			tx := fake.Submitted[0]
+			if signed, ok := horizontest.SignedNetwork(tx); !ok || signed != name {
+				t.Errorf("signed for %q (%v), want %q", signed, ok, name)
+			}
+
+			// The signature hint is the last four bytes of the signing key
+			hint := source.Hint()
+			if sigs := tx.Signatures(); len(sigs) != 1 || sigs[0].Hint != hint {
+				t.Errorf("signatures = %v, want one with hint %x", sigs, hint)
+			}
+		})
+	}
+}
+
+func TestSessionBalanceText(t *testing.T) {
+	funded := horizon.Account{AccountID: testWallet, Balances: []horizon.Balance{
+		{Balance: "12.5000000", Asset: base.Asset{Type: "native"}},
+	}}
+	tests := []struct {
+		name       string
+		accounts   []horizon.Account
+		accountErr error
+		want       string
+	}{
+		{"funded", []horizon.Account{funded}, nil, "Balance: 12.5000000 XLM"},
+		{"unfunded", nil, nil, "Account not funded"},
+		{"server error", []horizon.Account{funded}, &horizonclient.Error{Problem: problem.P{Status: 503}}, "Balance unavailable: Horizon server error (503); the network may be having problems, try again later"},
+		{"network down", []horizon.Account{funded}, errors.New("dial tcp: connection refused"), "Balance unavailable (can't reach Horizon)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := horizontest.NewFakeHorizon(tt.accounts...)
+			fake.AccountErr = tt.accountErr
+			accountRecords.clear()
+			t.Cleanup(accountRecords.clear)
+			s := &Session{Network: "testnet", Client: fake, AccountID: testWallet}
+			if got := sessionBalanceText(s); got != tt.want {
+				t.Errorf("sessionBalanceText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/amount"
+)
+
+// Non-secret preferences, kept apart from the wallet so they can be exported
+type Settings struct {
+	Version int    `json:"version"`
+	Theme   string `json:"theme"` // "system", "light" or "dark"
+
+	HighContrast bool    `json:"high_contrast"`
+	FontScale    float32 `json:"font_scale"`
+
+	LastSend *sendParams `json:"last_send,omitempty"`
+
+	// Low balance alerts: account -> asset -> threshold
+	Thresholds map[string]map[string]string `json:"thresholds,omitempty"`
+
+	DustThreshold string `json:"dust_threshold,omitempty"`
+
+	// Correct transaction time bounds for a skewed local clock
+	UseLedgerTime bool `json:"use_ledger_time,omitempty"`
+
+	// Confirm every send, not just mainnet ones and those above ConfirmThreshold XLM
+	AlwaysConfirmSends bool   `json:"always_confirm_sends,omitempty"`
+	ConfirmThreshold   string `json:"confirm_threshold,omitempty"`
+
+	Templates []sendTemplate `json:"templates,omitempty"`
+
+	// Address book of frequent recipients
+	Contacts []contact `json:"contacts,omitempty"`
+
+	// Prefilled on every new send, editable per transaction
+	DefaultMemo *memoSpec `json:"default_memo,omitempty"`
+
+	// Mainnet XLM sends above SafeModeCap, or worth more than SafeModeFiatCap
+	// in FiatCurrency, need the wallet password re-entered
+	SafeMode        bool   `json:"safe_mode,omitempty"`
+	SafeModeCap     string `json:"safe_mode_cap,omitempty"`
+	SafeModeFiatCap string `json:"safe_mode_fiat_cap,omitempty"`
+
+	// Set while an activity export is incomplete
+	ExportResume *exportResume `json:"export_resume,omitempty"`
+
+	// Background refresh interval; 0 means the default
+	PollSeconds int `json:"poll_seconds,omitempty"`
+
+	// Main window size when the app last closed; zero means the default
+	WindowWidth  float32 `json:"window_width,omitempty"`
+	WindowHeight float32 `json:"window_height,omitempty"`
+
+	// Minutes of inactivity before the wallet locks; 0 means the default
+	// and -1 never
+	AutoLockMinutes int `json:"auto_lock_minutes,omitempty"`
+
+	// Currency the balance's approximate value is shown in; empty means USD
+	FiatCurrency string `json:"fiat_currency,omitempty"`
+
+	// Seconds a Horizon request may take before it's abandoned; 0 means the default
+	HorizonTimeoutSeconds int `json:"horizon_timeout_seconds,omitempty"`
+}
+
+// Longest Horizon request timeout that can be configured
+const maxHorizonTimeout = 120 * time.Second
+
+func horizonTimeout() time.Duration {
+	if settings.HorizonTimeoutSeconds == 0 {
+		return core.DefaultRequestTimeout
+	}
+	return time.Duration(settings.HorizonTimeoutSeconds) * time.Second
+}
+
+// Version 3 added the safe mode, refresh, auto-lock, request timeout and fiat
+// preferences, and version 4 the safe mode fiat cap. Their zero values mean the
+// defaults, so older files need no upgrade step, but an older app must not
+// silently drop them on import.
+const (
+	settingsFile    = "stellar_settings.json"
+	settingsVersion = 4
+)
+
+var settings = defaultSettings()
+
+func defaultSettings() Settings {
+	return Settings{
+		Version:   settingsVersion,
+		Theme:     "system",
+		FontScale: 1,
+	}
+}
+
+// Bring settings written by an older version up to date, filling in defaults
+// for anything that version didn't know about
+func upgradeSettings(s Settings) Settings {
+	defaults := defaultSettings()
+	if s.Version < 1 {
+		if s.Theme == "" {
+			s.Theme = defaults.Theme
+		}
+	}
+	if s.Version < 2 {
+		s.FontScale = defaults.FontScale
+	}
+	s.Version = settingsVersion
+	return s
+}
+
+// Default main window size, a phone-like portrait window
+var defaultWindowSize = fyne.NewSize(360, 640)
+
+// Window size to open with: the saved one if there is a sensible one
+func windowSize(s Settings) fyne.Size {
+	if s.WindowWidth < 200 || s.WindowHeight < 200 {
+		return defaultWindowSize
+	}
+	return fyne.NewSize(s.WindowWidth, s.WindowHeight)
+}
+
+func validateSettings(s Settings) error {
+	switch s.Theme {
+	case "system", "light", "dark":
+	default:
+		return fmt.Errorf("unknown theme %q", s.Theme)
+	}
+	if s.FontScale < minFontScale || s.FontScale > maxFontScale {
+		return fmt.Errorf("font scale %.2f out of range (%g-%g)", s.FontScale, minFontScale, maxFontScale)
+	}
+	if s.PollSeconds != 0 && time.Duration(s.PollSeconds)*time.Second < minPollInterval {
+		return fmt.Errorf("refresh interval must be at least %v", minPollInterval)
+	}
+	if s.AutoLockMinutes < -1 {
+		return fmt.Errorf("invalid auto-lock timeout %d", s.AutoLockMinutes)
+	}
+	if s.HorizonTimeoutSeconds < 0 || time.Duration(s.HorizonTimeoutSeconds)*time.Second > maxHorizonTimeout {
+		return fmt.Errorf("request timeout must be between 1 and %d seconds", int(maxHorizonTimeout/time.Second))
+	}
+	if _, ok := fiatSymbols[s.FiatCurrency]; s.FiatCurrency != "" && !ok {
+		return fmt.Errorf("unknown fiat currency %q", s.FiatCurrency)
+	}
+	if s.SafeModeCap != "" {
+		if _, err := amount.ParseInt64(s.SafeModeCap); err != nil {
+			return fmt.Errorf("invalid safe mode cap %q", s.SafeModeCap)
+		}
+	}
+	if s.SafeModeFiatCap != "" {
+		if _, err := amount.ParseInt64(s.SafeModeFiatCap); err != nil {
+			return fmt.Errorf("invalid safe mode fiat cap %q", s.SafeModeFiatCap)
+		}
+	}
+	if s.DefaultMemo != nil {
+		if _, err := buildMemo(*s.DefaultMemo); err != nil {
+			return fmt.Errorf("invalid default memo: %v", err)
+		}
+	}
+	if s.ConfirmThreshold != "" {
+		if _, err := amount.ParseInt64(s.ConfirmThreshold); err != nil {
+			return fmt.Errorf("invalid confirmation threshold %q", s.ConfirmThreshold)
+		}
+	}
+	for _, c := range s.Contacts {
+		if _, err := putContact(nil, c); err != nil {
+			return fmt.Errorf("invalid contact: %v", err)
+		}
+	}
+	return nil
+}
+
+// Parse a settings file, rejecting unknown fields so wallet files or other
+// JSON can't be imported by mistake
+func decodeSettings(data []byte) (Settings, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Settings{}, fmt.Errorf("invalid settings file: %v", err)
+	}
+	if header.Version > settingsVersion {
+		return Settings{}, fmt.Errorf("settings version %d is newer than this app supports (%d)", header.Version, settingsVersion)
+	}
+
+	var s Settings
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&s); err != nil {
+		return Settings{}, fmt.Errorf("invalid settings file: %v", err)
+	}
+
+	s = upgradeSettings(s)
+	if err := validateSettings(s); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
+
+func encodeSettings(s Settings) ([]byte, error) {
+	s.Version = settingsVersion
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func loadSettings() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			settings = defaultSettings()
+			return nil
+		}
+		return err
+	}
+
+	s, err := decodeSettings(data)
+	if err != nil {
+		return err
+	}
+	settings = s
+	return nil
+}
+
+func saveSettings() error {
+	data, err := encodeSettings(settings)
+	if err != nil {
+		return err
+	}
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	return core.WriteFile(path, data)
+}
+
+func showSettingsDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	themeSelect := widget.NewSelect([]string{"system", "light", "dark"}, nil)
+	themeSelect.SetSelected(settings.Theme)
+
+	contrastCheck := widget.NewCheck("High contrast", nil)
+	contrastCheck.SetChecked(settings.HighContrast)
+
+	scaleSelect := widget.NewSelect([]string{"1", "1.25", "1.5", "2"}, nil)
+	scaleSelect.SetSelected(strconv.FormatFloat(float64(settings.FontScale), 'f', -1, 32))
+
+	ledgerTimeCheck := widget.NewCheck("Base time limits on ledger time", nil)
+	ledgerTimeCheck.SetChecked(settings.UseLedgerTime)
+
+	confirmCheck := widget.NewCheck("Always confirm sends", nil)
+	confirmCheck.SetChecked(settings.AlwaysConfirmSends)
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetPlaceHolder(defaultConfirmThreshold)
+	thresholdEntry.SetText(settings.ConfirmThreshold)
+
+	safeModeCheck := widget.NewCheck("Safe mode", nil)
+	safeModeCheck.SetChecked(settings.SafeMode)
+	safeCapEntry := widget.NewEntry()
+	safeCapEntry.SetPlaceHolder(defaultSafeModeCap)
+	safeCapEntry.SetText(settings.SafeModeCap)
+	safeFiatCapEntry := widget.NewEntry()
+	safeFiatCapEntry.SetPlaceHolder("None")
+	safeFiatCapEntry.SetText(settings.SafeModeFiatCap)
+
+	pollSelect := widget.NewSelect([]string{"30", "60", "120", "300"}, nil)
+	pollSelect.SetSelected(strconv.Itoa(int(pollInterval() / time.Second)))
+
+	lockSelect := widget.NewSelect([]string{"5", "10", "30", "60", "Never"}, nil)
+	if timeout := autoLockTimeout(); timeout == 0 {
+		lockSelect.SetSelected("Never")
+	} else {
+		lockSelect.SetSelected(strconv.Itoa(int(timeout / time.Minute)))
+	}
+
+	timeoutSelect := widget.NewSelect([]string{"10", "15", "30", "60", "120"}, nil)
+	timeoutSelect.SetSelected(strconv.Itoa(int(horizonTimeout() / time.Second)))
+
+	fiatSelect := widget.NewSelect([]string{"USD", "EUR"}, nil)
+	fiatSelect.SetSelected(strings.ToUpper(fiatCurrency()))
+
+	defaultMemo := initialMemo(settings.DefaultMemo, sendParams{})
+	memoEntry := widget.NewEntry()
+	memoTypeSelect := widget.NewSelect(memoTypes, func(memoType string) {
+		memoEntry.SetPlaceHolder(memoPlaceholder(memoType))
+	})
+	memoTypeSelect.SetSelected(defaultMemo.Type)
+	memoEntry.SetText(defaultMemo.Value)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Theme", themeSelect),
+		widget.NewFormItem("", contrastCheck),
+		widget.NewFormItem("Font Scale", scaleSelect),
+		widget.NewFormItem("", ledgerTimeCheck),
+		widget.NewFormItem("", confirmCheck),
+		widget.NewFormItem("Confirm Above (XLM)", thresholdEntry),
+		widget.NewFormItem("", safeModeCheck),
+		widget.NewFormItem("Safe Mode Cap (XLM)", safeCapEntry),
+		widget.NewFormItem("Safe Mode Cap (Fiat)", safeFiatCapEntry),
+		widget.NewFormItem("Refresh (seconds)", pollSelect),
+		widget.NewFormItem("Auto-Lock (minutes)", lockSelect),
+		widget.NewFormItem("Request Timeout (seconds)", timeoutSelect),
+		widget.NewFormItem("Fiat Currency", fiatSelect),
+		widget.NewFormItem("Default Memo Type", memoTypeSelect),
+		widget.NewFormItem("Default Memo", memoEntry),
+	}
+
+	dialog.ShowForm("Settings", "Save", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		// Fill in a copy and validate all of it before anything takes effect,
+		// so a bad field further down can't leave the settings half applied
+		next := settings
+		next.Theme = themeSelect.Selected
+		next.HighContrast = contrastCheck.Checked
+		next.UseLedgerTime = ledgerTimeCheck.Checked
+		next.AlwaysConfirmSends = confirmCheck.Checked
+		next.ConfirmThreshold = strings.TrimSpace(thresholdEntry.Text)
+		next.SafeMode = safeModeCheck.Checked
+		next.SafeModeCap = strings.TrimSpace(safeCapEntry.Text)
+		next.SafeModeFiatCap = strings.TrimSpace(safeFiatCapEntry.Text)
+		if seconds, err := strconv.Atoi(pollSelect.Selected); err == nil {
+			next.PollSeconds = seconds
+		}
+		if lockSelect.Selected == "Never" {
+			next.AutoLockMinutes = -1
+		} else if minutes, err := strconv.Atoi(lockSelect.Selected); err == nil {
+			next.AutoLockMinutes = minutes
+		}
+		if seconds, err := strconv.Atoi(timeoutSelect.Selected); err == nil {
+			next.HorizonTimeoutSeconds = seconds
+		}
+		next.FiatCurrency = strings.ToLower(fiatSelect.Selected)
+		next.DefaultMemo = nil
+		if memo := (memoSpec{Type: memoTypeSelect.Selected, Value: memoEntry.Text}); memo.Type != "none" {
+			next.DefaultMemo = &memo
+		}
+		if scale, err := strconv.ParseFloat(scaleSelect.Selected, 32); err == nil {
+			next.FontScale = float32(scale)
+		}
+
+		if err := validateSettings(next); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		confirmSafeModeChange(settings, next, window, func() {
+			settings = next
+			applySettings(window)
+			if err := saveSettings(); err != nil {
+				dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+			}
+			go refreshFiatValue(wallet.Balance)
+		})
+	}, window)
+}
+
+// Put the current settings into effect after they've been edited or imported
+func applySettings(window fyne.Window) {
+	applyTheme()
+	startAutoLock(window)
+	core.SetRequestTimeout(horizonTimeout())
+	initializeClient(wallet.Network)
+}
+
+func exportSettings() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		data, err := encodeSettings(settings)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error encoding settings: %v", err), window)
+			return
+		}
+		if _, err := writer.Write(data); err != nil {
+			dialog.ShowError(fmt.Errorf("error writing settings: %v", err), window)
+			return
+		}
+		dialog.ShowInformation("Success", "Settings exported!", window)
+	}, window)
+	save.SetFileName(settingsFile)
+	save.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	save.Show()
+}
+
+func importSettings() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error reading settings: %v", err), window)
+			return
+		}
+
+		s, err := decodeSettings(data)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		confirmSafeModeChange(settings, s, window, func() {
+			settings = s
+			applySettings(window)
+			if err := saveSettings(); err != nil {
+				dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+				return
+			}
+			dialog.ShowInformation("Success", "Settings imported!", window)
+		})
+	}, window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	open.Show()
+}
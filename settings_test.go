@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"current version", `{"version": 4, "theme": "dark", "font_scale": 1.5}`, ""},
+		{"older version upgraded", `{"version": 1, "theme": "light"}`, ""},
+		{"newer version", `{"version": 5, "theme": "dark", "font_scale": 1}`, "newer than this app supports"},
+		{"unknown field", `{"version": 3, "theme": "dark", "font_scale": 1, "secret_key": "S"}`, "invalid settings file"},
+		{"bad safe mode fiat cap", `{"version": 4, "theme": "dark", "font_scale": 1, "safe_mode_fiat_cap": "$5"}`, "invalid safe mode fiat cap"},
+		{"bad theme", `{"version": 3, "theme": "neon", "font_scale": 1}`, "unknown theme"},
+		{"bad default memo", `{"version": 3, "theme": "dark", "font_scale": 1, "default_memo": {"type": "id", "value": "abc"}}`, "invalid default memo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := decodeSettings([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if s.Version != settingsVersion {
+					t.Errorf("version = %d, want %d", s.Version, settingsVersion)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+var (
+	shortcutRefresh = &desktop.CustomShortcut{KeyName: fyne.KeyR, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutSend    = &desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierShortcutDefault}
+)
+
+// Main window shortcuts: copy the address, refresh and send. A focused text
+// field handles shortcuts itself, so these never take over copying or typing
+// in the send form.
+func addMainShortcuts(c fyne.Canvas, refresh, send func()) {
+	c.AddShortcut(&fyne.ShortcutCopy{}, func(fyne.Shortcut) {
+		touchActivity()
+		if window, err := copyToClipboard(wallet.PublicKey); err == nil {
+			dialog.ShowInformation("Success", "Address copied to clipboard!", window)
+		}
+	})
+	c.AddShortcut(shortcutRefresh, func(fyne.Shortcut) {
+		touchActivity()
+		refresh()
+	})
+	c.AddShortcut(shortcutSend, func(fyne.Shortcut) {
+		touchActivity()
+		send()
+	})
+}
+
+// Drop the shortcuts while the wallet is locked
+func removeMainShortcuts(c fyne.Canvas) {
+	c.RemoveShortcut(&fyne.ShortcutCopy{})
+	c.RemoveShortcut(shortcutRefresh)
+	c.RemoveShortcut(shortcutSend)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// What a payment would do to the sender, worked out from current account
+// state without submitting anything
+type simulation struct {
+	Problems  []string
+	Code      string // asset sent
+	Before    int64  // sender's balance of the asset, in stroops
+	After     int64
+	Fee       int64
+	XLMBefore int64 // for credit assets, the XLM the fee comes out of
+	XLMAfter  int64
+}
+
+// Check a payment of amountText of asset from source the way the network
+// would, reporting each reason it would likely fail. destination is nil when
+// the recipient account doesn't exist. A claimable balance needs no trustline
+// or account at the other end, but locks one more base reserve.
+func simulatePayment(source horizon.Account, destination *horizon.Account, op txnbuild.Operation, asset txnbuild.Asset, amountText string, baseFee int64, signers []string) simulation {
+	_, claimable := op.(*txnbuild.CreateClaimableBalance)
+	sim := simulation{Code: assetCode(asset), Fee: baseFee}
+
+	sent, err := amount.ParseInt64(amountText)
+	if err != nil {
+		sim.Problems = append(sim.Problems, fmt.Sprintf("invalid amount %q", amountText))
+		return sim
+	}
+
+	xlm, err := assetBreakdown(source, txnbuild.NativeAsset{})
+	if err != nil {
+		sim.Problems = append(sim.Problems, err.Error())
+		return sim
+	}
+	if claimable {
+		xlm.Spendable -= baseReserve
+	}
+
+	if asset.IsNative() {
+		sim.Before = xlm.Total
+		switch {
+		case sent > xlm.Spendable:
+			sim.Problems = append(sim.Problems, fmt.Sprintf("op_underfunded: only %s XLM can be sent after the reserve", stroopsToXLM(max(xlm.Spendable, 0))))
+		case sent+sim.Fee > xlm.Spendable:
+			sim.Problems = append(sim.Problems, "tx_insufficient_balance: the fee would take the account below its minimum reserve")
+		}
+	} else {
+		breakdown, err := assetBreakdown(source, asset)
+		if err != nil {
+			sim.Problems = append(sim.Problems, fmt.Sprintf("op_src_no_trust: %v", err))
+		} else {
+			sim.Before = breakdown.Total
+			if sent > breakdown.Spendable {
+				sim.Problems = append(sim.Problems, fmt.Sprintf("op_underfunded: only %s %s can be sent", amount.StringFromInt64(breakdown.Spendable), sim.Code))
+			}
+		}
+		if sim.Fee > xlm.Spendable {
+			sim.Problems = append(sim.Problems, "tx_insufficient_balance: the fee would take the account below its minimum reserve")
+		}
+		sim.XLMBefore = xlm.Total
+		sim.XLMAfter = xlm.Total - sim.Fee
+	}
+	sim.After = sim.Before - sent
+	if asset.IsNative() {
+		sim.After -= sim.Fee
+	}
+
+	if !claimable {
+		switch {
+		case destination == nil:
+			sim.Problems = append(sim.Problems, "op_no_destination: the recipient account doesn't exist")
+		default:
+			switch trustlineAuthorization(destination.Balances, asset) {
+			case trustlineMissing:
+				sim.Problems = append(sim.Problems, fmt.Sprintf("op_no_trust: the recipient has no %s trustline", sim.Code))
+			case trustlineUnauthorized, trustlineMaintainLiabilities:
+				sim.Problems = append(sim.Problems, fmt.Sprintf("op_not_authorized: the recipient's %s trustline isn't authorized", sim.Code))
+			default:
+				if lineFull(*destination, asset, sent) {
+					sim.Problems = append(sim.Problems, fmt.Sprintf("op_line_full: the recipient's %s trustline limit would be exceeded", sim.Code))
+				}
+			}
+		}
+	}
+
+	if have, need := signatureWeight(source, signers), requiredWeight(source, []txnbuild.Operation{op}); have < need {
+		sim.Problems = append(sim.Problems, fmt.Sprintf("tx_bad_auth: this key has weight %d, the payment needs %d", have, need))
+	}
+	return sim
+}
+
+// Whether receiving sent of a credit asset would exceed account's trustline limit
+func lineFull(account horizon.Account, asset txnbuild.Asset, sent int64) bool {
+	if asset.IsNative() {
+		return false
+	}
+	for _, balance := range account.Balances {
+		if !balanceMatches(balance, asset) {
+			continue
+		}
+		held, err := amount.ParseInt64(balance.Balance)
+		if err != nil {
+			return false
+		}
+		limit, err := amount.ParseInt64(balance.Limit)
+		if err != nil {
+			return false
+		}
+		var buying int64
+		if balance.BuyingLiabilities != "" {
+			buying, _ = amount.ParseInt64(balance.BuyingLiabilities)
+		}
+		return held+buying+sent > limit
+	}
+	return false
+}
+
+func simulationText(sim simulation) string {
+	var b strings.Builder
+	if len(sim.Problems) == 0 {
+		b.WriteString("No problems found. The payment should succeed.\n")
+	} else {
+		b.WriteString("The payment would likely fail:\n")
+		for _, p := range sim.Problems {
+			fmt.Fprintf(&b, "  • %s\n", p)
+		}
+	}
+	fmt.Fprintf(&b, "\nFee: %s XLM\n", stroopsToXLM(sim.Fee))
+	fmt.Fprintf(&b, "%s balance: %s → %s\n", sim.Code, amount.StringFromInt64(sim.Before), amount.StringFromInt64(max(sim.After, 0)))
+	if sim.Code != "XLM" {
+		fmt.Fprintf(&b, "XLM balance: %s → %s\n", stroopsToXLM(sim.XLMBefore), stroopsToXLM(max(sim.XLMAfter, 0)))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Look up the sender and recipient and report how op, paying destination,
+// would go, without signing or submitting anything
+func showSimulation(destination string, op txnbuild.Operation, asset txnbuild.Asset, amountText string, baseFee int64) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if accountID, _, err := decodeMuxedAddress(destination); err == nil {
+		destination = accountID
+	}
+	var (
+		source, dest horizon.Account
+		destFound    bool
+		err          error
+	)
+	withProgress("Simulating payment...", func() {
+		source, err = accountRecords.fetch(client, wallet.Network, wallet.PublicKey)
+		if err != nil {
+			err = fmt.Errorf("error loading account: %v", err)
+			return
+		}
+		dest, err = accountRecords.fetch(client, wallet.Network, destination)
+		if isNotFound(err) {
+			err = nil
+			return
+		}
+		if err != nil {
+			err = fmt.Errorf("error loading recipient: %v", err)
+			return
+		}
+		destFound = true
+	}, func() {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		var recipient *horizon.Account
+		if destFound {
+			recipient = &dest
+		}
+		sim := simulatePayment(source, recipient, op, asset, amountText, baseFee, []string{wallet.PublicKey})
+		dialog.ShowInformation("Simulation", simulationText(sim), window)
+	})
+}
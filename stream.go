@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon/operations"
+)
+
+const (
+	minStreamBackoff = time.Second
+	maxStreamBackoff = time.Minute
+)
+
+var (
+	streamMu     sync.Mutex
+	cancelStream context.CancelFunc
+)
+
+// Backoff before the next reconnect attempt after waiting previous
+func nextStreamBackoff(previous time.Duration) time.Duration {
+	if previous < minStreamBackoff {
+		return minStreamBackoff
+	}
+	if next := previous * 2; next < maxStreamBackoff {
+		return next
+	}
+	return maxStreamBackoff
+}
+
+// Keep label up to date with payments to and from the wallet as Horizon
+// streams them, reconnecting with backoff until ctx is cancelled. Reconnects
+// pick up after the last payment seen so none are missed.
+func streamPayments(ctx context.Context, label *widget.Label) {
+	accountID := wallet.PublicKey
+	streamClient := client
+	cursor := "now"
+	var backoff time.Duration
+
+	for {
+		received := false
+		err := streamClient.StreamPayments(ctx, horizonclient.OperationRequest{
+			ForAccount: accountID,
+			Cursor:     cursor,
+		}, func(op operations.Operation) {
+			cursor = op.PagingToken()
+			received = true
+			accountRecords.invalidate(wallet.Network, accountID)
+			refreshBalance(label)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		if received {
+			backoff = 0
+		}
+		backoff = nextStreamBackoff(backoff)
+		log.Printf("payment stream dropped, reconnecting in %v: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// Start streaming into label, replacing any stream already running
+func startPaymentStream(label *widget.Label) {
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	if cancelStream != nil {
+		cancelStream()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelStream = cancel
+	go streamPayments(ctx, label)
+}
+
+func stopPaymentStream() {
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	if cancelStream != nil {
+		cancelStream()
+		cancelStream = nil
+	}
+}
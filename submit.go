@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Protocol limit on operations in a single transaction
+const maxOpsPerTx = 100
+
+// Build, sign and submit a transaction from the wallet account, returning its
+// hash. Every send goes through here, so this is where safe mode is enforced:
+// approved is how many stroops of XLM the user has cleared with the password.
+func submitOperationsWithFee(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, approved int64, cosigners ...*keypair.Full) (string, error) {
+	if safeModeHolds(outgoingXLM(ops, wallet.PublicKey, wallet.PublicKey), approved) {
+		return "", errSafeModeHeld
+	}
+	sourceKP, err := keypair.ParseFull(wallet.SecretKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid wallet secret key: %v", err)
+	}
+	return activeSession().Submit(ops, memo, baseFee, sourceKP, cosigners...)
+}
+
+// Split operations into groups that each fit in one transaction
+func chunkOperations(ops []txnbuild.Operation, size int) [][]txnbuild.Operation {
+	var chunks [][]txnbuild.Operation
+	for len(ops) > size {
+		chunks = append(chunks, ops[:size])
+		ops = ops[size:]
+	}
+	if len(ops) > 0 {
+		chunks = append(chunks, ops)
+	}
+	return chunks
+}
+
+// Decide whether a failed submission was rejected only for its fee or timing,
+// in which case rebuilding with a higher fee and fresh time bounds can succeed.
+// Returns the base fee to suggest for the retry.
+func feeRetryAdvice(err error, baseFee int64) (bool, int64) {
+	switch transactionCode(resultCodes(err)) {
+	case "tx_insufficient_fee", "tx_too_late":
+	default:
+		return false, 0
+	}
+
+	suggested := baseFee * 2
+	if suggested < txnbuild.MinBaseFee*2 {
+		suggested = txnbuild.MinBaseFee * 2
+	}
+	if suggested > maxBaseFee {
+		suggested = maxBaseFee
+	}
+	if suggested <= baseFee {
+		return false, 0
+	}
+	return true, suggested
+}
+
+// Submit operations from the wallet account and report failures, offering a
+// one-click retry with a higher fee when the network rejected the fee or timing
+func submitWithFeedback(ops []txnbuild.Operation, memo txnbuild.Memo, onSuccess func(hash string)) {
+	submitWithFee(ops, memo, networkBaseFee(), nil, onSuccess)
+}
+
+// Like submitWithFeedback, for transactions with operations sourced from other
+// accounts that must sign as well
+func submitWithCosigners(ops []txnbuild.Operation, memo txnbuild.Memo, cosigners []*keypair.Full, onSuccess func(hash string)) {
+	submitWithFee(ops, memo, networkBaseFee(), cosigners, onSuccess)
+}
+
+// Set while a transaction is on its way to the network
+var submitting atomic.Bool
+
+// Run work in the background behind a modal progress dialog, then call done.
+// The dialog keeps the window responsive while blocking a second submission.
+func withProgress(message string, work func(), done func()) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	progress := dialog.NewCustomWithoutButtons("Please Wait",
+		container.NewVBox(widget.NewLabel(message), widget.NewProgressBarInfinite()), window)
+	progress.Show()
+	go func() {
+		work()
+		progress.Hide()
+		done()
+	}()
+}
+
+func submitWithFee(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, cosigners []*keypair.Full, onSuccess func(hash string)) {
+	authorizeSpend(outgoingXLM(ops, wallet.PublicKey, wallet.PublicKey), func(approved int64) {
+		submitApproved(ops, memo, baseFee, approved, cosigners, onSuccess, nil)
+	})
+}
+
+// Submit once safe mode has had its say, keeping the approval for fee retries.
+// onFailure, if set, hears about each failed attempt before the usual
+// error handling and retry offers.
+func submitApproved(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, approved int64, cosigners []*keypair.Full, onSuccess func(hash string), onFailure func(err error)) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+	touchActivity()
+
+	if !submitting.CompareAndSwap(false, true) {
+		dialog.ShowInformation("Please Wait", "A transaction is already being submitted.", window)
+		return
+	}
+	var (
+		hash string
+		err  error
+	)
+	withProgress("Submitting transaction...", func() {
+		hash, err = submitOperationsWithFee(ops, memo, baseFee, approved, cosigners...)
+	}, func() {
+		submitting.Store(false)
+		handleSubmitResult(ops, memo, baseFee, approved, cosigners, onSuccess, onFailure, hash, err)
+	})
+}
+
+// Report how a submission went, offering more signers or a higher fee when
+// those could make it succeed
+func handleSubmitResult(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, approved int64, cosigners []*keypair.Full, onSuccess func(hash string), onFailure func(err error), hash string, err error) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if err == nil {
+		onSuccess(hash)
+		return
+	}
+	if onFailure != nil {
+		onFailure(err)
+	}
+
+	retry, newFee := feeRetryAdvice(err, baseFee)
+	if !retry {
+		if isBadAuth(resultCodes(err)) {
+			showMoreSignersDialog(ops, memo, baseFee, cosigners, onSuccess)
+			return
+		}
+		if failures := operationFailures(resultCodes(err)); len(failures) > 0 {
+			showOperationFailures(ops, failures)
+			return
+		}
+		dialog.ShowError(errors.New(explainHorizonError(err)), window)
+		return
+	}
+	if fees, ok := currentFees(); ok && clampBaseFee(fees.P90) > newFee {
+		newFee = clampBaseFee(fees.P90)
+	}
+
+	message := fmt.Sprintf("The network rejected the transaction (%s).\nRetry with a base fee of %d stroops (%s XLM per operation)?",
+		transactionCode(resultCodes(err)), newFee, stroopsToXLM(newFee))
+	dialog.ShowConfirm("Retry With Higher Fee", message, func(ok bool) {
+		if ok {
+			submitApproved(ops, memo, newFee, approved, cosigners, onSuccess, onFailure)
+		}
+	}, window)
+}
+
+// Submit several transactions one after another, stopping at the first failure.
+// Safe mode weighs the batches together, so splitting a send can't slip under
+// the cap, and asks for the password at most once.
+//
+// When a batch fails after others went through, onPartial gets the hashes that
+// landed and the index of the failed batch, so the caller can say what was
+// done; nil shows just the hashes. A retry that succeeds carries on with the
+// remaining batches.
+func submitBatches(batches [][]txnbuild.Operation, memo txnbuild.Memo, onDone func(hashes []string), onPartial func(hashes []string, failed int)) {
+	var all []txnbuild.Operation
+	for _, batch := range batches {
+		all = append(all, batch...)
+	}
+
+	authorizeSpend(outgoingXLM(all, wallet.PublicKey, wallet.PublicKey), func(approved int64) {
+		var hashes []string
+		var next func(i int)
+		next = func(i int) {
+			if i == len(batches) {
+				onDone(hashes)
+				return
+			}
+			submitApproved(batches[i], memo, networkBaseFee(), approved, nil, func(hash string) {
+				hashes = append(hashes, hash)
+				next(i + 1)
+			}, func(error) {
+				if len(hashes) == 0 {
+					return
+				}
+				landed := append([]string(nil), hashes...)
+				if onPartial != nil {
+					onPartial(landed, i)
+				} else {
+					showPartialBatches(landed, len(batches), "")
+				}
+			})
+		}
+		next(0)
+	})
+}
+
+// Summary of a batched submission that stopped part way
+func partialBatchesText(hashes []string, total int, remaining string) string {
+	lines := []string{fmt.Sprintf("%d of %d transaction(s) went through before one failed:", len(hashes), total)}
+	lines = append(lines, hashes...)
+	if remaining != "" {
+		lines = append(lines, "", remaining)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Tell the user which batches landed before a failure, plus what the caller
+// says is left undone
+func showPartialBatches(hashes []string, total int, remaining string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	text := widget.NewMultiLineEntry()
+	text.SetText(partialBatchesText(hashes, total, remaining))
+	text.Wrapping = fyne.TextWrapWord
+	copyButton := widget.NewButton("Copy", func() {
+		window.Clipboard().SetContent(text.Text)
+	})
+	scroll := container.NewVScroll(text)
+	scroll.SetMinSize(fyne.NewSize(scaled(360), scaled(200)))
+	dialog.ShowCustom("Partly Submitted", "Close", container.NewBorder(nil, copyButton, nil, nil, scroll), window)
+}
+
+// List every operation of a failed transaction, marking the ones that failed
+func showOperationFailures(ops []txnbuild.Operation, failures []opFailure) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	failed := make(map[int]opFailure, len(failures))
+	for _, f := range failures {
+		failed[f.Index] = f
+	}
+
+	list := container.NewVBox(widget.NewLabel("The transaction failed; nothing was applied."))
+	for i, op := range ops {
+		label := widget.NewLabel(fmt.Sprintf("%d. %s", i+1, describeOperation(op)))
+		label.Wrapping = fyne.TextWrapWord
+		if f, ok := failed[i]; ok {
+			label.SetText(fmt.Sprintf("%d. %s\n   Failed: %s (%s)", i+1, describeOperation(op), f.Message, f.Code))
+			label.Importance = widget.DangerImportance
+		}
+		list.Add(label)
+	}
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(scaled(320), scaled(240)))
+	dialog.ShowCustom("Transaction Failed", "Close", scroll, window)
+}
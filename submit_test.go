@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestChunkOperations(t *testing.T) {
+	ops := func(n int) []txnbuild.Operation {
+		list := make([]txnbuild.Operation, n)
+		for i := range list {
+			list[i] = &txnbuild.BumpSequence{BumpTo: int64(i)}
+		}
+		return list
+	}
+
+	tests := []struct {
+		name     string
+		count    int
+		size     int
+		expected []int
+	}{
+		{"none", 0, 100, nil},
+		{"one", 1, 100, []int{1}},
+		{"exactly full", 100, 100, []int{100}},
+		{"one over", 101, 100, []int{100, 1}},
+		{"several", 250, 100, []int{100, 100, 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := ops(tt.count)
+			chunks := chunkOperations(input, tt.size)
+			if len(chunks) != len(tt.expected) {
+				t.Fatalf("got %d chunks, want %d", len(chunks), len(tt.expected))
+			}
+			next := 0
+			for i, chunk := range chunks {
+				if len(chunk) != tt.expected[i] {
+					t.Errorf("chunk %d has %d operations, want %d", i, len(chunk), tt.expected[i])
+				}
+				for _, op := range chunk {
+					if op != input[next] {
+						t.Fatalf("operation %d out of order", next)
+					}
+					next++
+				}
+			}
+		})
+	}
+}
+
+func TestFeeRetryAdvice(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		baseFee   int64
+		wantRetry bool
+		wantFee   int64
+	}{
+		{"insufficient fee doubles", submissionError("tx_insufficient_fee"), 500, true, 1000},
+		{"too late doubles", submissionError("tx_too_late"), 300, true, 600},
+		{"minimum fee raised to twice minimum", submissionError("tx_insufficient_fee"), txnbuild.MinBaseFee, true, txnbuild.MinBaseFee * 2},
+		{"capped at max", submissionError("tx_insufficient_fee"), maxBaseFee - 1, true, maxBaseFee},
+		{"already at max", submissionError("tx_insufficient_fee"), maxBaseFee, false, 0},
+		{"operation failure", submissionError("tx_failed", "op_underfunded"), 100, false, 0},
+		{"bad sequence", submissionError("tx_bad_seq"), 100, false, 0},
+		{"bad auth", submissionError("tx_bad_auth"), 100, false, 0},
+		{"not a submission error", errors.New("connection reset"), 100, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, fee := feeRetryAdvice(tt.err, tt.baseFee)
+			if retry != tt.wantRetry || fee != tt.wantFee {
+				t.Errorf("feeRetryAdvice = %v, %d, want %v, %d", retry, fee, tt.wantRetry, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestPartialBatchesText(t *testing.T) {
+	got := partialBatchesText([]string{"aaa", "bbb"}, 3, "1 balance(s) not claimed:\nccc")
+	want := "2 of 3 transaction(s) went through before one failed:\naaa\nbbb\n\n1 balance(s) not claimed:\nccc"
+	if got != want {
+		t.Errorf("text = %q\nwant %q", got, want)
+	}
+	if got := partialBatchesText([]string{"aaa"}, 2, ""); got != "1 of 2 transaction(s) went through before one failed:\naaa" {
+		t.Errorf("text without remainder = %q", got)
+	}
+}
+
+func TestSubmitOperationsWithFee(t *testing.T) {
+	kp := keypair.MustRandom()
+	savedWallet, savedClient, savedSettings := wallet, client, settings
+	t.Cleanup(func() {
+		wallet, client, settings = savedWallet, savedClient, savedSettings
+		accountRecords.clear()
+	})
+	settings = Settings{}
+
+	for _, network := range []string{"public", "testnet"} {
+		t.Run(network, func(t *testing.T) {
+			fake := horizontest.NewFakeHorizon(horizon.Account{AccountID: kp.Address(), Sequence: 7})
+			wallet = &Wallet{PublicKey: kp.Address(), SecretKey: kp.Seed(), Network: network}
+			client = fake
+			accountRecords.clear()
+
+			payment := &txnbuild.Payment{Destination: testOther, Amount: "1", Asset: txnbuild.NativeAsset{}}
+			if _, err := submitOperationsWithFee([]txnbuild.Operation{payment}, nil, 250, 0); err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.Submitted) != 1 {
+				t.Fatalf("submitted %d transactions, want 1", len(fake.Submitted))
+			}
+			tx := fake.Submitted[0]
+			if signed, ok := horizontest.SignedNetwork(tx); !ok || signed != network {
+				t.Errorf("signed for %q (%v), want %q", signed, ok, network)
+			}
+			if fee := tx.BaseFee(); fee != 250 {
+				t.Errorf("base fee = %d, want 250", fee)
+			}
+		})
+	}
+}
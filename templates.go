@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/txnbuild"
+)
+
+// A named, reusable payment to one or more recipients. Never holds secrets.
+type sendTemplate struct {
+	Name       string   `json:"name"`
+	Recipients []string `json:"recipients"`
+	Asset      string   `json:"asset"`
+	Amount     string   `json:"amount"` // paid to each recipient
+	Memo       string   `json:"memo,omitempty"`
+	MemoType   string   `json:"memo_type,omitempty"`
+}
+
+// Add a template, replacing any existing one with the same name
+func putTemplate(templates []sendTemplate, t sendTemplate) ([]sendTemplate, error) {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return templates, fmt.Errorf("template name is required")
+	}
+	if len(t.Recipients) == 0 {
+		return templates, fmt.Errorf("template needs at least one recipient")
+	}
+
+	updated := make([]sendTemplate, 0, len(templates)+1)
+	for _, existing := range templates {
+		if existing.Name != t.Name {
+			updated = append(updated, existing)
+		}
+	}
+	updated = append(updated, t)
+	sort.Slice(updated, func(i, j int) bool { return updated[i].Name < updated[j].Name })
+	return updated, nil
+}
+
+func findTemplate(templates []sendTemplate, name string) (sendTemplate, bool) {
+	for _, t := range templates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return sendTemplate{}, false
+}
+
+func deleteTemplate(templates []sendTemplate, name string) []sendTemplate {
+	var remaining []sendTemplate
+	for _, t := range templates {
+		if t.Name != name {
+			remaining = append(remaining, t)
+		}
+	}
+	return remaining
+}
+
+func templateNames(templates []sendTemplate) []string {
+	names := make([]string, len(templates))
+	for i, t := range templates {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Split a recipients field on commas, whitespace or newlines
+func parseRecipients(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	})
+}
+
+// One payment per recipient in the template
+func templatePayments(t sendTemplate) ([]txnbuild.Operation, error) {
+	asset, err := parseAsset(t.Asset)
+	if err != nil {
+		return nil, err
+	}
+	var ops []txnbuild.Operation
+	for _, recipient := range t.Recipients {
+		ops = append(ops, &txnbuild.Payment{Destination: recipient, Amount: t.Amount, Asset: asset})
+	}
+	return ops, nil
+}
+
+func storeTemplate(t sendTemplate) error {
+	templates, err := putTemplate(settings.Templates, t)
+	if err != nil {
+		return err
+	}
+	settings.Templates = templates
+	return saveSettings()
+}
+
+// Ask for a name and save the given send as a template
+func showSaveTemplateDialog(params sendParams) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. Rent")
+	items := []*widget.FormItem{widget.NewFormItem("Name", nameEntry)}
+
+	dialog.ShowForm("Save Template", "Save", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		t := sendTemplate{
+			Name:       nameEntry.Text,
+			Recipients: parseRecipients(params.Recipient),
+			Asset:      params.Asset,
+			Amount:     strings.TrimSpace(params.Amount),
+			Memo:       params.Memo,
+			MemoType:   params.MemoType,
+		}
+		if err := storeTemplate(t); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+}
+
+func showTemplatesDialog(balanceLabel *widget.Label) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	templateSelect := widget.NewSelect(templateNames(settings.Templates), nil)
+	nameEntry := widget.NewEntry()
+	recipientsEntry := widget.NewMultiLineEntry()
+	recipientsEntry.SetPlaceHolder("One recipient per line")
+	assetEntry := widget.NewEntry()
+	assetEntry.SetText("XLM")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Amount per recipient")
+	memoEntry := widget.NewEntry()
+
+	// Kept from the loaded template; the dialog only edits the memo value
+	memoType := ""
+	templateSelect.OnChanged = func(name string) {
+		t, ok := findTemplate(settings.Templates, name)
+		if !ok {
+			return
+		}
+		nameEntry.SetText(t.Name)
+		recipientsEntry.SetText(strings.Join(t.Recipients, "\n"))
+		assetEntry.SetText(t.Asset)
+		amountEntry.SetText(t.Amount)
+		memoEntry.SetText(t.Memo)
+		memoType = t.MemoType
+	}
+
+	current := func() sendTemplate {
+		return sendTemplate{
+			Name:       nameEntry.Text,
+			Recipients: parseRecipients(recipientsEntry.Text),
+			Asset:      strings.TrimSpace(assetEntry.Text),
+			Amount:     strings.TrimSpace(amountEntry.Text),
+			Memo:       memoEntry.Text,
+			MemoType:   memoType,
+		}
+	}
+
+	saveButton := widget.NewButton("Save", func() {
+		if err := storeTemplate(current()); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		templateSelect.Options = templateNames(settings.Templates)
+		templateSelect.SetSelected(strings.TrimSpace(nameEntry.Text))
+	})
+	deleteButton := widget.NewButton("Delete", func() {
+		name := templateSelect.Selected
+		if name == "" {
+			return
+		}
+		settings.Templates = deleteTemplate(settings.Templates, name)
+		if err := saveSettings(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving settings: %v", err), window)
+		}
+		templateSelect.Options = templateNames(settings.Templates)
+		templateSelect.ClearSelected()
+	})
+
+	var popup dialog.Dialog
+	useButton := widget.NewButton("Use", func() {
+		t := current()
+		if len(t.Recipients) == 0 {
+			dialog.ShowError(fmt.Errorf("template needs at least one recipient"), window)
+			return
+		}
+		popup.Hide()
+		useTemplate(t, balanceLabel)
+	})
+
+	content := container.NewVBox(
+		templateSelect,
+		widget.NewForm(
+			widget.NewFormItem("Name", nameEntry),
+			widget.NewFormItem("Recipients", recipientsEntry),
+			widget.NewFormItem("Asset", assetEntry),
+			widget.NewFormItem("Amount", amountEntry),
+			widget.NewFormItem("Memo", memoEntry),
+		),
+		container.NewGridWithColumns(3, saveButton, deleteButton, useButton),
+	)
+	popup = dialog.NewCustom("Templates", "Close", content, window)
+	popup.Show()
+}
+
+// A single payment opens the regular send dialog; anything else is sent as
+// one batch after confirmation
+func useTemplate(t sendTemplate, balanceLabel *widget.Label) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if len(t.Recipients) == 1 {
+		asset, err := parseAsset(t.Asset)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		showSendDialog(balanceLabel, sendParams{Recipient: t.Recipients[0], Amount: t.Amount, Asset: assetString(asset), Memo: t.Memo, MemoType: t.MemoType})
+		return
+	}
+
+	ops, err := templatePayments(t)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	memo, err := buildMemo(sendParams{Memo: t.Memo, MemoType: t.MemoType}.memo())
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	var lines []string
+	for _, op := range ops {
+		lines = append(lines, describeOperation(op))
+	}
+	message := fmt.Sprintf("Send %d payment(s) from template %s on %s?\n\n%s", len(ops), t.Name, wallet.Network, strings.Join(lines, "\n"))
+	confirmSendMessage(templateTotal(t), message, func() {
+		submitBatches(chunkOperations(ops, maxOpsPerTx), memo, func(hashes []string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Sent in %d transaction(s).", len(hashes)), window)
+			refreshBalanceAsync(balanceLabel)
+		}, nil)
+	})
+}
+
+// Total a template sends across all its recipients. One too large to add up
+// is reported as the largest possible amount, so it is still confirmed.
+func templateTotal(t sendTemplate) string {
+	each, err := amount.ParseInt64(strings.TrimSpace(t.Amount))
+	if err != nil || len(t.Recipients) == 0 {
+		return t.Amount
+	}
+	if each > math.MaxInt64/int64(len(t.Recipients)) {
+		return amount.StringFromInt64(math.MaxInt64)
+	}
+	return amount.StringFromInt64(each * int64(len(t.Recipients)))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTemplateStore(t *testing.T) {
+	rent := sendTemplate{Name: "rent", Recipients: []string{testOther}, Asset: "XLM", Amount: "100"}
+	team := sendTemplate{Name: "team", Recipients: []string{testOther, testWallet}, Asset: "XLM", Amount: "5"}
+
+	templates, err := putTemplate(nil, team)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if templates, err = putTemplate(templates, rent); err != nil {
+		t.Fatal(err)
+	}
+	if got := templateNames(templates); !reflect.DeepEqual(got, []string{"rent", "team"}) {
+		t.Errorf("names = %q, want sorted [rent team]", got)
+	}
+
+	// Saving under an existing name, even with stray spaces, replaces it
+	raised := rent
+	raised.Name, raised.Amount = "  rent ", "120"
+	if templates, err = putTemplate(templates, raised); err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("%d templates after replacing one, want 2", len(templates))
+	}
+	if got, ok := findTemplate(templates, "rent"); !ok || got.Amount != "120" {
+		t.Errorf("rent = %+v, %v, want the replacement", got, ok)
+	}
+	if _, ok := findTemplate(templates, "Rent"); ok {
+		t.Error("lookup is case sensitive, found Rent")
+	}
+
+	for _, tt := range []struct {
+		template sendTemplate
+		wantErr  string
+	}{
+		{sendTemplate{Name: " ", Recipients: []string{testOther}}, "name is required"},
+		{sendTemplate{Name: "empty"}, "at least one recipient"},
+	} {
+		kept, err := putTemplate(templates, tt.template)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+		}
+		if !reflect.DeepEqual(kept, templates) {
+			t.Error("rejected template changed the list")
+		}
+	}
+
+	if got := deleteTemplate(templates, "missing"); !reflect.DeepEqual(got, templates) {
+		t.Errorf("deleting a missing name gave %+v", got)
+	}
+	templates = deleteTemplate(templates, "rent")
+	if _, ok := findTemplate(templates, "rent"); ok || len(templates) != 1 {
+		t.Errorf("after delete: %+v", templates)
+	}
+	if got, ok := findTemplate(templates, "team"); !ok || !reflect.DeepEqual(got, team) {
+		t.Errorf("team = %+v, %v, want it untouched", got, ok)
+	}
+}
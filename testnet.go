@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+)
+
+// How long to wait for a Friendbot-funded account to show up on Horizon
+const (
+	fundedPollInterval = time.Second
+	fundedPollAttempts = 15
+)
+
+// Wait until address exists on the network client talks to
+func waitForAccount(address string) error {
+	var err error
+	for i := 0; i < fundedPollAttempts; i++ {
+		if _, err = client.AccountDetail(horizonclient.AccountRequest{AccountID: address}); err == nil {
+			return nil
+		}
+		if !isNotFound(err) {
+			return err
+		}
+		time.Sleep(fundedPollInterval)
+	}
+	return fmt.Errorf("account %s was not found on the network after funding", shortAddress(address))
+}
+
+// Start over on testnet with a fresh funded account. The current account
+// stays in the wallet, so nothing it holds is lost.
+func showNewTestnetAccountDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if wallet.Network != "testnet" {
+		dialog.ShowInformation("New Testnet Account", "This is only available on testnet.", window)
+		return
+	}
+	message := fmt.Sprintf("Create a new account funded by Friendbot and switch to it?\n\n%s stays in this wallet.", shortAddress(wallet.PublicKey))
+	dialog.ShowConfirm("New Testnet Account", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		kp, err := keypair.Random()
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		withProgress("Funding new account with Friendbot...", func() {
+			if err = fundAccount(kp.Address()); err != nil {
+				err = fmt.Errorf("error funding account: %v", err)
+				return
+			}
+			err = waitForAccount(kp.Address())
+		}, func() {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if err := addWalletAccount(kp, nil); err != nil {
+				dialog.ShowError(fmt.Errorf("error adding account: %v", err), window)
+				return
+			}
+			window.SetContent(createMainUI())
+			dialog.ShowInformation("New Testnet Account", fmt.Sprintf("Switched to %s.", kp.Address()), window)
+		})
+	}, window)
+}
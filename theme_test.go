@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+func TestWalletThemeSize(t *testing.T) {
+	base := theme.DefaultTheme()
+	tests := []struct {
+		name  string
+		scale float32
+		want  float32
+	}{
+		{"unset", 0, 1},
+		{"negative", -2, 1},
+		{"normal", 1, 1},
+		{"larger", 1.5, 1.5},
+		{"too small", 0.1, minFontScale},
+		{"too large", 10, maxFontScale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := newWalletTheme("system", false, tt.scale)
+			for _, name := range []fyne.ThemeSizeName{theme.SizeNameText, theme.SizeNameHeadingText, theme.SizeNameInlineIcon} {
+				if got, want := th.Size(name), base.Size(name)*tt.want; got != want {
+					t.Errorf("Size(%s) = %v, want %v", name, got, want)
+				}
+			}
+			if got, want := th.Size(theme.SizeNamePadding), base.Size(theme.SizeNamePadding); got != want {
+				t.Errorf("padding = %v, want it unscaled at %v", got, want)
+			}
+		})
+	}
+}
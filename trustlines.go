@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Whether an account can receive an asset through its trustline
+type trustlineState int
+
+const (
+	trustlineMissing trustlineState = iota
+	trustlineUnauthorized
+	trustlineMaintainLiabilities
+	trustlineAuthorized
+)
+
+// Authorization state of the trustline for asset among an account's balances
+func trustlineAuthorization(balances []horizon.Balance, asset txnbuild.Asset) trustlineState {
+	if asset.IsNative() {
+		return trustlineAuthorized
+	}
+
+	for _, balance := range balances {
+		if !balanceMatches(balance, asset) {
+			continue
+		}
+		switch {
+		case balance.IsAuthorized == nil || *balance.IsAuthorized:
+			return trustlineAuthorized
+		case balance.IsAuthorizedToMaintainLiabilities != nil && *balance.IsAuthorizedToMaintainLiabilities:
+			return trustlineMaintainLiabilities
+		default:
+			return trustlineUnauthorized
+		}
+	}
+	return trustlineMissing
+}
+
+// Check that destination can receive asset before a payment is attempted
+func checkDestinationTrustline(destination string, asset txnbuild.Asset) error {
+	if asset.IsNative() {
+		return nil
+	}
+
+	account, err := accountRecords.fetch(client, wallet.Network, destination)
+	if err != nil {
+		return fmt.Errorf("destination account does not exist: %v", err)
+	}
+	return trustlineError(account, asset)
+}
+
+// Why account can't receive asset, or nil when it can
+func trustlineError(account horizon.Account, asset txnbuild.Asset) error {
+	switch trustlineAuthorization(account.Balances, asset) {
+	case trustlineMissing:
+		return fmt.Errorf("recipient has no trustline for %s; they must add one before they can receive it", assetCode(asset))
+	case trustlineUnauthorized, trustlineMaintainLiabilities:
+		return fmt.Errorf("recipient's %s trustline is not authorized; the issuer %s must authorize the recipient first",
+			assetCode(asset), asset.GetIssuer())
+	}
+	return nil
+}
+
+// Operation adding (or changing the limit of) a trustline; an empty limit means the maximum
+func addTrustlineOp(asset txnbuild.CreditAsset, limit string) *txnbuild.ChangeTrust {
+	return &txnbuild.ChangeTrust{
+		Line:  asset.MustToChangeTrustAsset(),
+		Limit: limit,
+	}
+}
+
+func showAddAssetDialog(balanceLabel *widget.Label) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	codeEntry := widget.NewEntry()
+	codeEntry.SetPlaceHolder("e.g. USDC")
+	issuerEntry := widget.NewEntry()
+	issuerEntry.SetPlaceHolder("Issuer address (G...)")
+	limitEntry := widget.NewEntry()
+	limitEntry.SetPlaceHolder("Limit (optional)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Code", codeEntry),
+		widget.NewFormItem("Issuer", issuerEntry),
+		widget.NewFormItem("Limit", limitEntry),
+	}
+
+	dialog.ShowForm("Add Asset", "Add", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+
+		parsed, err := parseAsset(strings.TrimSpace(codeEntry.Text) + ":" + strings.TrimSpace(issuerEntry.Text))
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		asset, ok := parsed.(txnbuild.CreditAsset)
+		if !ok {
+			dialog.ShowError(fmt.Errorf("XLM needs no trustline"), window)
+			return
+		}
+		limit := strings.TrimSpace(limitEntry.Text)
+		if limit != "" {
+			if _, err := amount.ParseInt64(limit); err != nil {
+				dialog.ShowError(fmt.Errorf("invalid limit: %v", err), window)
+				return
+			}
+		}
+
+		if _, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: asset.Issuer}); err != nil {
+			if isNotFound(err) {
+				dialog.ShowError(fmt.Errorf("issuer account %s does not exist on %s", asset.Issuer, wallet.Network), window)
+			} else {
+				dialog.ShowError(fmt.Errorf("error checking issuer: %v", err), window)
+			}
+			return
+		}
+
+		submitWithFeedback([]txnbuild.Operation{addTrustlineOp(asset, limit)}, nil, func(hash string) {
+			dialog.ShowInformation("Success", fmt.Sprintf("Trustline for %s added! Hash: %s", asset.Code, hash), window)
+			refreshBalanceAsync(balanceLabel)
+		})
+	}, window)
+}
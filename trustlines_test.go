@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestTrustlineAuthorization(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+	yes, no := true, false
+	line := func(authorized, maintain *bool) horizon.Balance {
+		return horizon.Balance{
+			Balance:                           "1.0000000",
+			IsAuthorized:                      authorized,
+			IsAuthorizedToMaintainLiabilities: maintain,
+			Asset:                             base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: testOther},
+		}
+	}
+	native := horizon.Balance{Balance: "10.0000000", Asset: base.Asset{Type: "native"}}
+
+	tests := []struct {
+		name     string
+		balances []horizon.Balance
+		asset    txnbuild.Asset
+		want     trustlineState
+	}{
+		{"native", nil, txnbuild.NativeAsset{}, trustlineAuthorized},
+		{"missing", []horizon.Balance{native}, usd, trustlineMissing},
+		{"other issuer", []horizon.Balance{line(&yes, nil)}, txnbuild.CreditAsset{Code: "USD", Issuer: testWallet}, trustlineMissing},
+		{"authorized", []horizon.Balance{native, line(&yes, nil)}, usd, trustlineAuthorized},
+		{"no flag", []horizon.Balance{line(nil, nil)}, usd, trustlineAuthorized},
+		{"unauthorized", []horizon.Balance{line(&no, nil)}, usd, trustlineUnauthorized},
+		{"unauthorized without liabilities", []horizon.Balance{line(&no, &no)}, usd, trustlineUnauthorized},
+		{"maintain liabilities", []horizon.Balance{line(&no, &yes)}, usd, trustlineMaintainLiabilities},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trustlineAuthorization(tt.balances, tt.asset); got != tt.want {
+				t.Errorf("trustlineAuthorization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddTrustlineOp(t *testing.T) {
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: testOther}
+	// An empty limit is left for txnbuild to fill in as the maximum
+	for _, limit := range []string{"", "100"} {
+		op := addTrustlineOp(usd, limit)
+		if op.Limit != limit {
+			t.Errorf("addTrustlineOp(%q).Limit = %q", limit, op.Limit)
+		}
+		line, ok := op.Line.(txnbuild.ChangeTrustAssetWrapper)
+		if !ok || line.Asset != usd {
+			t.Errorf("addTrustlineOp(%q).Line = %#v, want %v", limit, op.Line, usd)
+		}
+	}
+}
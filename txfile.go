@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Parse a transaction envelope from file contents, which may be base64 text
+// or the raw binary XDR
+func parseTransactionFile(data []byte) (*txnbuild.GenericTransaction, error) {
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	if tx, err := txnbuild.TransactionFromXDR(text); err == nil {
+		return tx, nil
+	}
+
+	tx, err := txnbuild.TransactionFromXDR(base64.StdEncoding.EncodeToString(data))
+	if err != nil {
+		return nil, fmt.Errorf("file does not contain a valid transaction envelope: %v", err)
+	}
+	return tx, nil
+}
+
+func openTransactionFile() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error reading file: %v", err), window)
+			return
+		}
+
+		tx, err := parseTransactionFile(data)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		showTransactionReview(tx, "")
+	}, window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".xdr", ".txt"}))
+	open.Show()
+}
+
+// Show a loaded transaction and let the user sign it with the wallet key and
+// submit it, or for a SEP-7 link with a callback, post it there instead
+func showTransactionReview(gtx *txnbuild.GenericTransaction, callback string) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	var summary string
+	if feeBump, ok := gtx.FeeBump(); ok {
+		inner := feeBump.InnerTransaction()
+		summary = fmt.Sprintf("Fee bump paid by %s\n\nInner transaction:\n%s",
+			feeBump.FeeAccount(), transactionSummary(inner))
+		if estimate, err := estimateTransactionCost(len(inner.Operations()), inner.BaseFee(), true, feeBump.BaseFee()); err == nil {
+			summary += "\n\nCost:\n" + costEstimateText(estimate)
+		}
+	} else if tx, ok := gtx.Transaction(); ok {
+		summary = transactionSummary(tx)
+		if estimate, err := estimateTransactionCost(len(tx.Operations()), tx.BaseFee(), false, 0); err == nil {
+			summary += "\n\nCost:\n" + costEstimateText(estimate)
+		}
+	}
+
+	summaryGrid := widget.NewTextGrid()
+
+	shareButton := widget.NewButton("Share as Link", func() {
+		envelope, err := gtx.MarshalText()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error encoding transaction: %v", err), window)
+			return
+		}
+		showShareTransactionDialog(string(envelope))
+	})
+
+	confirmText := "Sign & Submit"
+	if callback != "" {
+		summary += "\n\nOnce signed, this transaction is sent to " + callback + " instead of the network."
+		confirmText = "Sign & Send"
+	}
+	summaryGrid.SetText(summary)
+
+	dialog.ShowCustomConfirm("Review Transaction", confirmText, "Cancel",
+		container.NewBorder(nil, shareButton, nil, nil, container.NewScroll(summaryGrid)), func(submit bool) {
+			if !submit {
+				return
+			}
+
+			authorizeSpend(envelopeOutgoingXLM(gtx, wallet.PublicKey), func(approved int64) {
+				var (
+					hash string
+					err  error
+				)
+				withProgress("Submitting transaction...", func() {
+					hash, err = signAndDeliver(client, http.DefaultClient, gtx, approved, callback)
+				}, func() {
+					if err != nil {
+						dialog.ShowError(errors.New(explainHorizonError(err)), window)
+						return
+					}
+					if callback != "" {
+						dialog.ShowInformation("Success", "Signed transaction sent to "+callback, window)
+						return
+					}
+					dialog.ShowInformation("Success", fmt.Sprintf("Transaction successful! Hash: %s", hash), window)
+				})
+			})
+		}, window)
+}
+
+// Stroops of XLM an imported envelope moves out of account, looking inside fee bumps
+func envelopeOutgoingXLM(gtx *txnbuild.GenericTransaction, account string) int64 {
+	tx, ok := gtx.Transaction()
+	if feeBump, isFeeBump := gtx.FeeBump(); isFeeBump {
+		tx, ok = feeBump.InnerTransaction(), true
+	}
+	if !ok {
+		return 0
+	}
+	source := tx.SourceAccount()
+	return outgoingXLM(tx.Operations(), source.AccountID, account)
+}
+
+// Add the wallet signature where it's missing to either kind of envelope,
+// returning the signed transaction or fee bump. Imported envelopes bypass the
+// usual submit path, so safe mode is checked here too.
+func signGeneric(gtx *txnbuild.GenericTransaction, approved int64) (*txnbuild.Transaction, *txnbuild.FeeBumpTransaction, error) {
+	kp, err := keypair.ParseFull(wallet.SecretKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid wallet secret key: %v", err)
+	}
+	if safeModeHolds(envelopeOutgoingXLM(gtx, kp.Address()), approved) {
+		return nil, nil, errSafeModeHeld
+	}
+
+	if feeBump, ok := gtx.FeeBump(); ok {
+		if !signedBy(feeBump.Signatures(), kp) && feeBump.FeeAccount() == kp.Address() {
+			if feeBump, err = feeBump.Sign(currentPassphrase(), kp); err != nil {
+				return nil, nil, fmt.Errorf("error signing transaction: %v", err)
+			}
+		}
+		return nil, feeBump, nil
+	}
+
+	tx, ok := gtx.Transaction()
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported transaction envelope")
+	}
+	if !signedBy(tx.Signatures(), kp) {
+		if tx, err = tx.Sign(currentPassphrase(), kp); err != nil {
+			return nil, nil, fmt.Errorf("error signing transaction: %v", err)
+		}
+	}
+	return tx, nil, nil
+}
+
+// Sign either kind of envelope and submit it through hc, dropping cached
+// accounts once it lands
+func signAndSubmitGeneric(hc core.HorizonAPI, gtx *txnbuild.GenericTransaction, approved int64) (string, error) {
+	tx, feeBump, err := signGeneric(gtx, approved)
+	if err != nil {
+		return "", err
+	}
+
+	if feeBump != nil {
+		resp, err := hc.SubmitFeeBumpTransaction(feeBump)
+		if err != nil {
+			return "", fmt.Errorf("error submitting transaction: %w", err)
+		}
+		accountRecords.clear()
+		return resp.Hash, nil
+	}
+
+	resp, err := hc.SubmitTransaction(tx)
+	if err != nil {
+		return "", fmt.Errorf("error submitting transaction: %w", err)
+	}
+	accountRecords.clear()
+	return resp.Hash, nil
+}
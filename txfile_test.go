@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// An unsigned payment envelope from testWallet
+func testEnvelope(t *testing.T) *txnbuild.Transaction {
+	t.Helper()
+	source := txnbuild.NewSimpleAccount(testWallet, 41)
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &source,
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: testOther, Amount: "1", Asset: txnbuild.NativeAsset{}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestParseTransactionFile(t *testing.T) {
+	tx := testEnvelope(t)
+	envelope, err := tx.Base64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := tx.HashHex(network.TestNetworkPassphrase)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"base64", []byte(envelope), false},
+		{"base64 with whitespace", []byte("\n  " + envelope + " \r\n"), false},
+		{"raw xdr", raw, false},
+		{"empty", nil, true},
+		{"whitespace only", []byte(" \n\t"), true},
+		{"garbage text", []byte("not a transaction"), true},
+		{"truncated base64", []byte(envelope[:len(envelope)/2]), true},
+		{"garbage bytes", []byte{0xff, 0x00, 0x13, 0x37}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gtx, err := parseTransactionFile(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTransactionFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			parsed, ok := gtx.Transaction()
+			if !ok {
+				t.Fatal("parsed envelope is not a plain transaction")
+			}
+			if got, _ := parsed.HashHex(network.TestNetworkPassphrase); got != want {
+				t.Errorf("parsed hash = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestSignAndSubmitGenericClearsCachedAccounts(t *testing.T) {
+	kp := keypair.MustRandom()
+	savedWallet := wallet
+	wallet = &Wallet{PublicKey: kp.Address(), SecretKey: kp.Seed(), Network: "testnet"}
+	t.Cleanup(func() {
+		wallet = savedWallet
+		accountRecords.clear()
+	})
+
+	source := txnbuild.NewSimpleAccount(kp.Address(), 1)
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &source,
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 5}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := tx.Sign(network.TestNetworkPassphrase, kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeBump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner: signed, FeeAccount: kp.Address(), BaseFee: txnbuild.MinBaseFee * 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := func(t *testing.T, v interface{ Base64() (string, error) }) *txnbuild.GenericTransaction {
+		t.Helper()
+		b64, err := v.Base64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		gtx, err := parseTransactionFile([]byte(b64))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gtx
+	}
+
+	tests := []struct {
+		name      string
+		gtx       *txnbuild.GenericTransaction
+		submitErr error
+		cleared   bool
+	}{
+		{"plain transaction", envelope(t, tx), nil, true},
+		{"fee bump", envelope(t, feeBump), nil, true},
+		{"rejected", envelope(t, tx), errors.New("tx_bad_seq"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountRecords.put("testnet", testOther, horizon.Account{AccountID: testOther})
+			fake := horizontest.NewFakeHorizon()
+			fake.SubmitErr = tt.submitErr
+
+			hash, err := signAndSubmitGeneric(fake, tt.gtx, 0)
+			if tt.submitErr != nil {
+				if !errors.Is(err, tt.submitErr) {
+					t.Fatalf("error = %v, want the submission error", err)
+				}
+			} else if err != nil || hash == "" {
+				t.Fatalf("signAndSubmitGeneric = %q, %v", hash, err)
+			}
+			if len(fake.Submitted)+len(fake.SubmittedFeeBumps) != 1 {
+				t.Fatalf("submitted %d plain and %d fee bump transactions, want one", len(fake.Submitted), len(fake.SubmittedFeeBumps))
+			}
+			if len(fake.Submitted) == 1 {
+				if sigs := fake.Submitted[0].Signatures(); len(sigs) != 1 {
+					t.Errorf("%d signatures, want the wallet's once", len(sigs))
+				}
+			}
+			if _, cached := accountRecords.get("testnet", testOther); cached == tt.cleared {
+				t.Errorf("account still cached = %v, want %v", cached, !tt.cleared)
+			}
+		})
+	}
+}
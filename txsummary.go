@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// One-line human readable description of an operation
+func describeOperation(op txnbuild.Operation) string {
+	switch o := op.(type) {
+	case *txnbuild.Payment:
+		return fmt.Sprintf("Pay %s %s to %s", o.Amount, assetCode(o.Asset), o.Destination)
+	case *txnbuild.CreateAccount:
+		return fmt.Sprintf("Create account %s with %s XLM", o.Destination, o.Amount)
+	case *txnbuild.PathPaymentStrictSend:
+		return fmt.Sprintf("Path pay %s %s to %s for at least %s %s",
+			o.SendAmount, assetCode(o.SendAsset), o.Destination, o.DestMin, assetCode(o.DestAsset))
+	case *txnbuild.PathPaymentStrictReceive:
+		return fmt.Sprintf("Path pay at most %s %s to %s for %s %s",
+			o.SendMax, assetCode(o.SendAsset), o.Destination, o.DestAmount, assetCode(o.DestAsset))
+	case *txnbuild.ChangeTrust:
+		if o.Limit == "0" {
+			return fmt.Sprintf("Remove trustline for %s", assetCode(o.Line))
+		}
+		return fmt.Sprintf("Trust %s %s", assetCode(o.Line), o.Line.GetIssuer())
+	case *txnbuild.ManageSellOffer:
+		return fmt.Sprintf("Sell offer %d: %s %s for %s at %s",
+			o.OfferID, o.Amount, assetCode(o.Selling), assetCode(o.Buying), o.Price.String())
+	case *txnbuild.ManageBuyOffer:
+		return fmt.Sprintf("Buy offer %d: %s %s for %s at %s",
+			o.OfferID, o.Amount, assetCode(o.Buying), assetCode(o.Selling), o.Price.String())
+	case *txnbuild.SetOptions:
+		return "Set account options"
+	case *txnbuild.AccountMerge:
+		return fmt.Sprintf("Merge account into %s", o.Destination)
+	case *txnbuild.ManageData:
+		if o.Value == nil {
+			return fmt.Sprintf("Remove data entry %q", o.Name)
+		}
+		return fmt.Sprintf("Set data entry %q", o.Name)
+	case *txnbuild.BumpSequence:
+		return fmt.Sprintf("Bump sequence to %d", o.BumpTo)
+	case *txnbuild.CreateClaimableBalance:
+		return fmt.Sprintf("Create claimable balance of %s %s for %d claimant(s)",
+			o.Amount, assetCode(o.Asset), len(o.Destinations))
+	case *txnbuild.ClaimClaimableBalance:
+		return fmt.Sprintf("Claim balance %s", o.BalanceID)
+	case *txnbuild.BeginSponsoringFutureReserves:
+		return fmt.Sprintf("Sponsor reserves for %s", o.SponsoredID)
+	case *txnbuild.EndSponsoringFutureReserves:
+		return "End sponsorship"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", op), "*txnbuild.")
+}
+
+// Describe a transaction memo for display
+func describeMemo(memo txnbuild.Memo) string {
+	switch m := memo.(type) {
+	case nil:
+		return "none"
+	case txnbuild.MemoText:
+		return fmt.Sprintf("text %q", string(m))
+	case txnbuild.MemoID:
+		return fmt.Sprintf("id %d", uint64(m))
+	case txnbuild.MemoHash:
+		return fmt.Sprintf("hash %x", m[:])
+	case txnbuild.MemoReturn:
+		return fmt.Sprintf("return %x", m[:])
+	}
+	return "unknown"
+}
+
+// Multi-line summary of a transaction for review before signing
+func transactionSummary(tx *txnbuild.Transaction) string {
+	source := tx.SourceAccount()
+	lines := []string{
+		fmt.Sprintf("Source: %s", source.AccountID),
+		fmt.Sprintf("Sequence: %d", tx.SequenceNumber()),
+		fmt.Sprintf("Max fee: %s XLM", stroopsToXLM(tx.MaxFee())),
+		fmt.Sprintf("Memo: %s", describeMemo(tx.Memo())),
+		fmt.Sprintf("Signatures: %d", len(tx.Signatures())),
+		"Operations:",
+	}
+	for i, op := range tx.Operations() {
+		line := fmt.Sprintf("  %d. %s", i+1, describeOperation(op))
+		if src := op.GetSourceAccount(); src != "" && src != source.AccountID {
+			line += fmt.Sprintf(" (source %s)", src)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Whether signatures already contain one from kp
+func signedBy(signatures []xdr.DecoratedSignature, kp keypair.KP) bool {
+	hint := kp.Hint()
+	for _, sig := range signatures {
+		if sig.Hint == xdr.SignatureHint(hint) {
+			return true
+		}
+	}
+	return false
+}
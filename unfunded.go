@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Called with whether the wallet account exists on the network each time a
+// balance refresh finds out. Set by the main window.
+var fundedStateChanged func(funded bool)
+
+func setFundedState(funded bool) {
+	if fundedStateChanged != nil {
+		fundedStateChanged(funded)
+	}
+}
+
+// Shown in place of the balance while the account doesn't exist yet: where to
+// send the minimum balance, and on testnet a button to get it from Friendbot
+func unfundedPanel(balanceLabel *widget.Label) (fyne.CanvasObject, *widget.Button) {
+	address := wallet.PublicKey
+
+	info := widget.NewLabel(fmt.Sprintf("This account isn't funded yet. Send it at least %s XLM to create it on the network.",
+		stroopsToXLM(2*baseReserve)))
+	info.Wrapping = fyne.TextWrapWord
+	info.Importance = widget.WarningImportance
+	addressLabel := widget.NewLabel(address)
+	addressLabel.Wrapping = fyne.TextWrapBreak
+
+	friendbotButton := widget.NewButton("Fund with Friendbot", nil)
+	friendbotButton.OnTapped = func() {
+		window := fyne.CurrentApp().Driver().AllWindows()[0]
+		friendbotButton.Disable()
+		go func() {
+			defer friendbotButton.Enable()
+			if err := fundAccount(address); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			refreshBalanceAsync(balanceLabel)
+		}()
+	}
+
+	content := container.NewVBox(info, addressLabel, container.NewHBox(
+		widget.NewButton("Copy Address", func() {
+			window := fyne.CurrentApp().Driver().AllWindows()[0]
+			window.Clipboard().SetContent(address)
+		}),
+		friendbotButton,
+	))
+	if img, err := qrImage(address); err == nil {
+		content.Add(img)
+	}
+	return content, friendbotButton
+}
@@ -0,0 +1,158 @@
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// How long a new transaction stays valid when Client.TimeBounds isn't set
+const defaultTimeout = 300
+
+// A Horizon client bound to one network. Horizon is usually a
+// *horizonclient.Client, or a horizontest.FakeHorizon in tests.
+type Client struct {
+	Horizon HorizonAPI
+	Network string
+
+	// Validity window for new transactions; five minutes from now when nil
+	TimeBounds func() txnbuild.TimeBounds
+}
+
+func NewClient(hc HorizonAPI, network string) *Client {
+	return &Client{Horizon: hc, Network: network}
+}
+
+func (c *Client) Passphrase() string {
+	return Passphrase(c.Network)
+}
+
+func (c *Client) Account(accountID string) (horizon.Account, error) {
+	return c.Horizon.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+}
+
+// Balances of an account keyed by "XLM" or "CODE:ISSUER". Liquidity pool
+// shares aren't sendable assets and are left out.
+func Balances(account horizon.Account) map[string]string {
+	balances := make(map[string]string, len(account.Balances))
+	for _, balance := range account.Balances {
+		switch balance.Asset.Type {
+		case "liquidity_pool_shares":
+		case "native":
+			balances["XLM"] = balance.Balance
+		default:
+			balances[balance.Asset.Code+":"+balance.Asset.Issuer] = balance.Balance
+		}
+	}
+	return balances
+}
+
+// Build an unsigned transaction from sourceID's account at its next sequence
+// number. The account is always fetched fresh for the current sequence.
+func (c *Client) Build(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, sourceID string) (*txnbuild.Transaction, error) {
+	sourceAccount, err := c.Account(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("source account does not exist: %v", err)
+	}
+
+	timeBounds := txnbuild.NewTimeout(defaultTimeout)
+	if c.TimeBounds != nil {
+		timeBounds = c.TimeBounds()
+	}
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			BaseFee:              baseFee,
+			Preconditions: txnbuild.Preconditions{
+				TimeBounds: timeBounds,
+			},
+			Operations: ops,
+			Memo:       memo,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error building transaction: %v", err)
+	}
+	return tx, nil
+}
+
+func (c *Client) Sign(tx *txnbuild.Transaction, signers ...*keypair.Full) (*txnbuild.Transaction, error) {
+	tx, err := tx.Sign(c.Passphrase(), signers...)
+	if err != nil {
+		return nil, fmt.Errorf("error signing transaction: %v", err)
+	}
+	return tx, nil
+}
+
+// Submit a signed transaction, returning its hash. Horizon's error is
+// wrapped, so its result codes can still be read from the returned error.
+func (c *Client) SubmitTransaction(tx *txnbuild.Transaction) (string, error) {
+	resp, err := c.Horizon.SubmitTransaction(tx)
+	if err != nil {
+		return "", fmt.Errorf("error submitting transaction: %w", err)
+	}
+	return resp.Hash, nil
+}
+
+// Build, sign and submit a transaction of ops from kp's account
+func (c *Client) Submit(ops []txnbuild.Operation, memo txnbuild.Memo, baseFee int64, kp *keypair.Full, cosigners ...*keypair.Full) (string, error) {
+	tx, err := c.Build(ops, memo, baseFee, kp.Address())
+	if err != nil {
+		return "", err
+	}
+	tx, err = c.Sign(tx, append([]*keypair.Full{kp}, cosigners...)...)
+	if err != nil {
+		return "", err
+	}
+	return c.SubmitTransaction(tx)
+}
+
+// Account a G... or M... destination pays into
+func DestinationAccount(destination string) (string, error) {
+	destination = strings.TrimSpace(destination)
+	if strkey.IsValidEd25519PublicKey(destination) {
+		return destination, nil
+	}
+	muxed, err := xdr.AddressToMuxedAccount(destination)
+	if err != nil {
+		return "", fmt.Errorf("invalid destination %q", destination)
+	}
+	accountID := muxed.ToAccountId()
+	return accountID.Address(), nil
+}
+
+// Payment operation for amount of asset to destination, checked before any
+// network call
+func PaymentOp(destination, amount string, asset txnbuild.Asset) (*txnbuild.Payment, error) {
+	if _, err := DestinationAccount(destination); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(amount) == "" {
+		return nil, fmt.Errorf("amount is required")
+	}
+	return &txnbuild.Payment{Destination: destination, Amount: amount, Asset: asset}, nil
+}
+
+// Pay destination from kp's account, after making sure the destination
+// account exists and isn't kp's own
+func (c *Client) SendPayment(kp *keypair.Full, destination, amount string, asset txnbuild.Asset, memo txnbuild.Memo, baseFee int64) (string, error) {
+	payment, err := PaymentOp(destination, amount, asset)
+	if err != nil {
+		return "", err
+	}
+	accountID, _ := DestinationAccount(destination)
+	if accountID == kp.Address() {
+		return "", fmt.Errorf("cannot send to your own address")
+	}
+	if _, err := c.Account(accountID); err != nil {
+		return "", fmt.Errorf("destination account does not exist: %v", err)
+	}
+	return c.Submit([]txnbuild.Operation{payment}, memo, baseFee, kp)
+}
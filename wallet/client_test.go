@@ -0,0 +1,85 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/just-nibble/fyne-test/internal/horizontest"
+	"github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestSendPaymentSignsForNetworkWithBaseFee(t *testing.T) {
+	source := keypair.MustRandom()
+	destination := keypair.MustRandom()
+
+	tests := []struct {
+		network string
+		baseFee int64
+	}{
+		{"public", 500},
+		{"testnet", txnbuild.MinBaseFee},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.network, func(t *testing.T) {
+			fake := horizontest.NewFakeHorizon(
+				horizon.Account{AccountID: source.Address(), Sequence: 100},
+				horizon.Account{AccountID: destination.Address(), Sequence: 1},
+			)
+			c := wallet.NewClient(fake, tt.network)
+
+			hash, err := c.SendPayment(source, destination.Address(), "10", txnbuild.NativeAsset{}, nil, tt.baseFee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.Submitted) != 1 {
+				t.Fatalf("submitted %d transactions, want 1", len(fake.Submitted))
+			}
+			tx := fake.Submitted[0]
+
+			if network, ok := horizontest.SignedNetwork(tx); !ok || network != tt.network {
+				t.Errorf("signed for %q (%v), want %q", network, ok, tt.network)
+			}
+			if tx.BaseFee() != tt.baseFee {
+				t.Errorf("base fee = %d, want %d", tx.BaseFee(), tt.baseFee)
+			}
+			if want, _ := tx.HashHex(wallet.Passphrase(tt.network)); hash != want {
+				t.Errorf("hash = %s, want %s", hash, want)
+			}
+			if tx.SequenceNumber() != 101 {
+				t.Errorf("sequence = %d, want 101", tx.SequenceNumber())
+			}
+		})
+	}
+}
+
+func TestSendPaymentRejectsBeforeSubmitting(t *testing.T) {
+	source := keypair.MustRandom()
+	missing := keypair.MustRandom()
+
+	tests := []struct {
+		name        string
+		destination string
+		amount      string
+	}{
+		{"own account", source.Address(), "10"},
+		{"missing destination", missing.Address(), "10"},
+		{"invalid destination", "GNOTANADDRESS", "10"},
+		{"no amount", missing.Address(), " "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := horizontest.NewFakeHorizon(horizon.Account{AccountID: source.Address(), Sequence: 1})
+			c := wallet.NewClient(fake, "testnet")
+			if _, err := c.SendPayment(source, tt.destination, tt.amount, txnbuild.NativeAsset{}, nil, txnbuild.MinBaseFee); err == nil {
+				t.Error("expected an error")
+			}
+			if len(fake.Submitted) != 0 {
+				t.Errorf("submitted %d transactions", len(fake.Submitted))
+			}
+		})
+	}
+}
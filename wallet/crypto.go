@@ -0,0 +1,74 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt cost parameters for deriving the wallet key from its password
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	KeyLen  = 32 // AES-256
+)
+
+var ErrWrongPassword = errors.New("wrong password")
+
+// Key for encrypting secrets, derived from the wallet password and salt
+func DeriveKey(password string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, KeyLen)
+}
+
+// Encrypt secret with AES-GCM under key, returning base64 ciphertext and nonce
+func Seal(key []byte, secret string) (string, string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", err
+	}
+	ciphertext := gcm.Seal(nil, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), base64.StdEncoding.EncodeToString(nonce), nil
+}
+
+// Decrypt what Seal produced
+func Open(key []byte, ciphertext, nonce string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("corrupt wallet file: %v", err)
+	}
+	nonceBytes, err := base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return "", fmt.Errorf("corrupt wallet file: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(nonceBytes) != gcm.NonceSize() {
+		return "", fmt.Errorf("corrupt wallet file: bad nonce")
+	}
+	plain, err := gcm.Open(nil, nonceBytes, sealed, nil)
+	if err != nil {
+		// GCM can't tell a wrong key from tampering; the key is by far the likelier cause
+		return "", ErrWrongPassword
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,33 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/txnbuild"
+)
+
+// The Horizon requests the wallet makes. *horizonclient.Client satisfies it;
+// horizontest.FakeHorizon stands in for it without a network.
+type HorizonAPI interface {
+	AccountDetail(request horizonclient.AccountRequest) (horizon.Account, error)
+	SubmitTransaction(transaction *txnbuild.Transaction) (horizon.Transaction, error)
+	SubmitFeeBumpTransaction(transaction *txnbuild.FeeBumpTransaction) (horizon.Transaction, error)
+	Transactions(request horizonclient.TransactionRequest) (horizon.TransactionsPage, error)
+	Payments(request horizonclient.OperationRequest) (operations.OperationsPage, error)
+	FeeStats() (horizon.FeeStats, error)
+	Root() (horizon.Root, error)
+
+	TransactionDetail(txHash string) (horizon.Transaction, error)
+	Operations(request horizonclient.OperationRequest) (operations.OperationsPage, error)
+	Offers(request horizonclient.OfferRequest) (horizon.OffersPage, error)
+	OrderBook(request horizonclient.OrderBookRequest) (horizon.OrderBookSummary, error)
+	StrictSendPaths(request horizonclient.StrictSendPathsRequest) (horizon.PathsPage, error)
+	ClaimableBalances(request horizonclient.ClaimableBalanceRequest) (horizon.ClaimableBalances, error)
+	ClaimableBalance(id string) (horizon.ClaimableBalance, error)
+	StreamPayments(ctx context.Context, request horizonclient.OperationRequest, handler horizonclient.OperationHandler) error
+}
+
+var _ HorizonAPI = (*horizonclient.Client)(nil)
@@ -0,0 +1,99 @@
+// Package wallet is the Stellar side of the app without any UI: networks,
+// account lookups, building, signing and submitting transactions, and
+// encrypting the secrets kept in the wallet file. Everything returns values
+// and errors, so it can be driven from tests or a command line tool.
+package wallet
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/network"
+)
+
+// Whether name is a network the wallet can use: "public" or "testnet"
+func ValidNetwork(name string) bool {
+	return name == "public" || name == "testnet"
+}
+
+// How long a Horizon request, retries included, may take by default
+const DefaultRequestTimeout = 15 * time.Second
+
+var (
+	settingsMu     sync.Mutex
+	requestTimeout = DefaultRequestTimeout
+
+	// Requests from clients made here are cancelled when this is done
+	lifetime = context.Background()
+)
+
+// SDF's Horizon servers, retrying transient failures. Guarded by settingsMu
+// and replaced, never modified, when the timeout changes.
+var (
+	testnetClient = newHorizonClient(horizonclient.DefaultTestNetClient.HorizonURL, DefaultRequestTimeout)
+	publicClient  = newHorizonClient(horizonclient.DefaultPublicNetClient.HorizonURL, DefaultRequestTimeout)
+)
+
+// Horizon client for the server at url that retries transient failures and
+// gives up after the request timeout
+func NewHorizonClient(url string) *horizonclient.Client {
+	settingsMu.Lock()
+	timeout := requestTimeout
+	settingsMu.Unlock()
+	return newHorizonClient(url, timeout)
+}
+
+func newHorizonClient(url string, timeout time.Duration) *horizonclient.Client {
+	c := &horizonclient.Client{
+		HorizonURL: url,
+		HTTP:       &http.Client{Transport: NewRetryTransport(nil)},
+	}
+	return c.SetHorizonTimeout(timeout)
+}
+
+// Limit every Horizon request to timeout, for the default clients and any
+// made afterwards. The default clients are replaced, so requests already
+// running on the old ones keep their timeout. Streams aren't affected; they
+// run until cancelled.
+func SetRequestTimeout(timeout time.Duration) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	requestTimeout = timeout
+	testnetClient = newHorizonClient(horizonclient.DefaultTestNetClient.HorizonURL, timeout)
+	publicClient = newHorizonClient(horizonclient.DefaultPublicNetClient.HorizonURL, timeout)
+}
+
+// Cancel every request in flight, streams included, once ctx is done, such
+// as when the app is closing
+func SetContext(ctx context.Context) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	lifetime = ctx
+}
+
+func lifetimeContext() context.Context {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	return lifetime
+}
+
+// Default Horizon client for a network
+func HorizonClient(name string) *horizonclient.Client {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	if name == "testnet" {
+		return testnetClient
+	}
+	return publicClient
+}
+
+// Passphrase transactions on a network are signed with
+func Passphrase(name string) string {
+	if name == "testnet" {
+		return network.TestNetworkPassphrase
+	}
+	return network.PublicNetworkPassphrase
+}
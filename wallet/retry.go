@@ -0,0 +1,127 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry policy for Horizon requests
+const (
+	retryAttempts = 3 // tries after the first
+	retryBackoff  = 500 * time.Millisecond
+	retryMaxWait  = 10 * time.Second // longest Retry-After honoured
+)
+
+// An http.RoundTripper that retries requests Horizon failed transiently:
+// rate limits (429), server errors (500, 502, 503, 504) and timeouts. Waits
+// double each time, or follow Retry-After when Horizon sends one. A POST,
+// such as a transaction submission, is only resent after a 429: after a
+// server error or timeout it may already have reached the network.
+type RetryTransport struct {
+	Base     http.RoundTripper
+	Attempts int
+	Backoff  time.Duration
+	MaxWait  time.Duration
+}
+
+func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	return &RetryTransport{Base: base, Attempts: retryAttempts, Backoff: retryBackoff, MaxWait: retryMaxWait}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// Also end the request with the app. The client cancels the request's own
+	// context once the response is read, which releases this one too.
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(lifetimeContext(), cancel)
+	context.AfterFunc(ctx, func() { stop() })
+	req = req.WithContext(ctx)
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if attempt >= t.Attempts || !transient(req, resp, err) {
+			return resp, err
+		}
+		// A body that can't be replayed can't be retried
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := retryDelay(resp, attempt, t.Backoff, t.MaxWait)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// Whether req may succeed if sent again, and sending it again can't repeat
+// its effect. Horizon turns a request away with 429 before acting on it, so
+// that alone is retried for requests other than GET and HEAD.
+func transient(req *http.Request, resp *http.Response, err error) bool {
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !idempotent(req) {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func idempotent(req *http.Request) bool {
+	return req.Method == "" || req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
+// How long to wait before retry number attempt+1: Retry-After when the
+// response has one, capped at maxWait, otherwise exponential backoff
+func retryDelay(resp *http.Response, attempt int, backoff, maxWait time.Duration) time.Duration {
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(after, maxWait)
+		}
+	}
+	return min(backoff<<attempt, maxWait)
+}
+
+// Retry-After as a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,131 @@
+package wallet
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Wed, 01 May 2024 12:00:05 GMT", 5 * time.Second, true},
+		{"Wed, 01 May 2024 11:59:00 GMT", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+// A RoundTripper answering every request with status, counting the requests
+type countingTransport struct {
+	status   int
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return &http.Response{StatusCode: c.status, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+}
+
+func TestRetryTransportOnlyRetriesSafeRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		expected int
+	}{
+		{"get server error", http.MethodGet, http.StatusServiceUnavailable, 3},
+		{"get rate limited", http.MethodGet, http.StatusTooManyRequests, 3},
+		{"get not found", http.MethodGet, http.StatusNotFound, 1},
+		{"post server error", http.MethodPost, http.StatusServiceUnavailable, 1},
+		{"post gateway timeout", http.MethodPost, http.StatusGatewayTimeout, 1},
+		{"post rate limited", http.MethodPost, http.StatusTooManyRequests, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &countingTransport{status: tt.status}
+			transport := &RetryTransport{Base: base, Attempts: 2}
+
+			var body io.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader("tx=AAAA")
+			}
+			req, err := http.NewRequest(tt.method, "https://horizon.example/transactions", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if base.requests != tt.expected {
+				t.Errorf("sent %d requests, want %d", base.requests, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTransientTimeouts(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://horizon.example/accounts/G", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://horizon.example/transactions", strings.NewReader("tx=AAAA"))
+	timeout := &timeoutError{}
+
+	if !transient(get, nil, timeout) {
+		t.Error("a GET that timed out isn't retried")
+	}
+	if transient(post, nil, timeout) {
+		t.Error("a POST that timed out is retried")
+	}
+	if transient(get, nil, context.Canceled) {
+		t.Error("a cancelled GET is retried")
+	}
+}
+
+type timeoutError struct{}
+
+func (*timeoutError) Error() string   { return "i/o timeout" }
+func (*timeoutError) Timeout() bool   { return true }
+func (*timeoutError) Temporary() bool { return true }
+
+func TestSetRequestTimeoutReplacesClients(t *testing.T) {
+	defer SetRequestTimeout(DefaultRequestTimeout)
+
+	before := HorizonClient("testnet")
+	SetRequestTimeout(42 * time.Second)
+	after := HorizonClient("testnet")
+
+	if after == before {
+		t.Fatal("SetRequestTimeout kept the old client")
+	}
+	if got := after.HorizonTimeout(); got != 42*time.Second {
+		t.Errorf("new client timeout = %v, want 42s", got)
+	}
+	if got := before.HorizonTimeout(); got == 42*time.Second {
+		t.Error("SetRequestTimeout changed a client already handed out")
+	}
+	if got := HorizonClient("public").HorizonTimeout(); got != 42*time.Second {
+		t.Errorf("public client timeout = %v, want 42s", got)
+	}
+}
@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// What it takes to re-derive an account: its phrase, passphrase and SEP-5 index
+type RecoveryPhrase struct {
+	Mnemonic   string `json:"mnemonic"`
+	Passphrase string `json:"passphrase,omitempty"`
+	Index      uint32 `json:"index"`
+}
+
+// One account in the wallet file
+type Account struct {
+	PublicKey string `json:"public_key"`
+	Balance   string `json:"balance"`
+	Network   string `json:"network"` // "public" or "testnet"
+
+	// Decrypted secret, only ever held in memory
+	SecretKey string `json:"-"`
+
+	// Secret key encrypted with AES-GCM under a key derived from the wallet password
+	EncryptedSecret string `json:"encrypted_secret,omitempty"`
+	Salt            string `json:"salt,omitempty"`
+	Nonce           string `json:"nonce,omitempty"`
+
+	// Phrase the account was derived from, if any. Only held in memory until
+	// the next save encrypts it like the secret key.
+	Recovery          *RecoveryPhrase `json:"-"`
+	EncryptedRecovery string          `json:"encrypted_recovery,omitempty"`
+	RecoveryNonce     string          `json:"recovery_nonce,omitempty"`
+
+	// Plaintext secret written by older versions, encrypted on first unlock
+	LegacySecret string `json:"secret_key,omitempty"`
+}
+
+// Every account the app manages, saved together in one file. All secrets are
+// encrypted under the same wallet password.
+type Store struct {
+	Wallets     []Account `json:"wallets"`
+	ActiveIndex int       `json:"active_index"`
+}
+
+// Returned by LoadStore, wrapped, for a wallet file that can't be decoded
+var ErrCorruptStore = errors.New("wallet file is corrupt")
+
+// Add a to the store, returning its index
+func (s *Store) Add(a Account) (int, error) {
+	if s.Index(a.PublicKey) >= 0 {
+		return 0, fmt.Errorf("account %s is already in the wallet", a.PublicKey)
+	}
+	s.Wallets = append(s.Wallets, a)
+	return len(s.Wallets) - 1, nil
+}
+
+// Remove the account at i; the store always keeps at least one
+func (s *Store) Remove(i int) error {
+	if i < 0 || i >= len(s.Wallets) {
+		return fmt.Errorf("no account at index %d", i)
+	}
+	if len(s.Wallets) == 1 {
+		return fmt.Errorf("cannot remove the only account")
+	}
+	s.Wallets = append(s.Wallets[:i], s.Wallets[i+1:]...)
+	if s.ActiveIndex > i || s.ActiveIndex >= len(s.Wallets) {
+		s.ActiveIndex--
+	}
+	return nil
+}
+
+func (s *Store) SetActive(i int) error {
+	if i < 0 || i >= len(s.Wallets) {
+		return fmt.Errorf("no account at index %d", i)
+	}
+	s.ActiveIndex = i
+	return nil
+}
+
+// The active account. The pointer is only good until the next Add or Remove.
+func (s *Store) Active() *Account {
+	return &s.Wallets[s.ActiveIndex]
+}
+
+func (s *Store) Index(publicKey string) int {
+	for i, a := range s.Wallets {
+		if a.PublicKey == publicKey {
+			return i
+		}
+	}
+	return -1
+}
+
+// Parse a wallet file, accepting the single-account files written by older versions
+func DecodeStore(data []byte) (Store, error) {
+	var s Store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Store{}, err
+	}
+	if s.Wallets == nil {
+		var a Account
+		if err := json.Unmarshal(data, &a); err != nil {
+			return Store{}, err
+		}
+		s = Store{Wallets: []Account{a}}
+	}
+	if len(s.Wallets) == 0 {
+		return Store{}, fmt.Errorf("wallet file has no accounts")
+	}
+	if s.ActiveIndex < 0 || s.ActiveIndex >= len(s.Wallets) {
+		s.ActiveIndex = 0
+	}
+	for i := range s.Wallets {
+		if a := &s.Wallets[i]; a.EncryptedSecret == "" && a.LegacySecret != "" {
+			a.SecretKey = a.LegacySecret
+		}
+	}
+	return s, nil
+}
+
+// Read the wallet file at path. A missing file gives an error matching
+// os.ErrNotExist, and one that can't be decoded an error matching
+// ErrCorruptStore.
+func LoadStore(path string) (Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Store{}, err
+	}
+	s, err := DecodeStore(data)
+	if err != nil {
+		return Store{}, fmt.Errorf("%w: %v", ErrCorruptStore, err)
+	}
+	return s, nil
+}
+
+// Encrypt every secret and recovery phrase held in memory under key, then
+// write the store to path. The plaintext never reaches disk.
+func (s *Store) Save(path string, key []byte) error {
+	for i := range s.Wallets {
+		a := &s.Wallets[i]
+		if a.SecretKey != "" {
+			if key == nil {
+				return fmt.Errorf("wallet password not set")
+			}
+			ciphertext, nonce, err := Seal(key, a.SecretKey)
+			if err != nil {
+				return fmt.Errorf("error encrypting wallet: %v", err)
+			}
+			a.EncryptedSecret, a.Nonce = ciphertext, nonce
+		}
+		if a.Recovery != nil {
+			if key == nil {
+				return fmt.Errorf("wallet password not set")
+			}
+			plain, err := json.Marshal(a.Recovery)
+			if err != nil {
+				return err
+			}
+			ciphertext, nonce, err := Seal(key, string(plain))
+			if err != nil {
+				return fmt.Errorf("error encrypting recovery phrase: %v", err)
+			}
+			a.EncryptedRecovery, a.RecoveryNonce = ciphertext, nonce
+			a.Recovery = nil
+		}
+		a.LegacySecret = ""
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, data)
+}
@@ -0,0 +1,132 @@
+package wallet_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/keypair"
+)
+
+func TestLoadStore(t *testing.T) {
+	kp := keypair.MustRandom()
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantErr   error
+		wantCount int
+	}{
+		{"missing", filepath.Join(dir, "missing.json"), os.ErrNotExist, 0},
+		{"corrupt", write("corrupt.json", "{not json"), wallet.ErrCorruptStore, 0},
+		{"no accounts", write("empty.json", `{"wallets": []}`), wallet.ErrCorruptStore, 0},
+		{"single account from older versions", write("legacy.json", `{"public_key": "`+kp.Address()+`", "network": "testnet"}`), nil, 1},
+		{"store", write("store.json", `{"wallets": [{"public_key": "`+kp.Address()+`"}, {"public_key": "GB"}], "active_index": 9}`), nil, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := wallet.LoadStore(tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadStore() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(s.Wallets) != tt.wantCount {
+				t.Errorf("loaded %d accounts, want %d", len(s.Wallets), tt.wantCount)
+			}
+			if tt.wantCount > 0 && s.ActiveIndex != 0 {
+				t.Errorf("ActiveIndex = %d, want 0", s.ActiveIndex)
+			}
+		})
+	}
+}
+
+func TestStoreSaveEncryptsSecrets(t *testing.T) {
+	kp := keypair.MustRandom()
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	key := bytes.Repeat([]byte{7}, wallet.KeyLen)
+	path := filepath.Join(t.TempDir(), "wallet.json")
+
+	s := wallet.Store{Wallets: []wallet.Account{{
+		PublicKey:    kp.Address(),
+		Network:      "testnet",
+		SecretKey:    kp.Seed(),
+		LegacySecret: kp.Seed(),
+		Recovery:     &wallet.RecoveryPhrase{Mnemonic: mnemonic},
+	}}}
+	if err := s.Save(path, key); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{kp.Seed(), "abandon"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("wallet file contains %q in plaintext", secret)
+		}
+	}
+
+	loaded, err := wallet.LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := loaded.Active()
+	if a.SecretKey != "" {
+		t.Errorf("loaded a plaintext secret %q", a.SecretKey)
+	}
+	if secret, err := wallet.Open(key, a.EncryptedSecret, a.Nonce); err != nil || secret != kp.Seed() {
+		t.Errorf("decrypted secret = %q, %v", secret, err)
+	}
+	if phrase, err := wallet.Open(key, a.EncryptedRecovery, a.RecoveryNonce); err != nil || !bytes.Contains([]byte(phrase), []byte(mnemonic)) {
+		t.Errorf("decrypted recovery phrase = %q, %v", phrase, err)
+	}
+
+	locked := wallet.Store{Wallets: []wallet.Account{{PublicKey: kp.Address(), SecretKey: kp.Seed()}}}
+	if err := locked.Save(filepath.Join(t.TempDir(), "wallet.json"), nil); err == nil {
+		t.Error("Save wrote a secret without a wallet key")
+	}
+}
+
+func TestStoreAddRemove(t *testing.T) {
+	a, b, c := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	var s wallet.Store
+	for _, kp := range []*keypair.Full{a, b, c} {
+		if _, err := s.Add(wallet.Account{PublicKey: kp.Address()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Add(wallet.Account{PublicKey: b.Address()}); err == nil {
+		t.Error("Add accepted a duplicate account")
+	}
+
+	if err := s.SetActive(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Active().PublicKey; got != c.Address() {
+		t.Errorf("active after removing an earlier account = %s, want %s", got, c.Address())
+	}
+	if s.Index(a.Address()) != -1 {
+		t.Error("removed account still indexed")
+	}
+	if err := s.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove(0); err == nil {
+		t.Error("Remove took the only account")
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+)
+
+const minPasswordLen = 8
+
+// Key derived from the wallet password, kept in memory after unlocking so the
+// wallet can be re-encrypted on save without asking again
+var walletKey []byte
+
+// Derive the key for password and decrypt the wallet's secret with it
+func unlockSecret(w Wallet, password string) (string, []byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(w.Salt)
+	if err != nil {
+		return "", nil, fmt.Errorf("corrupt wallet file: %v", err)
+	}
+	key, err := core.DeriveKey(password, salt)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := core.Open(key, w.EncryptedSecret, w.Nonce)
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, key, nil
+}
+
+// Choose a new password: a fresh salt and the key derived from both
+func newWalletKey(password string) (string, []byte, error) {
+	if len(password) < minPasswordLen {
+		return "", nil, fmt.Errorf("password must be at least %d characters", minPasswordLen)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", nil, err
+	}
+	key, err := core.DeriveKey(password, salt)
+	if err != nil {
+		return "", nil, err
+	}
+	return base64.StdEncoding.EncodeToString(salt), key, nil
+}
+
+func checkWalletPassword(password string) bool {
+	_, _, err := unlockSecret(*wallet, password)
+	return err == nil
+}
+
+// Get the active account's decrypted secret into memory before anything else
+// runs: ask for the password of an encrypted wallet, or have the user choose
+// one for a new or plaintext wallet, which is then encrypted and saved
+func unlockWallet(window fyne.Window, onReady func()) {
+	if wallet.EncryptedSecret != "" {
+		showUnlockDialog(window, onReady)
+		return
+	}
+	showSetPasswordDialog(window, onReady)
+}
+
+func showUnlockDialog(window fyne.Window, onReady func()) {
+	passwordEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		widget.NewFormItem("Password", passwordEntry),
+	}
+	form := dialog.NewForm("Unlock Wallet", "Unlock", "Quit", items, func(submit bool) {
+		if !submit {
+			fyne.CurrentApp().Quit()
+			return
+		}
+		secret, key, err := unlockSecret(*wallet, passwordEntry.Text)
+		if err != nil {
+			errDialog := dialog.NewError(err, window)
+			errDialog.SetOnClosed(func() { showUnlockDialog(window, onReady) })
+			errDialog.Show()
+			return
+		}
+		// The right password but the wrong key: no point asking again
+		if err := checkSecretMatches(wallet.PublicKey, secret); err != nil {
+			errDialog := dialog.NewError(err, window)
+			errDialog.SetOnClosed(func() { fyne.CurrentApp().Quit() })
+			errDialog.Show()
+			return
+		}
+		wallet.SecretKey = secret
+		walletKey = key
+		onReady()
+	}, window)
+	passwordEntry.OnSubmitted = func(string) { form.Submit() }
+	form.Show()
+	window.Canvas().Focus(passwordEntry)
+}
+
+func showSetPasswordDialog(window fyne.Window, onReady func()) {
+	passwordEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+	info := widget.NewLabel("Choose a password to encrypt your secret key on this device. It cannot be recovered if lost.")
+	info.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", info),
+		widget.NewFormItem("Password", passwordEntry),
+		widget.NewFormItem("Confirm", confirmEntry),
+	}
+	form := dialog.NewForm("Set Wallet Password", "Save", "Quit", items, func(submit bool) {
+		if !submit {
+			fyne.CurrentApp().Quit()
+			return
+		}
+		retry := func(err error) {
+			errDialog := dialog.NewError(err, window)
+			errDialog.SetOnClosed(func() { showSetPasswordDialog(window, onReady) })
+			errDialog.Show()
+		}
+		if passwordEntry.Text != confirmEntry.Text {
+			retry(fmt.Errorf("passwords do not match"))
+			return
+		}
+		salt, key, err := newWalletKey(passwordEntry.Text)
+		if err != nil {
+			retry(err)
+			return
+		}
+		// One password covers every account
+		for i := range store.Wallets {
+			store.Wallets[i].Salt = salt
+		}
+		walletKey = key
+		if err := saveWallet(); err != nil {
+			retry(fmt.Errorf("error saving wallet: %v", err))
+			return
+		}
+		onReady()
+	}, window)
+	form.Resize(fyne.NewSize(scaled(320), form.MinSize().Height))
+	form.Show()
+}
+
+// Placeholder shown until the wallet is unlocked
+func lockedContent() fyne.CanvasObject {
+	return container.NewCenter(widget.NewLabel("Wallet locked"))
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	core "github.com/just-nibble/fyne-test/wallet"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+)
+
+// Every account the app manages, saved together in walletFile
+type WalletStore = core.Store
+
+var store WalletStore
+
+// Check every account in a decoded store, repairing what can be repaired and
+// describing each repair. Accounts with an unusable public key are dropped
+// while others remain; an unknown network falls back to testnet. A secret that
+// doesn't belong to its account is never repaired: that wallet is refused.
+func repairWalletStore(s *WalletStore) ([]string, error) {
+	var warnings []string
+	kept := s.Wallets[:0]
+	for i, w := range s.Wallets {
+		if !strkey.IsValidEd25519PublicKey(w.PublicKey) {
+			warnings = append(warnings, fmt.Sprintf("Account %d had an invalid public key %q and was skipped.", i+1, w.PublicKey))
+			continue
+		}
+		if w.SecretKey != "" {
+			if err := checkSecretMatches(w.PublicKey, w.SecretKey); err != nil {
+				return nil, err
+			}
+		}
+		switch w.Network {
+		case "public", "testnet":
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s had an unknown network %q and was switched to testnet.", shortAddress(w.PublicKey), w.Network))
+			w.Network = "testnet"
+		}
+		kept = append(kept, w)
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("wallet file has no usable accounts")
+	}
+	if len(kept) < len(s.Wallets) {
+		s.ActiveIndex = 0
+	}
+	s.Wallets = kept
+	return warnings, nil
+}
+
+// Make sure a secret seed is the one for publicKey, so a corrupt or
+// hand-edited wallet can't sign for a different account than it shows
+func checkSecretMatches(publicKey, secret string) error {
+	kp, err := keypair.ParseFull(secret)
+	if err != nil {
+		return fmt.Errorf("the secret key stored for %s is not valid; restore the wallet from a backup", shortAddress(publicKey))
+	}
+	if kp.Address() != publicKey {
+		return fmt.Errorf("the secret key stored for %s belongs to %s; the wallet file has been altered, restore it from a backup",
+			shortAddress(publicKey), shortAddress(kp.Address()))
+	}
+	return nil
+}
+
+// Make the wallet at i active, decrypting its secret with the key already
+// unlocked, and point the client at its network
+func activateWallet(i int) error {
+	walletMu.Lock()
+	if i < 0 || i >= len(store.Wallets) {
+		walletMu.Unlock()
+		return fmt.Errorf("no account at index %d", i)
+	}
+	if w := &store.Wallets[i]; w.SecretKey == "" && w.EncryptedSecret != "" {
+		secret, err := core.Open(walletKey, w.EncryptedSecret, w.Nonce)
+		if err != nil {
+			walletMu.Unlock()
+			return fmt.Errorf("error decrypting account: %v", err)
+		}
+		if err := checkSecretMatches(w.PublicKey, secret); err != nil {
+			walletMu.Unlock()
+			return err
+		}
+		w.SecretKey = secret
+	}
+	store.SetActive(i)
+	wallet = store.Active()
+	walletMu.Unlock()
+
+	initializeClient(wallet.Network)
+	resetBalanceState()
+	return saveWallet()
+}
+
+// Add kp as a new account on the current network and switch to it, or just
+// switch if it is already in the store. phrase is kept, encrypted, when the
+// account was derived from one.
+func addWalletAccount(kp *keypair.Full, phrase *recoveryPhrase) error {
+	walletMu.Lock()
+	i := store.Index(kp.Address())
+	if i < 0 {
+		var err error
+		i, err = store.Add(Wallet{
+			PublicKey: kp.Address(),
+			SecretKey: kp.Seed(),
+			Network:   wallet.Network,
+			Balance:   "0",
+			Salt:      wallet.Salt,
+			Recovery:  phrase,
+		})
+		if err != nil {
+			walletMu.Unlock()
+			return err
+		}
+		// Add may have moved the slice
+		wallet = store.Active()
+	}
+	walletMu.Unlock()
+	return activateWallet(i)
+}
+
+// Selector labels for the accounts in the store, in order
+func walletOptions() []string {
+	options := make([]string, len(store.Wallets))
+	for i, w := range store.Wallets {
+		options[i] = fmt.Sprintf("%d. %s", i+1, shortAddress(w.PublicKey))
+	}
+	return options
+}
+
+func switchWallet(i int) {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+	if err := activateWallet(i); err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	window.SetContent(createMainUI())
+}
+
+func showNewAccountDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	message := fmt.Sprintf("Create a new account on %s with its own recovery phrase and switch to it?", wallet.Network)
+	dialog.ShowConfirm("New Account", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		phrase, kp, err := newRecoveryPhrase()
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if err := addWalletAccount(kp, &phrase); err != nil {
+			dialog.ShowError(fmt.Errorf("error adding account: %v", err), window)
+			return
+		}
+		finish := func(fundErr error) {
+			window.SetContent(createMainUI())
+			showRecoveryPhrase(phrase)
+			if fundErr != nil {
+				dialog.ShowError(fmt.Errorf("the account was created but could not be funded: %v", fundErr), window)
+			}
+		}
+		if wallet.Network != "testnet" {
+			finish(nil)
+			return
+		}
+
+		var fundErr error
+		withProgress("Funding account with Friendbot...", func() {
+			fundErr = fundAccount(kp.Address())
+		}, func() {
+			finish(fundErr)
+		})
+	}, window)
+}
+
+func showRemoveAccountDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	if len(store.Wallets) == 1 {
+		dialog.ShowInformation("Remove Account", "This is the only account in the wallet.", window)
+		return
+	}
+	message := fmt.Sprintf("Remove %s from this wallet?\n\nIts secret key is deleted from this device. Make sure it is backed up.", wallet.PublicKey)
+	dialog.ShowConfirm("Remove Account", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		walletMu.Lock()
+		err := store.Remove(store.ActiveIndex)
+		walletMu.Unlock()
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		switchWallet(store.ActiveIndex)
+	}, window)
+}
+
+// Add an account the user already owns from its S... secret seed or its
+// recovery phrase (the first account derived from it)
+func showImportWalletDialog() {
+	window := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	seedEntry := widget.NewMultiLineEntry()
+	seedEntry.SetPlaceHolder("S... secret seed or recovery phrase")
+	seedEntry.Wrapping = fyne.TextWrapWord
+	items := []*widget.FormItem{
+		widget.NewFormItem("Seed or Phrase", seedEntry),
+	}
+
+	dialog.ShowForm("Import Wallet", "Import", "Cancel", items, func(submit bool) {
+		if !submit {
+			return
+		}
+		var (
+			kp     *keypair.Full
+			phrase *recoveryPhrase
+			err    error
+		)
+		text := strings.TrimSpace(seedEntry.Text)
+		if len(strings.Fields(text)) > 1 {
+			var parsed recoveryPhrase
+			parsed, kp, err = parseRecoveryPhrase(text)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			phrase = &parsed
+		} else if kp, err = keypair.ParseFull(text); err != nil {
+			dialog.ShowError(fmt.Errorf("invalid secret seed: it should start with S and be 56 characters long"), window)
+			return
+		}
+		if store.Index(kp.Address()) >= 0 {
+			dialog.ShowInformation("Import Wallet", fmt.Sprintf("%s is already in this wallet.", kp.Address()), window)
+			return
+		}
+
+		var lookupErr error
+		withProgress("Checking account...", func() {
+			_, lookupErr = client.AccountDetail(horizonclient.AccountRequest{AccountID: kp.Address()})
+		}, func() {
+			message := fmt.Sprintf("Add %s to this wallet and switch to it?", kp.Address())
+			switch {
+			case isNotFound(lookupErr):
+				message += fmt.Sprintf("\n\nThis account is not funded on %s yet. It needs at least %s XLM before it can be used.", wallet.Network, stroopsToXLM(2*baseReserve))
+			case lookupErr != nil:
+				message += fmt.Sprintf("\n\nIts balance could not be checked: %v", lookupErr)
+			}
+			dialog.ShowConfirm("Import Wallet", message, func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := addWalletAccount(kp, phrase); err != nil {
+					dialog.ShowError(fmt.Errorf("error importing account: %v", err), window)
+					return
+				}
+				window.SetContent(createMainUI())
+			}, window)
+		})
+	}, window)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+)
+
+func TestCheckSecretMatches(t *testing.T) {
+	kp := keypair.MustRandom()
+	other := keypair.MustRandom()
+
+	tests := []struct {
+		name    string
+		secret  string
+		errText string
+	}{
+		{"matching", kp.Seed(), ""},
+		{"other account", other.Seed(), "belongs to"},
+		{"not a seed", "SNOTASEED", "not valid"},
+		{"public key", kp.Address(), "not valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSecretMatches(kp.Address(), tt.secret)
+			switch {
+			case tt.errText == "" && err != nil:
+				t.Errorf("checkSecretMatches() = %v", err)
+			case tt.errText != "" && (err == nil || !strings.Contains(err.Error(), tt.errText)):
+				t.Errorf("checkSecretMatches() = %v, want an error mentioning %q", err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestRepairWalletStore(t *testing.T) {
+	a, b := keypair.MustRandom(), keypair.MustRandom()
+
+	tests := []struct {
+		name         string
+		store        WalletStore
+		wantErr      bool
+		warnings     int
+		wantAccounts []string
+		wantNetworks []string
+		wantActive   int
+	}{
+		{
+			name:         "healthy",
+			store:        WalletStore{Wallets: []Wallet{{PublicKey: a.Address(), Network: "public"}, {PublicKey: b.Address(), Network: "testnet"}}, ActiveIndex: 1},
+			wantAccounts: []string{a.Address(), b.Address()},
+			wantNetworks: []string{"public", "testnet"},
+			wantActive:   1,
+		},
+		{
+			name:         "unknown network",
+			store:        WalletStore{Wallets: []Wallet{{PublicKey: a.Address(), Network: "futurenet"}}},
+			warnings:     1,
+			wantAccounts: []string{a.Address()},
+			wantNetworks: []string{"testnet"},
+		},
+		{
+			name:         "invalid key skipped",
+			store:        WalletStore{Wallets: []Wallet{{PublicKey: "GBROKEN", Network: "public"}, {PublicKey: b.Address(), Network: "public"}}, ActiveIndex: 1},
+			warnings:     1,
+			wantAccounts: []string{b.Address()},
+			wantNetworks: []string{"public"},
+			wantActive:   0,
+		},
+		{
+			name:         "matching secret",
+			store:        WalletStore{Wallets: []Wallet{{PublicKey: a.Address(), SecretKey: a.Seed(), Network: "testnet"}}},
+			wantAccounts: []string{a.Address()},
+			wantNetworks: []string{"testnet"},
+		},
+		{
+			name:    "mismatched secret refused",
+			store:   WalletStore{Wallets: []Wallet{{PublicKey: a.Address(), SecretKey: b.Seed(), Network: "testnet"}}},
+			wantErr: true,
+		},
+		{
+			name:    "nothing usable",
+			store:   WalletStore{Wallets: []Wallet{{PublicKey: "GBROKEN"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.store
+			warnings, err := repairWalletStore(&s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("repairWalletStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("warnings = %q, want %d", warnings, tt.warnings)
+			}
+			if len(s.Wallets) != len(tt.wantAccounts) {
+				t.Fatalf("kept %d accounts, want %d", len(s.Wallets), len(tt.wantAccounts))
+			}
+			for i, w := range s.Wallets {
+				if w.PublicKey != tt.wantAccounts[i] || w.Network != tt.wantNetworks[i] {
+					t.Errorf("account %d = %s on %s, want %s on %s", i, w.PublicKey, w.Network, tt.wantAccounts[i], tt.wantNetworks[i])
+				}
+			}
+			if s.ActiveIndex != tt.wantActive {
+				t.Errorf("ActiveIndex = %d, want %d", s.ActiveIndex, tt.wantActive)
+			}
+		})
+	}
+}